@@ -0,0 +1,263 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package account
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DefaultBillingItemMask is the default object mask for billing items
+const DefaultBillingItemMask = "id,description,categoryCode,hostName,domainName,notes,createDate," +
+	"recurringFee,hourlyFlag,cancellationDate,allowCancellationFlag,pendingCancellationFlag," +
+	"location[name],orderItem[id,order[id]]"
+
+// Category codes of the top level billing items of each kind of resource
+var billingCategories = map[string][]string{
+	ResourceVirtualGuests: {"guest_core"},
+	ResourceHardware:      {"server"},
+	ResourceVlans:         {"network_vlan"},
+	ResourceSubnets: {
+		"sov_sec_ip_addresses_priv", "sov_sec_ip_addresses_pub", "static_sec_ip_addresses",
+		"pri_ipv6_addresses", "static_ipv6_addresses", "global_ipv4", "global_ipv6",
+	},
+	ResourceVolumes: {
+		"storage_as_a_service", "storage_service_enterprise",
+		"performance_storage_iscsi", "performance_storage_nfs",
+	},
+}
+
+// Query holds the criteria used to search the top level billing items of the
+// account. Empty fields are ignored. Description matches the items whose
+// description contains it, Category is the category code of the items (e.g.
+// "server", "guest_core"), ResourceType is the kind of resource billed (e.g.
+// ResourceVirtualGuests, ResourceVolumes) and OrderId the id of the order
+// which created the items. When both Category and ResourceType are set, no
+// item matches a category which the resource type does not bill.
+type Query struct {
+	Description  string
+	Category     string
+	ResourceType string
+	OrderId      int
+}
+
+// BillingCancellation is the result of the checks run before canceling a
+// billing item. Children lists the active billing items attached to it, which
+// are canceled along with it.
+type BillingCancellation struct {
+	BillingItemId int
+	Description   string
+	CategoryCode  string
+	Cancelable    bool
+	PendingCancel bool
+	Children      []string
+}
+
+// Blocked reports whether any of the checks prevents the cancellation of the
+// billing item
+func (c BillingCancellation) Blocked() bool {
+	return !c.Cancelable || c.PendingCancel
+}
+
+// Reasons returns a description of each check preventing the cancellation
+func (c BillingCancellation) Reasons() []string {
+	reasons := []string{}
+
+	if !c.Cancelable {
+		reasons = append(reasons, "cancellation not allowed")
+	}
+
+	if c.PendingCancel {
+		reasons = append(reasons, "cancellation already pending")
+	}
+
+	return reasons
+}
+
+// FindBillingItems returns the top level billing items of the account
+// matching query, which are the ones to cancel to cancel a resource. An object
+// mask can be provided as an optional argument, and DefaultBillingItemMask is
+// used otherwise.
+func FindBillingItems(sess *session.Session, query Query, mask ...string) ([]datatypes.Billing_Item, error) {
+	objectMask := DefaultBillingItemMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	filters := filter.New()
+
+	if query.Description != "" {
+		filters = append(filters, filter.Path("allTopLevelBillingItems.description").Like(query.Description))
+	}
+
+	// Category and ResourceType both filter on the category code, so they are
+	// combined into a single set of codes
+	var categories []string
+
+	if query.ResourceType != "" {
+		var ok bool
+		categories, ok = billingCategories[query.ResourceType]
+		if !ok {
+			return nil, fmt.Errorf("Unknown resource type %s", query.ResourceType)
+		}
+	}
+
+	if query.Category != "" {
+		if categories != nil && !contains(categories, query.Category) {
+			return []datatypes.Billing_Item{}, nil
+		}
+
+		categories = []string{query.Category}
+	}
+
+	if len(categories) == 1 {
+		filters = append(filters, filter.Path("allTopLevelBillingItems.categoryCode").Eq(categories[0]))
+	} else if len(categories) > 1 {
+		codes := make([]interface{}, 0, len(categories))
+		for _, category := range categories {
+			codes = append(codes, category)
+		}
+
+		filters = append(filters, filter.Path("allTopLevelBillingItems.categoryCode").In(codes...))
+	}
+
+	if query.OrderId != 0 {
+		filters = append(filters, filter.Path("allTopLevelBillingItems.orderItem.order.id").Eq(query.OrderId))
+	}
+
+	return services.GetAccountService(sess).
+		Mask(objectMask).
+		Filter(filters.Build()).
+		GetAllTopLevelBillingItems()
+}
+
+// CancelBillingItem cancels the billing item with the provided id, along with
+// its associated billing items, immediately when immediate is set, or at the
+// end of the billing cycle otherwise. The cancellation is only issued when
+// none of the checks prevents it, and never when dryRun is set, in which case
+// the checks are reported without returning an error. The result of the
+// checks is returned in all cases.
+func CancelBillingItem(
+	sess *session.Session,
+	billingItemId int,
+	immediate bool,
+	reason string,
+	dryRun bool,
+) (BillingCancellation, error) {
+
+	cancellations, err := CancelBillingItems(sess, []int{billingItemId}, immediate, reason, dryRun)
+	if len(cancellations) == 0 {
+		return BillingCancellation{}, err
+	}
+
+	return cancellations[0], err
+}
+
+// CancelBillingItems cancels the billing items with the provided ids as
+// CancelBillingItem does. All the billing items are checked before any of
+// them is canceled, and none is canceled when the checks prevent the
+// cancellation of any of them.
+func CancelBillingItems(
+	sess *session.Session,
+	billingItemIds []int,
+	immediate bool,
+	reason string,
+	dryRun bool,
+) ([]BillingCancellation, error) {
+
+	cancellations := make([]BillingCancellation, 0, len(billingItemIds))
+	blocked := []string{}
+
+	for _, id := range billingItemIds {
+		cancellation, err := checkBillingItemCancellation(sess, id)
+		if err != nil {
+			return cancellations, err
+		}
+
+		cancellations = append(cancellations, cancellation)
+
+		if cancellation.Blocked() {
+			blocked = append(blocked, fmt.Sprintf("%d (%s)", id, strings.Join(cancellation.Reasons(), ", ")))
+		}
+	}
+
+	if dryRun {
+		return cancellations, nil
+	}
+
+	if len(blocked) > 0 {
+		return cancellations, fmt.Errorf("Cannot cancel billing items %s", strings.Join(blocked, "; "))
+	}
+
+	for _, cancellation := range cancellations {
+		ok, err := services.GetBillingItemService(sess).
+			Id(cancellation.BillingItemId).
+			CancelItem(sl.Bool(immediate), sl.Bool(true), sl.String(reason), nil)
+		if err != nil {
+			return cancellations, fmt.Errorf("Error canceling billing item %d: %s", cancellation.BillingItemId, err)
+		}
+
+		if !ok {
+			return cancellations, fmt.Errorf("Cancellation of billing item %d was not accepted", cancellation.BillingItemId)
+		}
+	}
+
+	return cancellations, nil
+}
+
+func checkBillingItemCancellation(sess *session.Session, billingItemId int) (BillingCancellation, error) {
+	item, err := services.GetBillingItemService(sess).
+		Id(billingItemId).
+		Mask("id,description,categoryCode,allowCancellationFlag,pendingCancellationFlag," +
+			"activeChildren[id,description,categoryCode]").
+		GetObject()
+	if err != nil {
+		return BillingCancellation{}, err
+	}
+
+	cancellation := BillingCancellation{
+		BillingItemId: billingItemId,
+		Description:   sl.Get(item.Description, "").(string),
+		CategoryCode:  sl.Get(item.CategoryCode, "").(string),
+		Cancelable:    sl.Get(item.AllowCancellationFlag, 0).(int) == 1,
+		PendingCancel: sl.Get(item.PendingCancellationFlag, false).(bool),
+		Children:      []string{},
+	}
+
+	for _, child := range item.ActiveChildren {
+		cancellation.Children = append(cancellation.Children, fmt.Sprintf(
+			"%s (%s)", sl.Get(child.Description, ""), sl.Get(child.CategoryCode, "")))
+	}
+
+	return cancellation, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,135 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package account
+
+import (
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// InvoiceEstimate is the estimated amount of the next invoice of the account,
+// along with the breakdown of its recurring fees.
+//
+// ByResourceType is keyed by kind of resource (e.g. ResourceVirtualGuests),
+// or by category code for the billing items of other kinds. ByDatacenter is
+// keyed by datacenter name, the billing items without a datacenter being
+// grouped under an empty name. ByTag is keyed by the tags of the virtual
+// guests and hardware, the fees of a device being counted for each of its
+// tags, so the amounts of ByTag do not add up to the total.
+type InvoiceEstimate struct {
+	Total          float64
+	Recurring      float64
+	ByResourceType map[string]float64
+	ByDatacenter   map[string]float64
+	ByTag          map[string]float64
+}
+
+// EstimateNextInvoice returns the estimated amount of the next invoice of the
+// account, and the breakdown of its recurring fees by kind of resource,
+// datacenter and tag
+func EstimateNextInvoice(sess *session.Session) (InvoiceEstimate, error) {
+	service := services.GetAccountService(sess)
+
+	total, err := service.GetNextInvoiceTotalAmount()
+	if err != nil {
+		return InvoiceEstimate{}, err
+	}
+
+	items, err := service.
+		Mask("id,categoryCode,nextInvoiceTotalRecurringAmount,location[name]").
+		GetNextInvoiceTopLevelBillingItems()
+	if err != nil {
+		return InvoiceEstimate{}, err
+	}
+
+	tags, err := billingItemTags(sess)
+	if err != nil {
+		return InvoiceEstimate{}, err
+	}
+
+	kinds := map[string]string{}
+	for kind, categories := range billingCategories {
+		for _, category := range categories {
+			kinds[category] = kind
+		}
+	}
+
+	estimate := InvoiceEstimate{
+		Total:          float64(total),
+		ByResourceType: map[string]float64{},
+		ByDatacenter:   map[string]float64{},
+		ByTag:          map[string]float64{},
+	}
+
+	for _, item := range items {
+		amount := float64(sl.Get(item.NextInvoiceTotalRecurringAmount, datatypes.Float64(0)).(datatypes.Float64))
+
+		category := sl.Get(item.CategoryCode, "").(string)
+		if kind, ok := kinds[category]; ok {
+			category = kind
+		}
+
+		estimate.Recurring += amount
+		estimate.ByResourceType[category] += amount
+		estimate.ByDatacenter[sl.Grab(item, "Location.Name", "").(string)] += amount
+
+		for _, tag := range tags[sl.Get(item.Id, 0).(int)] {
+			estimate.ByTag[tag] += amount
+		}
+	}
+
+	return estimate, nil
+}
+
+// billingItemTags returns the tags of the virtual guests and hardware of the
+// account, keyed by the id of their billing item
+func billingItemTags(sess *session.Session) (map[int][]string, error) {
+	const mask = "id,billingItem[id],tagReferences[tag[name]]"
+
+	service := services.GetAccountService(sess).Mask(mask)
+
+	guests, err := service.GetVirtualGuests()
+	if err != nil {
+		return nil, err
+	}
+
+	hardware, err := service.GetHardware()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[int][]string{}
+	add := func(billingItemId int, refs []datatypes.Tag_Reference) {
+		for _, ref := range refs {
+			if name := sl.Grab(ref, "Tag.Name", "").(string); name != "" {
+				tags[billingItemId] = append(tags[billingItemId], name)
+			}
+		}
+	}
+
+	for _, guest := range guests {
+		add(sl.Grab(guest, "BillingItem.Id", 0).(int), guest.TagReferences)
+	}
+
+	for _, hw := range hardware {
+		add(sl.Grab(hw, "BillingItem.Id", 0).(int), hw.TagReferences)
+	}
+
+	return tags, nil
+}
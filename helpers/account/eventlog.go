@@ -0,0 +1,129 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package account
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Number of entries requested at once by QueryEventLog
+const eventLogPageSize = 100
+
+// Format of the dates of the event log filters, which, unlike the other
+// object filters, expect an ISO 8601 date with microseconds and an offset
+const eventLogDateFormat = "2006-01-02T15:04:05.000000-07:00"
+
+// EventQuery holds the criteria used to search the event log of the account.
+// Empty fields are ignored. ObjectName is the kind of object the events are
+// about (e.g. "CCI", "Bare Metal Instance"), and EventName the name of the
+// events (e.g. "Power On").
+type EventQuery struct {
+	ObjectName string
+	EventName  string
+	UserId     int
+	DateRange  DateRange
+}
+
+// AuditEntry is an entry of the event log. MetaData holds the details of the
+// event, as provided by the API.
+type AuditEntry struct {
+	Date       time.Time
+	EventName  string
+	ObjectName string
+	ObjectId   int
+	Label      string
+	UserId     int
+	Username   string
+	UserType   string
+	IpAddress  string
+	TraceId    string
+	MetaData   string
+}
+
+// QueryEventLog returns the entries of the event log of the account matching
+// query, newest first. The entries are requested in pages until all of them
+// are returned.
+func QueryEventLog(sess *session.Session, query EventQuery) ([]AuditEntry, error) {
+	filters := filter.New()
+
+	if query.ObjectName != "" {
+		filters = append(filters, filter.Path("objectName").Eq(query.ObjectName))
+	}
+
+	if query.EventName != "" {
+		filters = append(filters, filter.Path("eventName").Eq(query.EventName))
+	}
+
+	if query.UserId != 0 {
+		filters = append(filters, filter.Path("userId").Eq(query.UserId))
+	}
+
+	start := query.DateRange.Start.Format(eventLogDateFormat)
+	end := query.DateRange.End.Format(eventLogDateFormat)
+
+	switch {
+	case !query.DateRange.Start.IsZero() && !query.DateRange.End.IsZero():
+		filters = append(filters, filter.Path("eventCreateDate").DateBetween(start, end))
+	case !query.DateRange.Start.IsZero():
+		filters = append(filters, filter.Path("eventCreateDate").DateAfter(start))
+	case !query.DateRange.End.IsZero():
+		filters = append(filters, filter.Path("eventCreateDate").DateBefore(end))
+	}
+
+	service := services.GetEventLogService(sess).Filter(filters.Build())
+
+	entries := []AuditEntry{}
+	for offset := 0; ; offset += eventLogPageSize {
+		events, err := service.Limit(eventLogPageSize).Offset(offset).GetAllObjects()
+		if err != nil {
+			return nil, fmt.Errorf("Error querying the event log: %s", err)
+		}
+
+		for _, event := range events {
+			entry := AuditEntry{
+				EventName:  sl.Get(event.EventName, "").(string),
+				ObjectName: sl.Get(event.ObjectName, "").(string),
+				ObjectId:   sl.Get(event.ObjectId, 0).(int),
+				Label:      sl.Get(event.Label, "").(string),
+				UserId:     sl.Get(event.UserId, 0).(int),
+				Username:   sl.Get(event.Username, "").(string),
+				UserType:   sl.Get(event.UserType, "").(string),
+				IpAddress:  sl.Get(event.IpAddress, "").(string),
+				TraceId:    sl.Get(event.TraceId, "").(string),
+				MetaData:   sl.Get(event.MetaData, "").(string),
+			}
+
+			if event.EventCreateDate != nil {
+				entry.Date = event.EventCreateDate.Time
+			}
+
+			entries = append(entries, entry)
+		}
+
+		if len(events) < eventLogPageSize {
+			break
+		}
+	}
+
+	return entries, nil
+}
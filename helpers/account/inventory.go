@@ -0,0 +1,159 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package account
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/helpers/image"
+	"github.com/softlayer/softlayer-go/helpers/loadbalancer"
+	"github.com/softlayer/softlayer-go/helpers/network"
+	"github.com/softlayer/softlayer-go/helpers/storage"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+)
+
+// Kinds of resources gathered by InventoryAccount
+const (
+	ResourceVirtualGuests = "virtual_guests"
+	ResourceHardware      = "hardware"
+	ResourceVlans         = "vlans"
+	ResourceSubnets       = "subnets"
+	ResourceVolumes       = "volumes"
+	ResourceImages        = "images"
+	ResourceLoadBalancers = "load_balancers"
+)
+
+const virtualGuestInventoryMask = "id,globalIdentifier,hostname,domain,fullyQualifiedDomainName," +
+	"maxCpu,maxMemory,hourlyBillingFlag,createDate,primaryIpAddress,primaryBackendIpAddress," +
+	"datacenter[name],status[keyName],powerState[keyName],operatingSystemReferenceCode,billingItem[id]"
+
+const hardwareInventoryMask = "id,globalIdentifier,hostname,domain,fullyQualifiedDomainName," +
+	"processorPhysicalCoreAmount,memoryCapacity,provisionDate,primaryIpAddress,primaryBackendIpAddress," +
+	"datacenter[name],hardwareStatus[status],billingItem[id]"
+
+const subnetInventoryMask = "id,networkIdentifier,cidr,subnetType,addressSpace,gateway,note," +
+	"networkVlan[id,vlanNumber],datacenter[name],billingItem[id]"
+
+// InventoryOptions restricts the resources gathered by InventoryAccount to
+// the provided kinds (e.g. ResourceVirtualGuests, ResourceHardware). All the
+// kinds are gathered when Kinds is empty.
+type InventoryOptions struct {
+	Kinds []string
+}
+
+// Inventory is a snapshot of the resources of an account. The resources of
+// the kinds which were not gathered are left empty.
+type Inventory struct {
+	Taken         time.Time
+	VirtualGuests []datatypes.Virtual_Guest
+	Hardware      []datatypes.Hardware
+	Vlans         []datatypes.Network_Vlan
+	Subnets       []datatypes.Network_Subnet
+	Volumes       []datatypes.Network_Storage
+	Images        []datatypes.Virtual_Guest_Block_Device_Template_Group
+	LoadBalancers []datatypes.Network_LBaaS_LoadBalancer
+}
+
+// InventoryAccount gathers the resources of the account, the kinds of which
+// are listed concurrently, and returns them in a single snapshot. An error is
+// returned when any kind of resources cannot be listed.
+func InventoryAccount(sess *session.Session, opts InventoryOptions) (Inventory, error) {
+	inventory := Inventory{Taken: time.Now()}
+
+	listers := map[string]func() error{
+		ResourceVirtualGuests: func() (err error) {
+			inventory.VirtualGuests, err = services.GetAccountService(sess).Mask(virtualGuestInventoryMask).GetVirtualGuests()
+			return
+		},
+		ResourceHardware: func() (err error) {
+			inventory.Hardware, err = services.GetAccountService(sess).Mask(hardwareInventoryMask).GetHardware()
+			return
+		},
+		ResourceVlans: func() (err error) {
+			inventory.Vlans, err = network.FindVlans(sess, network.VlanQuery{})
+			return
+		},
+		ResourceSubnets: func() (err error) {
+			inventory.Subnets, err = services.GetAccountService(sess).Mask(subnetInventoryMask).GetSubnets()
+			return
+		},
+		ResourceVolumes: func() (err error) {
+			inventory.Volumes, err = storage.FindVolumes(sess, storage.Query{})
+			return
+		},
+		ResourceImages: func() (err error) {
+			inventory.Images, err = image.FindImages(sess, image.Query{})
+			return
+		},
+		ResourceLoadBalancers: func() (err error) {
+			inventory.LoadBalancers, err = services.GetNetworkLBaaSLoadBalancerService(sess).
+				Mask(loadbalancer.LoadBalancerMask).
+				GetAllObjects()
+			return
+		},
+	}
+
+	kinds := []string{}
+	seen := map[string]bool{}
+	for _, kind := range opts.Kinds {
+		if _, ok := listers[kind]; !ok {
+			return Inventory{}, fmt.Errorf("Unknown resource kind %s", kind)
+		}
+
+		if !seen[kind] {
+			kinds = append(kinds, kind)
+			seen[kind] = true
+		}
+	}
+
+	if len(kinds) == 0 {
+		kinds = []string{
+			ResourceVirtualGuests,
+			ResourceHardware,
+			ResourceVlans,
+			ResourceSubnets,
+			ResourceVolumes,
+			ResourceImages,
+			ResourceLoadBalancers,
+		}
+	}
+
+	// Each lister writes its own field of the inventory, and its own error
+	errs := make([]error, len(kinds))
+
+	var wg sync.WaitGroup
+	for i, kind := range kinds {
+		wg.Add(1)
+		go func(i int, list func() error) {
+			defer wg.Done()
+			errs[i] = list()
+		}(i, listers[kind])
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return Inventory{}, fmt.Errorf("Error listing %s: %s", kinds[i], err)
+		}
+	}
+
+	return inventory, nil
+}
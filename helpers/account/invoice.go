@@ -0,0 +1,224 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package account
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DefaultInvoiceMask is the default object mask for invoices
+const DefaultInvoiceMask = "id,createDate,closedDate,typeCode,statusCode,invoiceTotalAmount," +
+	"invoiceTotalPreTaxAmount,invoiceTopLevelItemCount"
+
+// Number of top level items requested at once by GetInvoiceDetail
+const invoiceItemPageSize = 100
+
+const invoiceItemMask = "id,parentId,categoryCode,description,hostName,domainName,location[name]," +
+	"oneTimeAfterTaxAmount,recurringAfterTaxAmount,setupAfterTaxAmount,laborAfterTaxAmount," +
+	"children[id,parentId,categoryCode,description,hostName,domainName,location[name]," +
+	"oneTimeAfterTaxAmount,recurringAfterTaxAmount,setupAfterTaxAmount,laborAfterTaxAmount]"
+
+// Format of the dates of the object filters
+const filterDateFormat = "01/02/2006 15:04:05"
+
+// DateRange is a range of dates. A zero Start or End leaves the range open on
+// that side.
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// InvoiceDetail is an invoice along with all its top level items. The items
+// attached to each top level item are listed in its Children.
+type InvoiceDetail struct {
+	Invoice datatypes.Billing_Invoice
+	Items   []datatypes.Billing_Invoice_Item
+}
+
+// InvoiceRow is a line of an invoice, as exported by ExportInvoiceCSV and
+// ExportInvoiceJSON. ParentId is the id of the top level item of the line, and
+// is 0 for the top level items themselves. Amounts include taxes.
+type InvoiceRow struct {
+	InvoiceId   int     `json:"invoiceId"`
+	ItemId      int     `json:"itemId"`
+	ParentId    int     `json:"parentId"`
+	Category    string  `json:"category"`
+	Description string  `json:"description"`
+	HostName    string  `json:"hostName"`
+	DomainName  string  `json:"domainName"`
+	Location    string  `json:"location"`
+	OneTime     float64 `json:"oneTime"`
+	Recurring   float64 `json:"recurring"`
+	Total       float64 `json:"total"`
+}
+
+// GetInvoices returns the invoices of the account created within dateRange.
+// An object mask can be provided as an optional argument, and
+// DefaultInvoiceMask is used otherwise.
+func GetInvoices(sess *session.Session, dateRange DateRange, mask ...string) ([]datatypes.Billing_Invoice, error) {
+	objectMask := DefaultInvoiceMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	filters := filter.New()
+
+	start := dateRange.Start.Format(filterDateFormat)
+	end := dateRange.End.Format(filterDateFormat)
+
+	switch {
+	case !dateRange.Start.IsZero() && !dateRange.End.IsZero():
+		filters = append(filters, filter.Path("invoices.createDate").DateBetween(start, end))
+	case !dateRange.Start.IsZero():
+		filters = append(filters, filter.Path("invoices.createDate").DateAfter(start))
+	case !dateRange.End.IsZero():
+		filters = append(filters, filter.Path("invoices.createDate").DateBefore(end))
+	}
+
+	return services.GetAccountService(sess).
+		Mask(objectMask).
+		Filter(filters.Build()).
+		GetInvoices()
+}
+
+// GetInvoiceDetail returns the invoice with the provided id along with all its
+// top level items and their children. The top level items are requested in
+// pages, as large invoices cannot be returned at once.
+func GetInvoiceDetail(sess *session.Session, invoiceId int) (InvoiceDetail, error) {
+	service := services.GetBillingInvoiceService(sess).Id(invoiceId)
+
+	invoice, err := service.Mask(DefaultInvoiceMask).GetObject()
+	if err != nil {
+		return InvoiceDetail{}, err
+	}
+
+	detail := InvoiceDetail{
+		Invoice: invoice,
+		Items:   []datatypes.Billing_Invoice_Item{},
+	}
+
+	for offset := 0; ; offset += invoiceItemPageSize {
+		items, err := service.
+			Mask(invoiceItemMask).
+			Limit(invoiceItemPageSize).
+			Offset(offset).
+			GetInvoiceTopLevelItems()
+		if err != nil {
+			return InvoiceDetail{}, fmt.Errorf("Error listing the items of invoice %d: %s", invoiceId, err)
+		}
+
+		detail.Items = append(detail.Items, items...)
+
+		if len(items) < invoiceItemPageSize {
+			break
+		}
+	}
+
+	return detail, nil
+}
+
+// InvoiceRows flattens the items of an invoice into rows, each top level item
+// followed by its children
+func InvoiceRows(detail InvoiceDetail) []InvoiceRow {
+	invoiceId := sl.Get(detail.Invoice.Id, 0).(int)
+
+	rows := []InvoiceRow{}
+	for _, item := range detail.Items {
+		rows = append(rows, invoiceRow(invoiceId, 0, item))
+
+		for _, child := range item.Children {
+			rows = append(rows, invoiceRow(invoiceId, sl.Get(item.Id, 0).(int), child))
+		}
+	}
+
+	return rows
+}
+
+// ExportInvoiceCSV writes the rows of an invoice to w as CSV, preceded by a
+// header line
+func ExportInvoiceCSV(w io.Writer, rows []InvoiceRow) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{
+		"invoiceId", "itemId", "parentId", "category", "description",
+		"hostName", "domainName", "location", "oneTime", "recurring", "total",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := []string{
+			strconv.Itoa(row.InvoiceId),
+			strconv.Itoa(row.ItemId),
+			strconv.Itoa(row.ParentId),
+			row.Category,
+			row.Description,
+			row.HostName,
+			row.DomainName,
+			row.Location,
+			strconv.FormatFloat(row.OneTime, 'f', 2, 64),
+			strconv.FormatFloat(row.Recurring, 'f', 2, 64),
+			strconv.FormatFloat(row.Total, 'f', 2, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportInvoiceJSON writes the rows of an invoice to w as a JSON array
+func ExportInvoiceJSON(w io.Writer, rows []InvoiceRow) error {
+	return json.NewEncoder(w).Encode(rows)
+}
+
+func invoiceRow(invoiceId int, parentId int, item datatypes.Billing_Invoice_Item) InvoiceRow {
+	amount := func(fee *datatypes.Float64) float64 {
+		return float64(sl.Get(fee, datatypes.Float64(0)).(datatypes.Float64))
+	}
+
+	oneTime := amount(item.OneTimeAfterTaxAmount) + amount(item.SetupAfterTaxAmount) + amount(item.LaborAfterTaxAmount)
+	recurring := amount(item.RecurringAfterTaxAmount)
+
+	return InvoiceRow{
+		InvoiceId:   invoiceId,
+		ItemId:      sl.Get(item.Id, 0).(int),
+		ParentId:    parentId,
+		Category:    sl.Get(item.CategoryCode, "").(string),
+		Description: sl.Get(item.Description, "").(string),
+		HostName:    sl.Get(item.HostName, "").(string),
+		DomainName:  sl.Get(item.DomainName, "").(string),
+		Location:    sl.Grab(item, "Location.Name", "").(string),
+		OneTime:     oneTime,
+		Recurring:   recurring,
+		Total:       oneTime + recurring,
+	}
+}
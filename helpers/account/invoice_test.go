@@ -0,0 +1,90 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package account
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+var invoiceDetail = InvoiceDetail{
+	Invoice: datatypes.Billing_Invoice{Id: sl.Int(1000)},
+	Items: []datatypes.Billing_Invoice_Item{
+		{
+			Id:                      sl.Int(1),
+			CategoryCode:            sl.String("guest_core"),
+			Description:             sl.String("2 x 2.0 GHz Cores"),
+			HostName:                sl.String("web"),
+			DomainName:              sl.String("example.com"),
+			Location:                &datatypes.Location{Name: sl.String("dal10")},
+			RecurringAfterTaxAmount: sl.Float(10.5),
+			SetupAfterTaxAmount:     sl.Float(2),
+			Children: []datatypes.Billing_Invoice_Item{
+				{
+					Id:                      sl.Int(2),
+					CategoryCode:            sl.String("ram"),
+					Description:             sl.String("4 GB, \"fast\""),
+					RecurringAfterTaxAmount: sl.Float(4.25),
+				},
+			},
+		},
+		{
+			Id:                    sl.Int(3),
+			CategoryCode:          sl.String("network_vlan"),
+			OneTimeAfterTaxAmount: sl.Float(1),
+			LaborAfterTaxAmount:   sl.Float(0.5),
+		},
+	},
+}
+
+func TestInvoiceRows(t *testing.T) {
+	expected := []InvoiceRow{
+		{InvoiceId: 1000, ItemId: 1, Category: "guest_core", Description: "2 x 2.0 GHz Cores", HostName: "web",
+			DomainName: "example.com", Location: "dal10", OneTime: 2, Recurring: 10.5, Total: 12.5},
+		{InvoiceId: 1000, ItemId: 2, ParentId: 1, Category: "ram", Description: "4 GB, \"fast\"", Recurring: 4.25, Total: 4.25},
+		{InvoiceId: 1000, ItemId: 3, Category: "network_vlan", OneTime: 1.5, Total: 1.5},
+	}
+
+	if rows := InvoiceRows(invoiceDetail); !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, rows)
+	}
+
+	if rows := InvoiceRows(InvoiceDetail{}); len(rows) != 0 {
+		t.Errorf("Expected no rows for an empty invoice, got %+v", rows)
+	}
+}
+
+func TestExportInvoiceCSV(t *testing.T) {
+	expected := `invoiceId,itemId,parentId,category,description,hostName,domainName,location,oneTime,recurring,total
+1000,1,0,guest_core,2 x 2.0 GHz Cores,web,example.com,dal10,2.00,10.50,12.50
+1000,2,1,ram,"4 GB, ""fast""",,,,0.00,4.25,4.25
+1000,3,0,network_vlan,,,,,1.50,0.00,1.50
+`
+
+	var buf bytes.Buffer
+	if err := ExportInvoiceCSV(&buf, InvoiceRows(invoiceDetail)); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	if buf.String() != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
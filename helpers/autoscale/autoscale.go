@@ -0,0 +1,209 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package autoscale
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Key names of the termination policies, which select the members removed
+// first when a group scales down
+const (
+	TerminateClosestToNextCharge = "CLOSEST_TO_NEXT_CHARGE"
+	TerminateNewest              = "NEWEST"
+	TerminateOldest              = "OLDEST"
+)
+
+// DefaultGroupMask is the default object mask for scale groups
+const DefaultGroupMask = "id,name,cooldown,minimumMemberCount,maximumMemberCount,virtualGuestMemberCount," +
+	"suspendedFlag,createDate,lastActionDate,status[keyName],regionalGroup[name],terminationPolicy[keyName]"
+
+// GroupConfig describes a scale group to create. RegionalGroup is the name of
+// the regional group the members are created in (e.g. "na-usa-central-1"),
+// Cooldown the time to wait after a scaling action before the next one, and
+// TerminationPolicy the key name of the termination policy, which defaults to
+// TerminateClosestToNextCharge. GuestTemplate is the template the members are
+// created from, as accepted by Virtual_Guest::createObject(), without a
+// datacenter.
+type GroupConfig struct {
+	Name              string
+	RegionalGroup     string
+	MinimumMembers    int
+	MaximumMembers    int
+	Cooldown          time.Duration
+	TerminationPolicy string
+	VlanIds           []int
+	GuestTemplate     datatypes.Virtual_Guest
+}
+
+// Member is a virtual guest member of a scale group
+type Member struct {
+	GuestId    int
+	Hostname   string
+	Status     string
+	PowerState string
+	CreateDate time.Time
+}
+
+// GroupStatus is the current state of a scale group and of its members
+type GroupStatus struct {
+	Id             int
+	Name           string
+	Status         string
+	Suspended      bool
+	MinimumMembers int
+	MaximumMembers int
+	Members        []Member
+}
+
+// CreateGroup creates a scale group from config, and returns it. The group
+// starts with MinimumMembers members.
+func CreateGroup(sess *session.Session, config GroupConfig) (datatypes.Scale_Group, error) {
+	if config.Name == "" || config.RegionalGroup == "" {
+		return datatypes.Scale_Group{}, fmt.Errorf("A name and regional group are required to create a scale group")
+	}
+
+	if config.MaximumMembers < config.MinimumMembers {
+		return datatypes.Scale_Group{}, fmt.Errorf(
+			"The maximum member count %d is lower than the minimum member count %d",
+			config.MaximumMembers, config.MinimumMembers)
+	}
+
+	regionalGroups, err := services.GetLocationGroupRegionalService(sess).
+		Mask("id").
+		Filter(filter.Path("name").Eq(config.RegionalGroup).Build()).
+		GetAllObjects()
+	if err != nil {
+		return datatypes.Scale_Group{}, err
+	}
+
+	if len(regionalGroups) == 0 {
+		return datatypes.Scale_Group{}, fmt.Errorf("No regional group found with name of %s", config.RegionalGroup)
+	}
+
+	terminationPolicy := config.TerminationPolicy
+	if terminationPolicy == "" {
+		terminationPolicy = TerminateClosestToNextCharge
+	}
+
+	policies, err := services.GetScaleTerminationPolicyService(sess).
+		Mask("id").
+		Filter(filter.Path("keyName").Eq(terminationPolicy).Build()).
+		GetAllObjects()
+	if err != nil {
+		return datatypes.Scale_Group{}, err
+	}
+
+	if len(policies) == 0 {
+		return datatypes.Scale_Group{}, fmt.Errorf("No termination policy found with key name of %s", terminationPolicy)
+	}
+
+	template := config.GuestTemplate
+
+	group := datatypes.Scale_Group{
+		Name:                       sl.String(config.Name),
+		RegionalGroupId:            regionalGroups[0].Id,
+		MinimumMemberCount:         sl.Int(config.MinimumMembers),
+		MaximumMemberCount:         sl.Int(config.MaximumMembers),
+		Cooldown:                   sl.Int(int(config.Cooldown.Seconds())),
+		TerminationPolicyId:        policies[0].Id,
+		SuspendedFlag:              sl.Bool(false),
+		BalancedTerminationFlag:    sl.Bool(false),
+		VirtualGuestMemberTemplate: &template,
+		NetworkVlans:               make([]datatypes.Scale_Network_Vlan, 0, len(config.VlanIds)),
+	}
+
+	for _, id := range config.VlanIds {
+		group.NetworkVlans = append(group.NetworkVlans, datatypes.Scale_Network_Vlan{NetworkVlanId: sl.Int(id)})
+	}
+
+	created, err := services.GetScaleGroupService(sess).CreateObject(&group)
+	if err != nil {
+		return datatypes.Scale_Group{}, fmt.Errorf("Error creating scale group %s: %s", config.Name, err)
+	}
+
+	return created, nil
+}
+
+// GetGroupStatus returns the current state of the scale group with the
+// provided id, along with its members
+func GetGroupStatus(sess *session.Session, groupId int) (GroupStatus, error) {
+	group, err := services.GetScaleGroupService(sess).
+		Id(groupId).
+		Mask("id,name,suspendedFlag,minimumMemberCount,maximumMemberCount,status[keyName]," +
+			"virtualGuestMembers[createDate,virtualGuest[id,fullyQualifiedDomainName,status[keyName],powerState[keyName]]]").
+		GetObject()
+	if err != nil {
+		return GroupStatus{}, err
+	}
+
+	status := GroupStatus{
+		Id:             groupId,
+		Name:           sl.Get(group.Name, "").(string),
+		Status:         sl.Grab(group, "Status.KeyName", "").(string),
+		Suspended:      sl.Get(group.SuspendedFlag, false).(bool),
+		MinimumMembers: sl.Get(group.MinimumMemberCount, 0).(int),
+		MaximumMembers: sl.Get(group.MaximumMemberCount, 0).(int),
+		Members:        make([]Member, 0, len(group.VirtualGuestMembers)),
+	}
+
+	for _, member := range group.VirtualGuestMembers {
+		m := Member{
+			GuestId:    sl.Grab(member, "VirtualGuest.Id", 0).(int),
+			Hostname:   sl.Grab(member, "VirtualGuest.FullyQualifiedDomainName", "").(string),
+			Status:     sl.Grab(member, "VirtualGuest.Status.KeyName", "").(string),
+			PowerState: sl.Grab(member, "VirtualGuest.PowerState.KeyName", "").(string),
+		}
+
+		if member.CreateDate != nil {
+			m.CreateDate = member.CreateDate.Time
+		}
+
+		status.Members = append(status.Members, m)
+	}
+
+	return status, nil
+}
+
+// Scale adds delta members to the scale group with the provided id, or
+// removes them when delta is negative, within the member limits of the group
+func Scale(sess *session.Session, groupId int, delta int) error {
+	_, err := services.GetScaleGroupService(sess).Id(groupId).Scale(sl.Int(delta))
+	if err != nil {
+		return fmt.Errorf("Error scaling group %d by %d: %s", groupId, delta, err)
+	}
+
+	return nil
+}
+
+// ScaleTo adds or removes members of the scale group with the provided id
+// until it has count members
+func ScaleTo(sess *session.Session, groupId int, count int) error {
+	_, err := services.GetScaleGroupService(sess).Id(groupId).ScaleTo(sl.Int(count))
+	if err != nil {
+		return fmt.Errorf("Error scaling group %d to %d members: %s", groupId, count, err)
+	}
+
+	return nil
+}
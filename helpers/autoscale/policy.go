@@ -0,0 +1,157 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package autoscale
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Types of the scaling actions of a policy: Amount is a number of members to
+// add or remove, the number of members to reach, or a percentage of the
+// current members to add or remove
+const (
+	ScaleRelative = "RELATIVE"
+	ScaleAbsolute = "ABSOLUTE"
+	ScalePercent  = "PERCENT"
+)
+
+// Metrics watched by resource use triggers
+const (
+	MetricCPU               = "host.cpu.percent"
+	MetricPublicNetworkIn   = "host.network.frontend.in.rate"
+	MetricPublicNetworkOut  = "host.network.frontend.out.rate"
+	MetricPrivateNetworkIn  = "host.network.backend.in.rate"
+	MetricPrivateNetworkOut = "host.network.backend.out.rate"
+)
+
+// Algorithm averaging the watched metrics
+const watchAlgorithm = "EWMA"
+
+// PolicyConfig describes a scale policy to create, which applies a scaling
+// action of type ScaleType (e.g. ScaleRelative) and of Amount when one of its
+// triggers fires. Cooldown overrides the cooldown of the group when set.
+type PolicyConfig struct {
+	Name      string
+	ScaleType string
+	Amount    int
+	Cooldown  time.Duration
+}
+
+// AddPolicy creates a scale policy in the scale group with the provided id,
+// and returns its id. Triggers are added to the policy with
+// AddResourceUseTrigger and AddScheduleTrigger.
+func AddPolicy(sess *session.Session, groupId int, config PolicyConfig) (int, error) {
+	if config.ScaleType != ScaleRelative && config.ScaleType != ScaleAbsolute && config.ScaleType != ScalePercent {
+		return 0, fmt.Errorf("Unknown scale type %s", config.ScaleType)
+	}
+
+	policy := datatypes.Scale_Policy{
+		Name:         sl.String(config.Name),
+		ScaleGroupId: sl.Int(groupId),
+		ScaleActions: []datatypes.Scale_Policy_Action_Scale{{
+			Amount:    sl.Int(config.Amount),
+			ScaleType: sl.String(config.ScaleType),
+		}},
+	}
+
+	if config.Cooldown != 0 {
+		policy.Cooldown = sl.Int(int(config.Cooldown.Seconds()))
+	}
+
+	created, err := services.GetScalePolicyService(sess).CreateObject(&policy)
+	if err != nil {
+		return 0, fmt.Errorf("Error creating policy %s in scale group %d: %s", config.Name, groupId, err)
+	}
+
+	return *created.Id, nil
+}
+
+// AddResourceUseTrigger adds a trigger to the scale policy with the provided
+// id, which fires when the average of metric (e.g. MetricCPU) over period
+// compares to value with operator (">" or "<"). For instance, a trigger on
+// MetricCPU, ">" and 80 fires when the CPU use of the members exceeds 80%.
+func AddResourceUseTrigger(
+	sess *session.Session,
+	policyId int,
+	metric string,
+	operator string,
+	value int,
+	period time.Duration,
+) error {
+
+	if operator != ">" && operator != "<" {
+		return fmt.Errorf("Unknown operator %s", operator)
+	}
+
+	trigger := datatypes.Scale_Policy_Trigger_ResourceUse{
+		Watches: []datatypes.Scale_Policy_Trigger_ResourceUse_Watch{{
+			Algorithm: sl.String(watchAlgorithm),
+			Metric:    sl.String(metric),
+			Operator:  sl.String(operator),
+			Period:    sl.Int(int(period.Seconds())),
+			Value:     sl.String(strconv.Itoa(value)),
+		}},
+	}
+	trigger.ScalePolicyId = sl.Int(policyId)
+
+	_, err := services.GetScalePolicyTriggerResourceUseService(sess).CreateObject(&trigger)
+	if err != nil {
+		return fmt.Errorf("Error adding a %s trigger to policy %d: %s", metric, policyId, err)
+	}
+
+	return nil
+}
+
+// AddScheduleTrigger adds a trigger to the scale policy with the provided id,
+// which fires on the schedule described by the provided cron expression (e.g.
+// "0 8 * * MON-FRI"). The expression is validated before the trigger is
+// added.
+func AddScheduleTrigger(sess *session.Session, policyId int, schedule string) error {
+	service := services.GetScalePolicyTriggerRepeatingService(sess)
+
+	if err := service.ValidateCronExpression(sl.String(schedule)); err != nil {
+		return fmt.Errorf("Invalid schedule %s: %s", schedule, err)
+	}
+
+	trigger := datatypes.Scale_Policy_Trigger_Repeating{
+		Schedule: sl.String(schedule),
+	}
+	trigger.ScalePolicyId = sl.Int(policyId)
+
+	if _, err := service.CreateObject(&trigger); err != nil {
+		return fmt.Errorf("Error adding a schedule trigger to policy %d: %s", policyId, err)
+	}
+
+	return nil
+}
+
+// TriggerPolicy applies the scaling action of the scale policy with the
+// provided id immediately, regardless of its triggers
+func TriggerPolicy(sess *session.Session, policyId int) error {
+	if _, err := services.GetScalePolicyService(sess).Id(policyId).Trigger(); err != nil {
+		return fmt.Errorf("Error triggering policy %d: %s", policyId, err)
+	}
+
+	return nil
+}
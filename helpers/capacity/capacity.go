@@ -0,0 +1,147 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capacity
+
+import (
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Limits holds the quotas of an account, as agreed with SoftLayer, which the
+// API does not expose. A zero limit is unknown, and is not enforced.
+type Limits struct {
+	VirtualGuests  int
+	Cores          int
+	MemoryGB       int
+	PublicIps      int
+	VlansPerRouter int
+}
+
+// Quota compares the use of a resource to its limit. Limit is 0 when unknown.
+type Quota struct {
+	Used  int
+	Limit int
+}
+
+// Headroom returns the amount of the resource which can still be used, and
+// false when the limit is unknown
+func (q Quota) Headroom() (int, bool) {
+	if q.Limit == 0 {
+		return 0, false
+	}
+
+	return q.Limit - q.Used, true
+}
+
+// Exceeded reports whether the use of the resource reached its limit
+func (q Quota) Exceeded() bool {
+	headroom, ok := q.Headroom()
+	return ok && headroom <= 0
+}
+
+// Report is the capacity of an account: the use of its resources compared to
+// their limits. HourlyInstancesAvailable is the number of hourly virtual
+// guests which can still be ordered, as reported by SoftLayer. VlansPerRouter
+// is keyed by the hostname of the routers of all the pods, the VLANs of the
+// account being counted on their primary router.
+type Report struct {
+	Taken                    time.Time
+	VirtualGuests            Quota
+	Cores                    Quota
+	MemoryGB                 Quota
+	PublicIps                Quota
+	HourlyInstancesAvailable int
+	VlansPerRouter           map[string]Quota
+}
+
+// GetReport returns the capacity of the account, comparing the use of its
+// resources to limits
+func GetReport(sess *session.Session, limits Limits) (Report, error) {
+	service := services.GetAccountService(sess)
+
+	guests, err := service.Mask("id,maxCpu,maxMemory").GetVirtualGuests()
+	if err != nil {
+		return Report{}, err
+	}
+
+	subnets, err := service.Mask("id,usableIpAddressCount").GetPublicSubnets()
+	if err != nil {
+		return Report{}, err
+	}
+
+	vlans, err := service.Mask("id,primaryRouter[hostname]").GetNetworkVlans()
+	if err != nil {
+		return Report{}, err
+	}
+
+	hourly, err := services.GetScaleGroupService(sess).GetAvailableHourlyInstanceLimit()
+	if err != nil {
+		return Report{}, err
+	}
+
+	pods, err := services.GetNetworkPodService(sess).GetAllObjects()
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		Taken:                    time.Now(),
+		VirtualGuests:            Quota{Used: len(guests), Limit: limits.VirtualGuests},
+		Cores:                    Quota{Limit: limits.Cores},
+		MemoryGB:                 Quota{Limit: limits.MemoryGB},
+		PublicIps:                Quota{Limit: limits.PublicIps},
+		HourlyInstancesAvailable: hourly,
+		VlansPerRouter:           map[string]Quota{},
+	}
+
+	memoryMB := 0
+	for _, guest := range guests {
+		report.Cores.Used += sl.Get(guest.MaxCpu, 0).(int)
+		memoryMB += sl.Get(guest.MaxMemory, 0).(int)
+	}
+	report.MemoryGB.Used = memoryMB / 1024
+
+	for _, subnet := range subnets {
+		report.PublicIps.Used += int(sl.Get(subnet.UsableIpAddressCount, datatypes.Float64(0)).(datatypes.Float64))
+	}
+
+	for _, pod := range pods {
+		for _, router := range []*string{pod.BackendRouterName, pod.FrontendRouterName} {
+			if hostname := sl.Get(router, "").(string); hostname != "" {
+				report.VlansPerRouter[hostname] = Quota{Limit: limits.VlansPerRouter}
+			}
+		}
+	}
+
+	for _, vlan := range vlans {
+		hostname := sl.Grab(vlan, "PrimaryRouter.Hostname", "").(string)
+		if hostname == "" {
+			continue
+		}
+
+		quota := report.VlansPerRouter[hostname]
+		quota.Used++
+		quota.Limit = limits.VlansPerRouter
+		report.VlansPerRouter[hostname] = quota
+	}
+
+	return report, nil
+}
@@ -0,0 +1,324 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cdn
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DefaultVendor is the CDN vendor mappings are created with, when none is
+// specified
+const DefaultVendor = "akamai"
+
+// Origin types of a mapping
+const (
+	OriginServer        = "HOST_SERVER"
+	OriginObjectStorage = "OBJECT_STORAGE"
+)
+
+// Protocols a mapping serves content over
+const (
+	ProtocolHttp         = "HTTP"
+	ProtocolHttps        = "HTTPS"
+	ProtocolHttpAndHttps = "HTTP_AND_HTTPS"
+)
+
+// Mapping is a CDN mapping, which serves the content of an origin under a
+// domain. BucketName only applies to OBJECT_STORAGE origins. UniqueId, Cname
+// and Status are set by SoftLayer.
+type Mapping struct {
+	UniqueId   string
+	Vendor     string
+	Domain     string
+	Cname      string
+	Status     string
+	Origin     string
+	OriginType string
+	Protocol   string
+	HttpPort   int
+	HttpsPort  int
+	Path       string
+	Header     string
+	BucketName string
+}
+
+// Usage holds the usage of a CDN mapping over a period: the bandwidth served,
+// in GB, the number of hits, and the percentage of hits served from cache
+type Usage struct {
+	Bandwidth float64
+	Hits      int
+	HitRatio  float64
+}
+
+// The SoftLayer_Network_CdnMarketplace_* services and their datatypes are not
+// part of the generated code, so they are called directly through the session.
+
+// mappingContainer is the SoftLayer_Container_Network_CdnMarketplace_Configuration_Input
+// and SoftLayer_Container_Network_CdnMarketplace_Configuration_Mapping types
+type mappingContainer struct {
+	UniqueId   *string `json:"uniqueId,omitempty" xmlrpc:"uniqueId,omitempty"`
+	VendorName *string `json:"vendorName,omitempty" xmlrpc:"vendorName,omitempty"`
+	Domain     *string `json:"domain,omitempty" xmlrpc:"domain,omitempty"`
+	Cname      *string `json:"cname,omitempty" xmlrpc:"cname,omitempty"`
+	Status     *string `json:"status,omitempty" xmlrpc:"status,omitempty"`
+	Origin     *string `json:"origin,omitempty" xmlrpc:"origin,omitempty"`
+	OriginHost *string `json:"originHost,omitempty" xmlrpc:"originHost,omitempty"`
+	OriginType *string `json:"originType,omitempty" xmlrpc:"originType,omitempty"`
+	Protocol   *string `json:"protocol,omitempty" xmlrpc:"protocol,omitempty"`
+	HttpPort   *int    `json:"httpPort,omitempty" xmlrpc:"httpPort,omitempty"`
+	HttpsPort  *int    `json:"httpsPort,omitempty" xmlrpc:"httpsPort,omitempty"`
+	Path       *string `json:"path,omitempty" xmlrpc:"path,omitempty"`
+	Header     *string `json:"header,omitempty" xmlrpc:"header,omitempty"`
+	BucketName *string `json:"bucketName,omitempty" xmlrpc:"bucketName,omitempty"`
+}
+
+// metricsContainer is the SoftLayer_Container_Network_CdnMarketplace_Metrics type
+type metricsContainer struct {
+	Totals []string `json:"totals,omitempty" xmlrpc:"totals,omitempty"`
+}
+
+// ListMappings returns the CDN mappings of the account
+func ListMappings(sess *session.Session) ([]Mapping, error) {
+	var containers []mappingContainer
+	err := sess.DoRequest("SoftLayer_Network_CdnMarketplace_Configuration_Mapping", "listDomainMappings", nil, &sl.Options{}, &containers)
+	if err != nil {
+		return nil, err
+	}
+
+	return mappingsOf(containers), nil
+}
+
+// GetMapping returns the CDN mapping with the provided unique id
+func GetMapping(sess *session.Session, uniqueId string) (Mapping, error) {
+	var containers []mappingContainer
+	err := sess.DoRequest(
+		"SoftLayer_Network_CdnMarketplace_Configuration_Mapping",
+		"listDomainMappingByUniqueId",
+		[]interface{}{uniqueId},
+		&sl.Options{},
+		&containers)
+	if err != nil {
+		return Mapping{}, err
+	}
+
+	if len(containers) == 0 {
+		return Mapping{}, fmt.Errorf("No CDN mapping found with unique id %s", uniqueId)
+	}
+
+	return mappingsOf(containers)[0], nil
+}
+
+// CreateMapping creates a CDN mapping, and returns it. Vendor defaults to
+// DefaultVendor, OriginType to OriginServer, Protocol to ProtocolHttp, Path to
+// "/" and the ports to their standard values.
+func CreateMapping(sess *session.Session, mapping Mapping) (Mapping, error) {
+	input := mapping.container()
+
+	var containers []mappingContainer
+	err := sess.DoRequest(
+		"SoftLayer_Network_CdnMarketplace_Configuration_Mapping",
+		"createDomainMapping",
+		[]interface{}{&input},
+		&sl.Options{},
+		&containers)
+	if err != nil {
+		return Mapping{}, fmt.Errorf("Error creating CDN mapping for %s: %s", mapping.Domain, err)
+	}
+
+	if len(containers) == 0 {
+		return Mapping{}, fmt.Errorf("No CDN mapping was created for %s", mapping.Domain)
+	}
+
+	return mappingsOf(containers)[0], nil
+}
+
+// UpdateMapping updates the CDN mapping identified by the UniqueId of mapping,
+// and returns it. Defaults are applied as in CreateMapping.
+func UpdateMapping(sess *session.Session, mapping Mapping) (Mapping, error) {
+	if mapping.UniqueId == "" {
+		return Mapping{}, fmt.Errorf("The unique id of the CDN mapping to update is required")
+	}
+
+	input := mapping.container()
+
+	var containers []mappingContainer
+	err := sess.DoRequest(
+		"SoftLayer_Network_CdnMarketplace_Configuration_Mapping",
+		"updateDomainMapping",
+		[]interface{}{&input},
+		&sl.Options{},
+		&containers)
+	if err != nil {
+		return Mapping{}, fmt.Errorf("Error updating CDN mapping %s: %s", mapping.UniqueId, err)
+	}
+
+	if len(containers) == 0 {
+		return mapping, nil
+	}
+
+	return mappingsOf(containers)[0], nil
+}
+
+// DeleteMapping deletes the CDN mapping with the provided unique id
+func DeleteMapping(sess *session.Session, uniqueId string) error {
+	var containers []mappingContainer
+	return sess.DoRequest(
+		"SoftLayer_Network_CdnMarketplace_Configuration_Mapping",
+		"deleteDomainMapping",
+		[]interface{}{uniqueId},
+		&sl.Options{},
+		&containers)
+}
+
+// Purge removes the content under the provided paths (e.g. "/images/*") from
+// the cache of the CDN mapping with the provided unique id
+func Purge(sess *session.Session, uniqueId string, paths ...string) error {
+	for _, path := range paths {
+		var result []interface{}
+		err := sess.DoRequest(
+			"SoftLayer_Network_CdnMarketplace_Configuration_Cache_Purge",
+			"createPurge",
+			[]interface{}{uniqueId, path},
+			&sl.Options{},
+			&result)
+		if err != nil {
+			return fmt.Errorf("Error purging %s from CDN mapping %s: %s", path, uniqueId, err)
+		}
+	}
+
+	return nil
+}
+
+// GetUsage returns the usage of the CDN mapping with the provided unique id
+// between start and end
+func GetUsage(sess *session.Session, uniqueId string, start time.Time, end time.Time) (Usage, error) {
+	var metrics []metricsContainer
+	err := sess.DoRequest(
+		"SoftLayer_Network_CdnMarketplace_Metrics",
+		"getMappingUsageMetrics",
+		[]interface{}{uniqueId, start.Unix(), end.Unix(), "aggregate"},
+		&sl.Options{},
+		&metrics)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	usage := Usage{}
+	if len(metrics) == 0 {
+		return usage, nil
+	}
+
+	// Totals are returned as strings, in the order bandwidth, hits, hit ratio
+	totals := metrics[0].Totals
+	if len(totals) > 0 {
+		usage.Bandwidth, _ = strconv.ParseFloat(totals[0], 64)
+	}
+	if len(totals) > 1 {
+		hits, _ := strconv.ParseFloat(totals[1], 64)
+		usage.Hits = int(hits)
+	}
+	if len(totals) > 2 {
+		usage.HitRatio, _ = strconv.ParseFloat(totals[2], 64)
+	}
+
+	return usage, nil
+}
+
+func (m Mapping) container() mappingContainer {
+	if m.Vendor == "" {
+		m.Vendor = DefaultVendor
+	}
+	if m.OriginType == "" {
+		m.OriginType = OriginServer
+	}
+	if m.Protocol == "" {
+		m.Protocol = ProtocolHttp
+	}
+	if m.Path == "" {
+		m.Path = "/"
+	}
+	if m.HttpPort == 0 && m.Protocol != ProtocolHttps {
+		m.HttpPort = 80
+	}
+	if m.HttpsPort == 0 && m.Protocol != ProtocolHttp {
+		m.HttpsPort = 443
+	}
+
+	container := mappingContainer{
+		VendorName: sl.String(m.Vendor),
+		Domain:     sl.String(m.Domain),
+		Origin:     sl.String(m.Origin),
+		OriginType: sl.String(m.OriginType),
+		Protocol:   sl.String(m.Protocol),
+		Path:       sl.String(m.Path),
+	}
+
+	if m.UniqueId != "" {
+		container.UniqueId = sl.String(m.UniqueId)
+	}
+	if m.Cname != "" {
+		container.Cname = sl.String(m.Cname)
+	}
+	if m.HttpPort != 0 {
+		container.HttpPort = sl.Int(m.HttpPort)
+	}
+	if m.HttpsPort != 0 {
+		container.HttpsPort = sl.Int(m.HttpsPort)
+	}
+	if m.Header != "" {
+		container.Header = sl.String(m.Header)
+	}
+	if m.BucketName != "" {
+		container.BucketName = sl.String(m.BucketName)
+	}
+
+	return container
+}
+
+func mappingsOf(containers []mappingContainer) []Mapping {
+	mappings := make([]Mapping, 0, len(containers))
+	for _, c := range containers {
+		// The origin is returned as originHost
+		origin := sl.Get(c.OriginHost, "").(string)
+		if origin == "" {
+			origin = sl.Get(c.Origin, "").(string)
+		}
+
+		mappings = append(mappings, Mapping{
+			UniqueId:   sl.Get(c.UniqueId, "").(string),
+			Vendor:     sl.Get(c.VendorName, "").(string),
+			Domain:     sl.Get(c.Domain, "").(string),
+			Cname:      sl.Get(c.Cname, "").(string),
+			Status:     sl.Get(c.Status, "").(string),
+			Origin:     origin,
+			OriginType: sl.Get(c.OriginType, "").(string),
+			Protocol:   sl.Get(c.Protocol, "").(string),
+			HttpPort:   sl.Get(c.HttpPort, 0).(int),
+			HttpsPort:  sl.Get(c.HttpsPort, 0).(int),
+			Path:       sl.Get(c.Path, "").(string),
+			Header:     sl.Get(c.Header, "").(string),
+			BucketName: sl.Get(c.BucketName, "").(string),
+		})
+	}
+
+	return mappings
+}
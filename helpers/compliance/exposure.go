@@ -0,0 +1,246 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compliance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/softlayer/softlayer-go/helpers/firewall"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Kinds of findings
+const (
+	FindingPublicInterface    = "public_interface"
+	FindingPublicLoadBalancer = "public_load_balancer"
+	FindingOpenFirewallRule   = "open_firewall_rule"
+)
+
+// Types of the resources findings are about
+const (
+	ResourceVirtualGuest = "virtual_guest"
+	ResourceHardware     = "hardware"
+	ResourceLoadBalancer = "load_balancer"
+)
+
+const exposureMask = "id,fullyQualifiedDomainName,primaryIpAddress," +
+	"frontendNetworkComponents[speed,status],firewallServiceComponent[id,status]"
+
+// Finding is a resource of the account exposed to the public network. Kind is
+// one of FindingPublicInterface, FindingPublicLoadBalancer and
+// FindingOpenFirewallRule, and Detail describes the exposure. Load balancers
+// are identified by ResourceUuid, and the other resources by ResourceId.
+type Finding struct {
+	Kind         string
+	ResourceType string
+	ResourceId   int
+	ResourceUuid string
+	Name         string
+	Address      string
+	Detail       string
+}
+
+// ExposureReport lists the findings of a public exposure audit
+type ExposureReport struct {
+	Taken    time.Time
+	Findings []Finding
+}
+
+// exposedServer is a virtual guest or hardware server with a public
+// interface, along with its shared firewall
+type exposedServer struct {
+	resourceType string
+	id           int
+	name         string
+	address      string
+	speed        int
+	firewallId   int
+}
+
+// enabledSpeed returns the speed of a network component, or 0 when its port is
+// disabled
+func enabledSpeed(speed *int, status *string) int {
+	if strings.ToUpper(sl.Get(status, "ACTIVE").(string)) != "ACTIVE" {
+		return 0
+	}
+
+	return sl.Get(speed, 0).(int)
+}
+
+// AuditPublicExposure enumerates the resources of the account exposed to the
+// public network: the virtual guests and hardware servers with a public IP
+// address and an enabled public interface, whose port is active with a speed
+// above 0, the public load balancers, and the rules of the shared firewalls of
+// those servers which permit traffic from any address.
+func AuditPublicExposure(sess *session.Session) (ExposureReport, error) {
+	service := services.GetAccountService(sess).Mask(exposureMask)
+
+	guests, err := service.GetVirtualGuests()
+	if err != nil {
+		return ExposureReport{}, err
+	}
+
+	hardware, err := service.GetHardware()
+	if err != nil {
+		return ExposureReport{}, err
+	}
+
+	servers := []exposedServer{}
+
+	for _, guest := range guests {
+		speed := 0
+		for _, component := range guest.FrontendNetworkComponents {
+			if s := enabledSpeed(component.Speed, component.Status); s > speed {
+				speed = s
+			}
+		}
+
+		servers = append(servers, exposedServer{
+			resourceType: ResourceVirtualGuest,
+			id:           sl.Get(guest.Id, 0).(int),
+			name:         sl.Get(guest.FullyQualifiedDomainName, "").(string),
+			address:      sl.Get(guest.PrimaryIpAddress, "").(string),
+			speed:        speed,
+			firewallId:   sl.Grab(guest, "FirewallServiceComponent.Id", 0).(int),
+		})
+	}
+
+	for _, hw := range hardware {
+		speed := 0
+		for _, component := range hw.FrontendNetworkComponents {
+			if s := enabledSpeed(component.Speed, component.Status); s > speed {
+				speed = s
+			}
+		}
+
+		servers = append(servers, exposedServer{
+			resourceType: ResourceHardware,
+			id:           sl.Get(hw.Id, 0).(int),
+			name:         sl.Get(hw.FullyQualifiedDomainName, "").(string),
+			address:      sl.Get(hw.PrimaryIpAddress, "").(string),
+			speed:        speed,
+			firewallId:   sl.Grab(hw, "FirewallServiceComponent.Id", 0).(int),
+		})
+	}
+
+	report := ExposureReport{
+		Taken:    time.Now(),
+		Findings: []Finding{},
+	}
+
+	for _, server := range servers {
+		if server.address == "" || server.speed == 0 {
+			continue
+		}
+
+		protection := "no firewall"
+		if server.firewallId != 0 {
+			protection = fmt.Sprintf("shared firewall %d", server.firewallId)
+		}
+
+		report.Findings = append(report.Findings, Finding{
+			Kind:         FindingPublicInterface,
+			ResourceType: server.resourceType,
+			ResourceId:   server.id,
+			Name:         server.name,
+			Address:      server.address,
+			Detail:       fmt.Sprintf("public interface at %d Mbps, %s", server.speed, protection),
+		})
+
+		if server.firewallId == 0 {
+			continue
+		}
+
+		rules, err := firewall.GetSharedFirewallRules(sess, server.firewallId)
+		if err != nil {
+			return ExposureReport{}, err
+		}
+
+		for _, rule := range rules {
+			if !openRule(rule) {
+				continue
+			}
+
+			report.Findings = append(report.Findings, Finding{
+				Kind:         FindingOpenFirewallRule,
+				ResourceType: server.resourceType,
+				ResourceId:   server.id,
+				Name:         server.name,
+				Address:      server.address,
+				Detail:       describeRule(rule),
+			})
+		}
+	}
+
+	lbs, err := services.GetNetworkLBaaSLoadBalancerService(sess).
+		Mask("id,uuid,name,isPublic,ipAddress[ipAddress],listeners[protocol,protocolPort]").
+		GetAllObjects()
+	if err != nil {
+		return ExposureReport{}, err
+	}
+
+	for _, lb := range lbs {
+		if sl.Get(lb.IsPublic, 0).(int) != 1 {
+			continue
+		}
+
+		ports := []string{}
+		for _, listener := range lb.Listeners {
+			ports = append(ports, fmt.Sprintf("%s:%d", sl.Get(listener.Protocol, ""), sl.Get(listener.ProtocolPort, 0)))
+		}
+
+		report.Findings = append(report.Findings, Finding{
+			Kind:         FindingPublicLoadBalancer,
+			ResourceType: ResourceLoadBalancer,
+			ResourceUuid: sl.Get(lb.Uuid, "").(string),
+			Name:         sl.Get(lb.Name, "").(string),
+			Address:      sl.Grab(lb, "IpAddress.IpAddress", "").(string),
+			Detail:       fmt.Sprintf("public load balancer listening on %s", strings.Join(ports, ", ")),
+		})
+	}
+
+	return report, nil
+}
+
+// openRule reports whether rule permits traffic from any address
+func openRule(rule firewall.Rule) bool {
+	if rule.Action != "permit" {
+		return false
+	}
+
+	switch rule.SourceIpAddress {
+	case "any", "":
+		return true
+	case "0.0.0.0", "::":
+		return rule.SourceIpCidr == 0
+	}
+
+	return false
+}
+
+func describeRule(rule firewall.Rule) string {
+	ports := "all ports"
+	if rule.DestinationPortStart != 0 || rule.DestinationPortEnd != 0 {
+		ports = fmt.Sprintf("ports %d-%d", rule.DestinationPortStart, rule.DestinationPortEnd)
+	}
+
+	return fmt.Sprintf("%s %s from any address to %s", rule.Action, rule.Protocol, ports)
+}
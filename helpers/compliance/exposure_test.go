@@ -0,0 +1,45 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compliance
+
+import (
+	"testing"
+
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+func TestEnabledSpeed(t *testing.T) {
+	tests := []struct {
+		speed    *int
+		status   *string
+		expected int
+	}{
+		{sl.Int(100), sl.String("ACTIVE"), 100},
+		{sl.Int(100), nil, 100},
+		{sl.Int(100), sl.String("DISABLED"), 0},
+		{sl.Int(100), sl.String("ABUSE_DISCONNECT"), 0},
+		{sl.Int(0), sl.String("ACTIVE"), 0},
+		{nil, sl.String("ACTIVE"), 0},
+	}
+
+	for _, test := range tests {
+		if speed := enabledSpeed(test.speed, test.status); speed != test.expected {
+			t.Errorf("Expected %d for speed %v and status %v, got %d",
+				test.expected, sl.Get(test.speed), sl.Get(test.status), speed)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cost
+
+import (
+	"sort"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Types of the resources costs are attributed to
+const (
+	ResourceVirtualGuest = "virtual_guest"
+	ResourceHardware     = "hardware"
+	ResourceVolume       = "volume"
+)
+
+const billingItemCostMask = "billingItem[id,nextInvoiceTotalRecurringAmount]"
+
+// ResourceCost is the monthly cost of a resource, which is the recurring
+// amount of its billing item, children included, on the next invoice
+type ResourceCost struct {
+	Type          string
+	Id            int
+	Name          string
+	BillingItemId int
+	Monthly       float64
+	Tags          []string
+}
+
+// Report attributes the monthly costs of the account to its resources.
+// Resources lists the resources with a billing item, most expensive first.
+// ByTag sums the costs of the resources per tag, a resource being counted for
+// each of its tags. Unattributed is the cost of the top level billing items
+// which could not be mapped to a resource, such as VLANs or licenses.
+type Report struct {
+	Resources    []ResourceCost
+	ByTag        map[string]float64
+	Unattributed float64
+	Total        float64
+}
+
+// GetReport returns the monthly costs of the virtual guests, hardware servers
+// and storage volumes of the account, per resource and per tag
+func GetReport(sess *session.Session) (Report, error) {
+	service := services.GetAccountService(sess)
+
+	guests, err := service.
+		Mask("id,fullyQualifiedDomainName,tagReferences[tag[name]]," + billingItemCostMask).
+		GetVirtualGuests()
+	if err != nil {
+		return Report{}, err
+	}
+
+	hardware, err := service.
+		Mask("id,fullyQualifiedDomainName,tagReferences[tag[name]]," + billingItemCostMask).
+		GetHardware()
+	if err != nil {
+		return Report{}, err
+	}
+
+	volumes, err := service.Mask("id,username," + billingItemCostMask).GetNetworkStorage()
+	if err != nil {
+		return Report{}, err
+	}
+
+	items, err := service.Mask("id,nextInvoiceTotalRecurringAmount").GetNextInvoiceTopLevelBillingItems()
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		Resources: []ResourceCost{},
+		ByTag:     map[string]float64{},
+	}
+
+	for _, guest := range guests {
+		report.add(ResourceVirtualGuest, sl.Get(guest.Id, 0).(int),
+			sl.Get(guest.FullyQualifiedDomainName, "").(string),
+			sl.Grab(guest, "BillingItem.Id", 0).(int),
+			sl.Grab(guest, "BillingItem.NextInvoiceTotalRecurringAmount", datatypes.Float64(0)).(datatypes.Float64),
+			tagNames(guest.TagReferences))
+	}
+
+	for _, hw := range hardware {
+		report.add(ResourceHardware, sl.Get(hw.Id, 0).(int),
+			sl.Get(hw.FullyQualifiedDomainName, "").(string),
+			sl.Grab(hw, "BillingItem.Id", 0).(int),
+			sl.Grab(hw, "BillingItem.NextInvoiceTotalRecurringAmount", datatypes.Float64(0)).(datatypes.Float64),
+			tagNames(hw.TagReferences))
+	}
+
+	for _, volume := range volumes {
+		report.add(ResourceVolume, sl.Get(volume.Id, 0).(int),
+			sl.Get(volume.Username, "").(string),
+			sl.Grab(volume, "BillingItem.Id", 0).(int),
+			sl.Grab(volume, "BillingItem.NextInvoiceTotalRecurringAmount", datatypes.Float64(0)).(datatypes.Float64),
+			[]string{})
+	}
+
+	attributed := map[int]bool{}
+	for _, resource := range report.Resources {
+		attributed[resource.BillingItemId] = true
+	}
+
+	for _, item := range items {
+		amount := float64(sl.Get(item.NextInvoiceTotalRecurringAmount, datatypes.Float64(0)).(datatypes.Float64))
+
+		report.Total += amount
+		if !attributed[sl.Get(item.Id, 0).(int)] {
+			report.Unattributed += amount
+		}
+	}
+
+	sort.SliceStable(report.Resources, func(i, j int) bool {
+		return report.Resources[i].Monthly > report.Resources[j].Monthly
+	})
+
+	return report, nil
+}
+
+// add attributes the cost of a billing item to a resource. Resources without
+// a billing item, such as the ones being provisioned, are skipped.
+func (r *Report) add(
+	resourceType string,
+	id int,
+	name string,
+	billingItemId int,
+	monthly datatypes.Float64,
+	tags []string,
+) {
+
+	if billingItemId == 0 {
+		return
+	}
+
+	r.Resources = append(r.Resources, ResourceCost{
+		Type:          resourceType,
+		Id:            id,
+		Name:          name,
+		BillingItemId: billingItemId,
+		Monthly:       float64(monthly),
+		Tags:          tags,
+	})
+
+	for _, tag := range tags {
+		r.ByTag[tag] += float64(monthly)
+	}
+}
+
+func tagNames(refs []datatypes.Tag_Reference) []string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if name := sl.Grab(ref, "Tag.Name", "").(string); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
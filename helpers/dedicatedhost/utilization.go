@@ -0,0 +1,122 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dedicatedhost
+
+import (
+	"sort"
+
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+const utilizationMask = "id,name,datacenter[name],backendRouter[hostname]," +
+	"allocationStatus,guests[id,fullyQualifiedDomainName,maxCpu,maxMemory]"
+
+// Capacity is the allocation of one resource of a dedicated host: cores for
+// the CPU, GB for the memory and the disk
+type Capacity struct {
+	Total     int
+	Allocated int
+	Available int
+}
+
+// Percent returns the allocated share of the capacity, as a percentage
+func (c Capacity) Percent() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+
+	return float64(c.Allocated) * 100 / float64(c.Total)
+}
+
+// Guest is a virtual guest placed on a dedicated host. Memory is in MB.
+type Guest struct {
+	Id       int
+	Hostname string
+	Cpu      int
+	Memory   int
+}
+
+// Utilization is the allocation of the resources of a dedicated host, along
+// with the guests placed on it
+type Utilization struct {
+	Id            int
+	Name          string
+	Datacenter    string
+	BackendRouter string
+	Cpu           Capacity
+	Memory        Capacity
+	Disk          Capacity
+	Guests        []Guest
+}
+
+// GetUtilization returns the allocation of the resources of each dedicated
+// host of the account, sorted by datacenter and name
+func GetUtilization(sess *session.Session) ([]Utilization, error) {
+	hosts, err := services.GetAccountService(sess).Mask(utilizationMask).GetDedicatedHosts()
+	if err != nil {
+		return nil, err
+	}
+
+	report := make([]Utilization, 0, len(hosts))
+	for _, host := range hosts {
+		utilization := Utilization{
+			Id:            sl.Get(host.Id, 0).(int),
+			Name:          sl.Get(host.Name, "").(string),
+			Datacenter:    sl.Grab(host, "Datacenter.Name", "").(string),
+			BackendRouter: sl.Grab(host, "BackendRouter.Hostname", "").(string),
+			Cpu: Capacity{
+				Total:     sl.Grab(host, "AllocationStatus.CpuCount", 0).(int),
+				Allocated: sl.Grab(host, "AllocationStatus.CpuAllocated", 0).(int),
+				Available: sl.Grab(host, "AllocationStatus.CpuAvailable", 0).(int),
+			},
+			Memory: Capacity{
+				Total:     sl.Grab(host, "AllocationStatus.MemoryCapacity", 0).(int),
+				Allocated: sl.Grab(host, "AllocationStatus.MemoryAllocated", 0).(int),
+				Available: sl.Grab(host, "AllocationStatus.MemoryAvailable", 0).(int),
+			},
+			Disk: Capacity{
+				Total:     sl.Grab(host, "AllocationStatus.DiskCapacity", 0).(int),
+				Allocated: sl.Grab(host, "AllocationStatus.DiskAllocated", 0).(int),
+				Available: sl.Grab(host, "AllocationStatus.DiskAvailable", 0).(int),
+			},
+			Guests: make([]Guest, 0, len(host.Guests)),
+		}
+
+		for _, guest := range host.Guests {
+			utilization.Guests = append(utilization.Guests, Guest{
+				Id:       sl.Get(guest.Id, 0).(int),
+				Hostname: sl.Get(guest.FullyQualifiedDomainName, "").(string),
+				Cpu:      sl.Get(guest.MaxCpu, 0).(int),
+				Memory:   sl.Get(guest.MaxMemory, 0).(int),
+			})
+		}
+
+		report = append(report, utilization)
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		if report[i].Datacenter != report[j].Datacenter {
+			return report[i].Datacenter < report[j].Datacenter
+		}
+
+		return report[i].Name < report[j].Name
+	})
+
+	return report, nil
+}
@@ -0,0 +1,202 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dns
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// TTL bounds, in seconds, of resource records. Records with no TTL are
+// created with DefaultTTL.
+const (
+	DefaultTTL = 900
+	MinTTL     = 60
+	MaxTTL     = 604800
+)
+
+// RecordMask is the default object mask for resource records
+const RecordMask = "id,domainId,host,type,data,ttl,mxPriority"
+
+// recordTypes lists the resource record types which can be managed, and
+// whether a host can hold several records of each type
+var recordTypes = map[string]bool{
+	"a":     true,
+	"aaaa":  true,
+	"cname": false,
+	"mx":    true,
+	"ns":    true,
+	"ptr":   false,
+	"spf":   true,
+	"txt":   true,
+}
+
+// Record describes a resource record. Type is one of "a", "aaaa", "cname",
+// "mx", "ns", "ptr", "spf" or "txt". MxPriority only applies to mx records.
+type Record struct {
+	Host       string
+	Type       string
+	Data       string
+	Ttl        int
+	MxPriority int
+}
+
+// EnsureZone returns the DNS zone of the account with the provided name,
+// creating it if it does not exist
+func EnsureZone(sess *session.Session, name string) (datatypes.Dns_Domain, error) {
+	service := services.GetDnsDomainService(sess)
+
+	zones, err := service.Mask("id,name,serial,updateDate").GetByDomainName(sl.String(name))
+	if err != nil {
+		return datatypes.Dns_Domain{}, err
+	}
+
+	// getByDomainName() matches parts of names
+	for _, zone := range zones {
+		if zone.Name != nil && strings.EqualFold(*zone.Name, name) {
+			return zone, nil
+		}
+	}
+
+	return service.CreateObject(&datatypes.Dns_Domain{Name: sl.String(name)})
+}
+
+// GetRecordsByName returns the resource records of the zone with the provided
+// id whose host is name (e.g. "www", or "@" for the zone apex). An object mask
+// can be provided as an optional argument, and RecordMask is used otherwise.
+func GetRecordsByName(sess *session.Session, zoneId int, name string, mask ...string) ([]datatypes.Dns_Domain_ResourceRecord, error) {
+	return getRecords(sess, zoneId, filter.Path("resourceRecords.host").Eq(name), mask...)
+}
+
+// GetRecordsByType returns the resource records of the zone with the provided
+// id of the provided type. An object mask can be provided as an optional
+// argument, and RecordMask is used otherwise.
+func GetRecordsByType(sess *session.Session, zoneId int, recordType string, mask ...string) ([]datatypes.Dns_Domain_ResourceRecord, error) {
+	return getRecords(sess, zoneId, filter.Path("resourceRecords.type").Eq(strings.ToLower(recordType)), mask...)
+}
+
+// CreateOrUpdateRecord creates the resource record in the zone with the
+// provided id, unless an equivalent record exists, in which case that record
+// is updated instead and returned. Records are equivalent when they share the
+// same host, type and data, or, for types a host can only hold one record of
+// (cname, ptr), the same host and type.
+func CreateOrUpdateRecord(sess *session.Session, zoneId int, record Record) (datatypes.Dns_Domain_ResourceRecord, error) {
+	record, err := validateRecord(record)
+	if err != nil {
+		return datatypes.Dns_Domain_ResourceRecord{}, err
+	}
+
+	existing, err := GetRecordsByName(sess, zoneId, record.Host)
+	if err != nil {
+		return datatypes.Dns_Domain_ResourceRecord{}, err
+	}
+
+	template := recordTemplate(zoneId, record)
+
+	for _, current := range existing {
+		if !strings.EqualFold(sl.Get(current.Type, "").(string), record.Type) {
+			continue
+		}
+
+		if recordTypes[record.Type] && sl.Get(current.Data, "").(string) != record.Data {
+			continue
+		}
+
+		if isUpToDate(current, template) {
+			return current, nil
+		}
+
+		template.Id = current.Id
+		if _, err := services.GetDnsDomainResourceRecordService(sess).Id(*current.Id).EditObject(&template); err != nil {
+			return datatypes.Dns_Domain_ResourceRecord{}, err
+		}
+
+		return template, nil
+	}
+
+	return services.GetDnsDomainResourceRecordService(sess).CreateObject(&template)
+}
+
+// DeleteRecord deletes the resource record with the provided id
+func DeleteRecord(sess *session.Session, recordId int) error {
+	_, err := services.GetDnsDomainResourceRecordService(sess).Id(recordId).DeleteObject()
+	return err
+}
+
+func getRecords(sess *session.Session, zoneId int, f filter.Filter, mask ...string) ([]datatypes.Dns_Domain_ResourceRecord, error) {
+	objectMask := RecordMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	return services.GetDnsDomainService(sess).
+		Id(zoneId).
+		Mask(objectMask).
+		Filter(filter.Build(f)).
+		GetResourceRecords()
+}
+
+// validateRecord checks the type and TTL of a record, and returns it with its
+// type lower cased and its TTL defaulted
+func validateRecord(record Record) (Record, error) {
+	record.Type = strings.ToLower(record.Type)
+	if _, ok := recordTypes[record.Type]; !ok {
+		return record, fmt.Errorf("Unsupported resource record type %s", record.Type)
+	}
+
+	if record.Host == "" || record.Data == "" {
+		return record, fmt.Errorf("A host and data are required for %s records", record.Type)
+	}
+
+	if record.Ttl == 0 {
+		record.Ttl = DefaultTTL
+	}
+
+	if record.Ttl < MinTTL || record.Ttl > MaxTTL {
+		return record, fmt.Errorf("TTL %d is out of bounds (%d to %d seconds)", record.Ttl, MinTTL, MaxTTL)
+	}
+
+	return record, nil
+}
+
+func recordTemplate(zoneId int, record Record) datatypes.Dns_Domain_ResourceRecord {
+	template := datatypes.Dns_Domain_ResourceRecord{
+		DomainId: sl.Int(zoneId),
+		Host:     sl.String(record.Host),
+		Type:     sl.String(record.Type),
+		Data:     sl.String(record.Data),
+		Ttl:      sl.Int(record.Ttl),
+	}
+
+	if record.Type == "mx" {
+		template.MxPriority = sl.Int(record.MxPriority)
+	}
+
+	return template
+}
+
+func isUpToDate(current datatypes.Dns_Domain_ResourceRecord, template datatypes.Dns_Domain_ResourceRecord) bool {
+	return sl.Get(current.Data, "").(string) == *template.Data &&
+		sl.Get(current.Ttl, 0).(int) == *template.Ttl &&
+		sl.Get(current.MxPriority, 0).(int) == sl.Get(template.MxPriority, 0).(int)
+}
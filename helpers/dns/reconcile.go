@@ -0,0 +1,187 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dns
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// batchSize is the maximum number of records sent in a single API call
+const batchSize = 50
+
+// Plan holds the changes needed to reconcile the records of a zone with the
+// desired ones. Updated and deleted records carry their id.
+type Plan struct {
+	Create []datatypes.Dns_Domain_ResourceRecord
+	Update []datatypes.Dns_Domain_ResourceRecord
+	Delete []datatypes.Dns_Domain_ResourceRecord
+}
+
+// Empty reports whether the zone already holds the desired records
+func (p Plan) Empty() bool {
+	return len(p.Create) == 0 && len(p.Update) == 0 && len(p.Delete) == 0
+}
+
+// PlanRecords compares the records of the zone with the provided id to the
+// full desired record set, and returns the changes needed to reconcile them.
+// Records are matched as in CreateOrUpdateRecord. Records of unsupported types
+// (e.g. soa) and the NS records of the zone apex, which are managed by
+// SoftLayer, are left out of the comparison.
+func PlanRecords(sess *session.Session, zoneId int, desired []Record) (Plan, error) {
+	current, err := services.GetDnsDomainService(sess).
+		Id(zoneId).
+		Mask(RecordMask).
+		GetResourceRecords()
+	if err != nil {
+		return Plan{}, err
+	}
+
+	return planRecords(zoneId, current, desired)
+}
+
+// planRecords returns the changes needed to reconcile the current records of
+// the zone with the provided id with the desired ones, see PlanRecords
+func planRecords(zoneId int, current []datatypes.Dns_Domain_ResourceRecord, desired []Record) (Plan, error) {
+	existing := map[string][]datatypes.Dns_Domain_ResourceRecord{}
+	for _, record := range current {
+		recordType := strings.ToLower(sl.Get(record.Type, "").(string))
+		host := sl.Get(record.Host, "").(string)
+		if _, ok := recordTypes[recordType]; !ok || (recordType == "ns" && host == "@") {
+			continue
+		}
+
+		key := recordKey(host, recordType, sl.Get(record.Data, "").(string))
+		existing[key] = append(existing[key], record)
+	}
+
+	plan := Plan{
+		Create: []datatypes.Dns_Domain_ResourceRecord{},
+		Update: []datatypes.Dns_Domain_ResourceRecord{},
+		Delete: []datatypes.Dns_Domain_ResourceRecord{},
+	}
+	wanted := map[string]bool{}
+
+	for _, record := range desired {
+		record, err := validateRecord(record)
+		if err != nil {
+			return Plan{}, err
+		}
+
+		key := recordKey(record.Host, record.Type, record.Data)
+		if wanted[key] {
+			return Plan{}, fmt.Errorf("Duplicate %s record %s in the desired records", record.Type, record.Host)
+		}
+		wanted[key] = true
+
+		template := recordTemplate(zoneId, record)
+
+		matches := existing[key]
+		if len(matches) == 0 {
+			plan.Create = append(plan.Create, template)
+			continue
+		}
+
+		// Extra equivalent records are deleted below
+		existing[key] = matches[1:]
+
+		if !isUpToDate(matches[0], template) {
+			template.Id = matches[0].Id
+			plan.Update = append(plan.Update, template)
+		}
+	}
+
+	// Deletions are listed in the order of the zone's records
+	unmatched := map[int]bool{}
+	for _, records := range existing {
+		for _, record := range records {
+			unmatched[sl.Get(record.Id, 0).(int)] = true
+		}
+	}
+
+	for _, record := range current {
+		if unmatched[sl.Get(record.Id, 0).(int)] {
+			plan.Delete = append(plan.Delete, record)
+		}
+	}
+
+	return plan, nil
+}
+
+// ReconcileRecords makes the records of the zone with the provided id match
+// the full desired record set, see PlanRecords, and returns the changes made.
+// When dryRun is set, the changes are only planned and returned.
+//
+// Changes are applied in batches, deletions first so that a record replaced by
+// one of a type a host can only hold one record of (cname, ptr) is out of the
+// way beforehand.
+func ReconcileRecords(sess *session.Session, zoneId int, desired []Record, dryRun bool) (Plan, error) {
+	plan, err := PlanRecords(sess, zoneId, desired)
+	if err != nil || dryRun {
+		return plan, err
+	}
+
+	service := services.GetDnsDomainResourceRecordService(sess)
+
+	for _, batch := range batches(plan.Delete) {
+		if _, err := service.DeleteObjects(batch); err != nil {
+			return plan, fmt.Errorf("Error deleting resource records: %s", err)
+		}
+	}
+
+	for _, batch := range batches(plan.Update) {
+		if _, err := service.EditObjects(batch); err != nil {
+			return plan, fmt.Errorf("Error updating resource records: %s", err)
+		}
+	}
+
+	for _, batch := range batches(plan.Create) {
+		if _, err := service.CreateObjects(batch); err != nil {
+			return plan, fmt.Errorf("Error creating resource records: %s", err)
+		}
+	}
+
+	return plan, nil
+}
+
+// recordKey identifies equivalent records, see CreateOrUpdateRecord
+func recordKey(host string, recordType string, data string) string {
+	if !recordTypes[recordType] {
+		data = ""
+	}
+
+	return strings.ToLower(host) + " " + recordType + " " + data
+}
+
+func batches(records []datatypes.Dns_Domain_ResourceRecord) [][]datatypes.Dns_Domain_ResourceRecord {
+	result := [][]datatypes.Dns_Domain_ResourceRecord{}
+	for len(records) > batchSize {
+		result = append(result, records[:batchSize])
+		records = records[batchSize:]
+	}
+
+	if len(records) > 0 {
+		result = append(result, records)
+	}
+
+	return result
+}
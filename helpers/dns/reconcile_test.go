@@ -0,0 +1,130 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dns
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+func resourceRecord(id int, host string, recordType string, data string, ttl int) datatypes.Dns_Domain_ResourceRecord {
+	return datatypes.Dns_Domain_ResourceRecord{
+		Id:   sl.Int(id),
+		Host: sl.String(host),
+		Type: sl.String(recordType),
+		Data: sl.String(data),
+		Ttl:  sl.Int(ttl),
+	}
+}
+
+func recordIds(records []datatypes.Dns_Domain_ResourceRecord) []int {
+	ids := []int{}
+	for _, record := range records {
+		ids = append(ids, sl.Get(record.Id, 0).(int))
+	}
+
+	return ids
+}
+
+func TestPlanRecords(t *testing.T) {
+	current := []datatypes.Dns_Domain_ResourceRecord{
+		resourceRecord(1, "@", "ns", "ns1.softlayer.com.", 86400),
+		resourceRecord(2, "@", "soa", "ns1.softlayer.com. root.example.com.", 86400),
+		resourceRecord(3, "www", "a", "10.0.0.1", 900),
+		resourceRecord(4, "www", "a", "10.0.0.2", 900),
+		resourceRecord(5, "api", "cname", "www.example.com.", 900),
+		resourceRecord(6, "old", "a", "10.0.0.3", 900),
+		resourceRecord(7, "WWW", "a", "10.0.0.1", 900),
+	}
+
+	tests := []struct {
+		description string
+		desired     []Record
+		create      []string
+		update      []int
+		delete      []int
+	}{
+		{
+			description: "unchanged records, with the apex NS and SOA records left out",
+			desired: []Record{
+				{Host: "www", Type: "a", Data: "10.0.0.1"},
+				{Host: "www", Type: "A", Data: "10.0.0.2", Ttl: 900},
+				{Host: "api", Type: "cname", Data: "www.example.com."},
+				{Host: "old", Type: "a", Data: "10.0.0.3"},
+			},
+			create: []string{},
+			update: []int{},
+			delete: []int{7},
+		},
+		{
+			description: "created, updated and deleted records",
+			desired: []Record{
+				{Host: "www", Type: "a", Data: "10.0.0.1", Ttl: 300},
+				{Host: "api", Type: "cname", Data: "lb.example.com."},
+				{Host: "new", Type: "aaaa", Data: "::1"},
+			},
+			create: []string{"new"},
+			update: []int{3, 5},
+			delete: []int{4, 6, 7},
+		},
+	}
+
+	for _, test := range tests {
+		plan, err := planRecords(123, current, test.desired)
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.description, err)
+			continue
+		}
+
+		created := []string{}
+		for _, record := range plan.Create {
+			created = append(created, *record.Host)
+		}
+
+		if !reflect.DeepEqual(created, test.create) {
+			t.Errorf("%s: expected to create %v, got %v", test.description, test.create, created)
+		}
+
+		if updated := recordIds(plan.Update); !reflect.DeepEqual(updated, test.update) {
+			t.Errorf("%s: expected to update %v, got %v", test.description, test.update, updated)
+		}
+
+		if deleted := recordIds(plan.Delete); !reflect.DeepEqual(deleted, test.delete) {
+			t.Errorf("%s: expected to delete %v, got %v", test.description, test.delete, deleted)
+		}
+	}
+}
+
+func TestPlanRecordsErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		desired     []Record
+	}{
+		{"duplicate records", []Record{{Host: "www", Type: "a", Data: "10.0.0.1"}, {Host: "WWW", Type: "a", Data: "10.0.0.1"}}},
+		{"unsupported type", []Record{{Host: "www", Type: "srv", Data: "10.0.0.1"}}},
+		{"TTL out of bounds", []Record{{Host: "www", Type: "a", Data: "10.0.0.1", Ttl: 1}}},
+	}
+
+	for _, test := range tests {
+		if _, err := planRecords(123, nil, test.desired); err == nil {
+			t.Errorf("%s: expected an error", test.description)
+		}
+	}
+}
@@ -0,0 +1,362 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dns
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+)
+
+// Zone is the content of a BIND zone file. Skipped holds the records which
+// are not imported: the SOA and apex NS records, which are managed by
+// SoftLayer, and the records of unsupported types.
+type Zone struct {
+	Origin  string
+	Records []Record
+	Skipped []string
+}
+
+// ExportZone returns the BIND zone file of the zone with the provided id
+func ExportZone(sess *session.Session, zoneId int) (string, error) {
+	return services.GetDnsDomainService(sess).Id(zoneId).GetZoneFileContents()
+}
+
+// ImportZone creates the zone described by the BIND zone file text, along with
+// its records, then returns it with the parsed zone. Records already present in
+// the zone are updated rather than duplicated, see CreateOrUpdateRecord.
+func ImportZone(sess *session.Session, text string) (datatypes.Dns_Domain, Zone, error) {
+	zone, err := ParseZone(text)
+	if err != nil {
+		return datatypes.Dns_Domain{}, Zone{}, err
+	}
+
+	domain, err := EnsureZone(sess, zone.Origin)
+	if err != nil {
+		return datatypes.Dns_Domain{}, zone, err
+	}
+
+	for _, record := range zone.Records {
+		if _, err := CreateOrUpdateRecord(sess, *domain.Id, record); err != nil {
+			return domain, zone, fmt.Errorf("Error importing %s record %s: %s", record.Type, record.Host, err)
+		}
+	}
+
+	return domain, zone, nil
+}
+
+// ParseZone parses a BIND zone file. The origin of the zone is read from the
+// $ORIGIN directive, or else from the owner of the SOA record. Hosts are made
+// relative to the origin, with "@" standing for the origin itself.
+func ParseZone(text string) (Zone, error) {
+	zone := Zone{Records: []Record{}, Skipped: []string{}}
+
+	lines, err := zoneLines(text)
+	if err != nil {
+		return zone, err
+	}
+
+	defaultTtl := 0
+	lastOwner := ""
+
+	for _, line := range lines {
+		fields := splitFields(line.text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "$ORIGIN":
+			if len(fields) < 2 {
+				return zone, fmt.Errorf("Line %d: missing origin", line.number)
+			}
+			zone.Origin = strings.TrimSuffix(fields[1], ".")
+			continue
+		case "$TTL":
+			if len(fields) < 2 {
+				return zone, fmt.Errorf("Line %d: missing TTL", line.number)
+			}
+			if defaultTtl, err = parseTtl(fields[1]); err != nil {
+				return zone, fmt.Errorf("Line %d: invalid TTL %s", line.number, fields[1])
+			}
+			continue
+		case "$INCLUDE", "$GENERATE":
+			return zone, fmt.Errorf("Line %d: %s directives are not supported", line.number, fields[0])
+		}
+
+		// Lines starting with blanks belong to the previous owner
+		owner := lastOwner
+		if !line.continued {
+			owner, fields = fields[0], fields[1:]
+		}
+		lastOwner = owner
+
+		ttl := defaultTtl
+		for len(fields) > 0 {
+			if n, err := parseTtl(fields[0]); err == nil {
+				ttl, fields = n, fields[1:]
+			} else if strings.EqualFold(fields[0], "IN") {
+				fields = fields[1:]
+			} else {
+				break
+			}
+		}
+
+		if len(fields) < 2 {
+			return zone, fmt.Errorf("Line %d: incomplete record", line.number)
+		}
+
+		recordType := strings.ToLower(fields[0])
+		data := fields[1:]
+
+		if recordType == "soa" {
+			if zone.Origin == "" && owner != "@" {
+				zone.Origin = strings.TrimSuffix(owner, ".")
+			}
+			zone.Skipped = append(zone.Skipped, line.text)
+			continue
+		}
+
+		if _, ok := recordTypes[recordType]; !ok {
+			zone.Skipped = append(zone.Skipped, line.text)
+			continue
+		}
+
+		record := Record{Host: owner, Type: recordType, Ttl: ttl}
+
+		switch recordType {
+		case "mx":
+			if len(data) < 2 {
+				return zone, fmt.Errorf("Line %d: MX records need a priority and a host", line.number)
+			}
+			if record.MxPriority, err = strconv.Atoi(data[0]); err != nil {
+				return zone, fmt.Errorf("Line %d: invalid MX priority %s", line.number, data[0])
+			}
+			record.Data = data[1]
+		case "txt", "spf":
+			parts := make([]string, 0, len(data))
+			for _, part := range data {
+				parts = append(parts, strings.Trim(part, `"`))
+			}
+			record.Data = strings.Join(parts, "")
+		default:
+			record.Data = strings.Join(data, " ")
+		}
+
+		zone.Records = append(zone.Records, record)
+	}
+
+	if zone.Origin == "" {
+		return zone, fmt.Errorf("No origin found in the zone file")
+	}
+
+	// Owners are only made relative once the origin is known, as it may come
+	// from the SOA record
+	records := zone.Records
+	zone.Records = []Record{}
+	for _, record := range records {
+		record.Host = relativeHost(record.Host, zone.Origin)
+
+		if record.Type == "ns" && record.Host == "@" {
+			zone.Skipped = append(zone.Skipped, fmt.Sprintf("@ %d IN NS %s", record.Ttl, record.Data))
+			continue
+		}
+
+		zone.Records = append(zone.Records, record)
+	}
+
+	return zone, nil
+}
+
+type zoneLine struct {
+	number    int
+	text      string
+	continued bool
+}
+
+// zoneLines returns the logical lines of a zone file, with comments removed,
+// parenthesized records joined on a single line, and fields separated by a
+// single blank
+func zoneLines(text string) ([]zoneLine, error) {
+	lines := []zoneLine{}
+
+	var current *zoneLine
+	depth := 0
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for number := 1; scanner.Scan(); number++ {
+		raw := stripComment(scanner.Text())
+
+		if current == nil {
+			if strings.TrimSpace(raw) == "" {
+				continue
+			}
+
+			current = &zoneLine{
+				number:    number,
+				continued: raw[0] == ' ' || raw[0] == '\t',
+			}
+		}
+
+		// Parentheses within quoted strings are part of the data
+		quoted := false
+		cleaned := []rune(raw)
+		for i, c := range cleaned {
+			switch {
+			case c == '"':
+				quoted = !quoted
+			case c == '(' && !quoted:
+				depth++
+				cleaned[i] = ' '
+			case c == ')' && !quoted:
+				depth--
+				cleaned[i] = ' '
+			}
+		}
+
+		current.text = strings.Join(splitFields(current.text+" "+string(cleaned)), " ")
+
+		if depth == 0 {
+			lines = append(lines, *current)
+			current = nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("Line %d: unbalanced parentheses", current.number)
+	}
+
+	return lines, nil
+}
+
+// Seconds of the units of TTLs
+var ttlUnits = map[byte]int{
+	's': 1,
+	'm': 60,
+	'h': 60 * 60,
+	'd': 24 * 60 * 60,
+	'w': 7 * 24 * 60 * 60,
+}
+
+// parseTtl parses a TTL in seconds, or made of numbers followed by units
+// (e.g. "1h30m", "1d"), as BIND does
+func parseTtl(value string) (int, error) {
+	if n, err := strconv.Atoi(value); err == nil {
+		return n, nil
+	}
+
+	ttl, number := 0, ""
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c >= '0' && c <= '9' {
+			number += string(c)
+			continue
+		}
+
+		unit, ok := ttlUnits[c|0x20]
+		if !ok || number == "" {
+			return 0, fmt.Errorf("Invalid TTL %s", value)
+		}
+
+		n, _ := strconv.Atoi(number)
+		ttl, number = ttl+n*unit, ""
+	}
+
+	if number != "" || value == "" {
+		return 0, fmt.Errorf("Invalid TTL %s", value)
+	}
+
+	return ttl, nil
+}
+
+// stripComment removes the comment ending a line, ignoring semicolons within
+// quoted strings
+func stripComment(line string) string {
+	quoted := false
+	for i, c := range line {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == ';' && !quoted:
+			return line[:i]
+		}
+	}
+
+	return line
+}
+
+// splitFields splits a line on blanks, keeping quoted strings whole
+func splitFields(line string) []string {
+	fields := []string{}
+	current := ""
+	quoted := false
+
+	for _, c := range line {
+		switch {
+		case c == '"':
+			quoted = !quoted
+			current += string(c)
+		case (c == ' ' || c == '\t') && !quoted:
+			if current != "" {
+				fields = append(fields, current)
+				current = ""
+			}
+		default:
+			current += string(c)
+		}
+	}
+
+	if current != "" {
+		fields = append(fields, current)
+	}
+
+	return fields
+}
+
+// relativeHost returns an owner name relative to the origin
+func relativeHost(owner string, origin string) string {
+	if owner == "@" || owner == "" || origin == "" {
+		if owner == "" {
+			return "@"
+		}
+		return owner
+	}
+
+	if !strings.HasSuffix(owner, ".") {
+		return owner
+	}
+
+	name := strings.TrimSuffix(owner, ".")
+	if strings.EqualFold(name, origin) {
+		return "@"
+	}
+
+	if strings.HasSuffix(strings.ToLower(name), "."+strings.ToLower(origin)) {
+		return name[:len(name)-len(origin)-1]
+	}
+
+	return owner
+}
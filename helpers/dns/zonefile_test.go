@@ -0,0 +1,166 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dns
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseZone(t *testing.T) {
+	tests := []struct {
+		description string
+		text        string
+		expected    Zone
+	}{
+		{
+			description: "$TTL and $ORIGIN directives",
+			text: `$ORIGIN example.com.
+$TTL 3600
+www      IN A     10.0.0.1
+mail 300 IN A     10.0.0.2
+         IN AAAA  ::1
+@           MX 10 mail.example.com.
+`,
+			expected: Zone{
+				Origin: "example.com",
+				Records: []Record{
+					{Host: "www", Type: "a", Data: "10.0.0.1", Ttl: 3600},
+					{Host: "mail", Type: "a", Data: "10.0.0.2", Ttl: 300},
+					{Host: "mail", Type: "aaaa", Data: "::1", Ttl: 3600},
+					{Host: "@", Type: "mx", Data: "mail.example.com.", Ttl: 3600, MxPriority: 10},
+				},
+				Skipped: []string{},
+			},
+		},
+		{
+			description: "TTLs with units",
+			text: `$ORIGIN example.com.
+$TTL 1h
+www    IN A 10.0.0.1
+api 1d IN A 10.0.0.2
+ftp 1h30M IN A 10.0.0.3
+`,
+			expected: Zone{
+				Origin: "example.com",
+				Records: []Record{
+					{Host: "www", Type: "a", Data: "10.0.0.1", Ttl: 3600},
+					{Host: "api", Type: "a", Data: "10.0.0.2", Ttl: 86400},
+					{Host: "ftp", Type: "a", Data: "10.0.0.3", Ttl: 5400},
+				},
+				Skipped: []string{},
+			},
+		},
+		{
+			description: "multi-line SOA giving the origin, and apex NS records",
+			text: `example.com. 86400 IN SOA ns1.softlayer.com. root.example.com. (
+        2016102401 ; serial
+        7200       ; refresh
+        600        ; retry
+        1728000    ; expire
+        43200 )    ; minimum
+example.com. 86400 IN NS ns1.softlayer.com.
+www.example.com. 900 IN CNAME example.com.
+`,
+			expected: Zone{
+				Origin: "example.com",
+				Records: []Record{
+					{Host: "www", Type: "cname", Data: "example.com.", Ttl: 900},
+				},
+				Skipped: []string{
+					"example.com. 86400 IN SOA ns1.softlayer.com. root.example.com. 2016102401 7200 600 1728000 43200",
+					"@ 86400 IN NS ns1.softlayer.com.",
+				},
+			},
+		},
+		{
+			description: "quoted TXT data",
+			text: `$ORIGIN example.com.
+@ 300 IN TXT "v=spf1 include:example.net ~all ; not a comment"
+txt 300 IN TXT ( "first part (with parentheses) "
+                 "second part" )
+sad 300 IN TXT "unbalanced ) within quotes"
+`,
+			expected: Zone{
+				Origin: "example.com",
+				Records: []Record{
+					{Host: "@", Type: "txt", Data: "v=spf1 include:example.net ~all ; not a comment", Ttl: 300},
+					{Host: "txt", Type: "txt", Data: "first part (with parentheses) second part", Ttl: 300},
+					{Host: "sad", Type: "txt", Data: "unbalanced ) within quotes", Ttl: 300},
+				},
+				Skipped: []string{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		zone, err := ParseZone(test.text)
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.description, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(zone, test.expected) {
+			t.Errorf("%s: expected %+v, got %+v", test.description, test.expected, zone)
+		}
+	}
+}
+
+func TestParseZoneErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		text        string
+	}{
+		{"no origin", "www 300 IN A 10.0.0.1\n"},
+		{"invalid TTL", "$ORIGIN example.com.\n$TTL 1x\n"},
+		{"unbalanced parentheses", "$ORIGIN example.com.\n@ IN SOA ns1. root. ( 1 2 3 4\n"},
+		{"unsupported directive", "$INCLUDE other.zone\n"},
+	}
+
+	for _, test := range tests {
+		if _, err := ParseZone(test.text); err == nil {
+			t.Errorf("%s: expected an error", test.description)
+		}
+	}
+}
+
+func TestParseTtl(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int
+		valid    bool
+	}{
+		{"3600", 3600, true},
+		{"30s", 30, true},
+		{"5m", 300, true},
+		{"1h", 3600, true},
+		{"1d", 86400, true},
+		{"1W", 604800, true},
+		{"1h30m", 5400, true},
+		{"", 0, false},
+		{"h", 0, false},
+		{"1x", 0, false},
+		{"1h30", 0, false},
+	}
+
+	for _, test := range tests {
+		ttl, err := parseTtl(test.value)
+		if (err == nil) != test.valid || ttl != test.expected {
+			t.Errorf("Expected %d (valid: %t) for %q, got %d (error: %v)", test.expected, test.valid, test.value, ttl, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package email
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// SMTP endpoint of the SendGrid email delivery accounts
+const (
+	SmtpHost = "smtp.sendgrid.net"
+	SmtpPort = 587
+)
+
+// DefaultDeliveryAccountMask is the default object mask for email delivery
+// accounts
+const DefaultDeliveryAccountMask = "id,username,createDate,type[keyName,name],vendor[keyName,name]"
+
+// Credentials holds what a workload needs to send email through a delivery
+// account. SmtpAccess reports whether the account accepts SMTP connections.
+type Credentials struct {
+	Id              int
+	Username        string
+	Password        string
+	EmailAddress    string
+	SmtpHost        string
+	SmtpPort        int
+	SmtpAccess      bool
+	VendorPortalUrl string
+}
+
+// ListDeliveryAccounts returns the email delivery accounts of the account. An
+// object mask can be provided as an optional argument, and
+// DefaultDeliveryAccountMask is used otherwise.
+func ListDeliveryAccounts(sess *session.Session, mask ...string) ([]datatypes.Network_Message_Delivery, error) {
+	objectMask := DefaultDeliveryAccountMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	return services.GetAccountService(sess).Mask(objectMask).GetNetworkMessageDeliveryAccounts()
+}
+
+// GetCredentials returns the credentials and SMTP endpoint of the email
+// delivery account with the provided id
+func GetCredentials(sess *session.Session, deliveryAccountId int) (Credentials, error) {
+	service := services.GetNetworkMessageDeliveryEmailSendgridService(sess).Id(deliveryAccountId)
+
+	account, err := service.Mask("id,username,password,emailAddress,smtpAccess").GetObject()
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	url, err := service.GetVendorPortalUrl()
+	if err != nil {
+		return Credentials{}, fmt.Errorf("Error getting the portal URL of email delivery account %d: %s", deliveryAccountId, err)
+	}
+
+	// smtpAccess is a flag returned as a string
+	return Credentials{
+		Id:              deliveryAccountId,
+		Username:        sl.Get(account.Username, "").(string),
+		Password:        sl.Get(account.Password, "").(string),
+		EmailAddress:    sl.Get(account.EmailAddress, "").(string),
+		SmtpHost:        SmtpHost,
+		SmtpPort:        SmtpPort,
+		SmtpAccess:      sl.Get(account.SmtpAccess, "").(string) == "1",
+		VendorPortalUrl: url,
+	}, nil
+}
+
+// UpdatePassword sets the password of the email delivery account with the
+// provided id
+func UpdatePassword(sess *session.Session, deliveryAccountId int, password string) error {
+	_, err := services.GetNetworkMessageDeliveryEmailSendgridService(sess).
+		Id(deliveryAccountId).
+		EditObject(&datatypes.Network_Message_Delivery{Password: sl.String(password)})
+	if err != nil {
+		return fmt.Errorf("Error updating the password of email delivery account %d: %s", deliveryAccountId, err)
+	}
+
+	return nil
+}
+
+// UpdateEmailAddress sets the email address of the email delivery account
+// with the provided id
+func UpdateEmailAddress(sess *session.Session, deliveryAccountId int, emailAddress string) error {
+	_, err := services.GetNetworkMessageDeliveryEmailSendgridService(sess).
+		Id(deliveryAccountId).
+		UpdateEmailAddress(sl.String(emailAddress))
+	return err
+}
+
+// SetSmtpAccess enables or disables SMTP access to the email delivery account
+// with the provided id
+func SetSmtpAccess(sess *session.Session, deliveryAccountId int, enabled bool) error {
+	service := services.GetNetworkMessageDeliveryEmailSendgridService(sess).Id(deliveryAccountId)
+
+	var err error
+	if enabled {
+		_, err = service.EnableSmtpAccess()
+	} else {
+		_, err = service.DisableSmtpAccess()
+	}
+
+	return err
+}
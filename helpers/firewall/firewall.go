@@ -0,0 +1,349 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DefaultPollInterval is the time waited between two checks of a firewall
+// update request, when UpdateSharedFirewall or UpdateDedicatedFirewall is
+// passed a zero interval. Both return the context's error once their context
+// is done, without waiting further.
+const DefaultPollInterval = 30 * time.Second
+
+// Directions of the access control lists of a dedicated firewall
+const (
+	DirectionIn  = "in"
+	DirectionOut = "out"
+)
+
+const ruleMask = "id,orderValue,action,protocol,sourceIpAddress,sourceIpCidr," +
+	"destinationIpAddress,destinationIpCidr,destinationPortRangeStart,destinationPortRangeEnd,version,notes"
+
+// Rule is a firewall rule. Action is "permit" or "deny", and Protocol one of
+// "tcp", "udp", "icmp", "gre", "pptp", "ah" or "esp". Version is the IP
+// version the rule applies to, and defaults to 4.
+type Rule struct {
+	Action               string
+	Protocol             string
+	SourceIpAddress      string
+	SourceIpCidr         int
+	DestinationIpAddress string
+	DestinationIpCidr    int
+	DestinationPortStart int
+	DestinationPortEnd   int
+	Version              int
+	Notes                string
+}
+
+// Diff holds the differences between the current rules of a firewall and the
+// desired ones. Reordered is set when both hold the same rules in a
+// different order, which matters as rules are evaluated in order.
+type Diff struct {
+	Added     []Rule
+	Removed   []Rule
+	Reordered bool
+}
+
+// Empty reports whether the firewall already holds the desired rules
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && !d.Reordered
+}
+
+// DiffRules compares the current rules of a firewall to the desired ones
+func DiffRules(current []Rule, desired []Rule) Diff {
+	diff := Diff{Added: []Rule{}, Removed: []Rule{}}
+
+	counts := map[Rule]int{}
+	for _, rule := range current {
+		counts[rule.normalize()]++
+	}
+
+	for _, rule := range desired {
+		if counts[rule.normalize()] > 0 {
+			counts[rule.normalize()]--
+			continue
+		}
+		diff.Added = append(diff.Added, rule)
+	}
+
+	for _, rule := range current {
+		if counts[rule.normalize()] > 0 {
+			counts[rule.normalize()]--
+			diff.Removed = append(diff.Removed, rule)
+		}
+	}
+
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 {
+		for i := range current {
+			if current[i].normalize() != desired[i].normalize() {
+				diff.Reordered = true
+				break
+			}
+		}
+	}
+
+	return diff
+}
+
+// GetSharedFirewallRules returns the rules of the shared (per component)
+// firewall with the provided id, in evaluation order
+func GetSharedFirewallRules(sess *session.Session, firewallId int) ([]Rule, error) {
+	rules, err := services.GetNetworkComponentFirewallService(sess).
+		Id(firewallId).
+		Mask(ruleMask).
+		GetRules()
+	if err != nil {
+		return nil, err
+	}
+
+	converted := make([]datatypes.Network_Vlan_Firewall_Rule, 0, len(rules))
+	for _, rule := range rules {
+		// Both rule types share the same properties
+		converted = append(converted, datatypes.Network_Vlan_Firewall_Rule(rule))
+	}
+
+	return rulesOf(converted), nil
+}
+
+// GetDedicatedFirewallRules returns the rules of the access control list of
+// the dedicated firewall of the VLAN with the provided id, for the provided
+// direction of traffic on the outside interface, in evaluation order. The id of
+// the access control list is returned along with the rules.
+func GetDedicatedFirewallRules(sess *session.Session, vlanId int, direction string) (int, []Rule, error) {
+	aclId, err := getAccessControlListId(sess, vlanId, direction)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	rules, err := services.GetNetworkFirewallAccessControlListService(sess).
+		Id(aclId).
+		Mask(ruleMask).
+		GetRules()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return aclId, rulesOf(rules), nil
+}
+
+// UpdateSharedFirewall replaces the rules of the shared firewall with the
+// provided id with desired, and waits until the update is applied. Nothing is
+// submitted when the firewall already holds the desired rules. The differences
+// between the previous and desired rules are returned.
+func UpdateSharedFirewall(
+	ctx context.Context,
+	sess *session.Session,
+	firewallId int,
+	desired []Rule,
+	interval time.Duration,
+) (Diff, error) {
+
+	current, err := GetSharedFirewallRules(sess, firewallId)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	diff := DiffRules(current, desired)
+	if diff.Empty() {
+		return diff, nil
+	}
+
+	request := datatypes.Network_Firewall_Update_Request{
+		NetworkComponentFirewallId: sl.Int(firewallId),
+		Rules:                      updateRequestRules(desired),
+	}
+
+	return diff, submitUpdateRequest(ctx, sess, request, interval)
+}
+
+// UpdateDedicatedFirewall is like UpdateSharedFirewall, for the access control
+// list of the dedicated firewall of the VLAN with the provided id, for the
+// provided direction of traffic on the outside interface
+func UpdateDedicatedFirewall(
+	ctx context.Context,
+	sess *session.Session,
+	vlanId int,
+	direction string,
+	desired []Rule,
+	interval time.Duration,
+) (Diff, error) {
+
+	aclId, current, err := GetDedicatedFirewallRules(sess, vlanId, direction)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	diff := DiffRules(current, desired)
+	if diff.Empty() {
+		return diff, nil
+	}
+
+	request := datatypes.Network_Firewall_Update_Request{
+		FirewallContextAccessControlListId: sl.Int(aclId),
+		Rules:                              updateRequestRules(desired),
+	}
+
+	return diff, submitUpdateRequest(ctx, sess, request, interval)
+}
+
+// submitUpdateRequest creates the firewall update request, and polls it until
+// it is applied
+func submitUpdateRequest(
+	ctx context.Context,
+	sess *session.Session,
+	request datatypes.Network_Firewall_Update_Request,
+	interval time.Duration,
+) error {
+
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	service := services.GetNetworkFirewallUpdateRequestService(sess)
+
+	created, err := service.CreateObject(&request)
+	if err != nil {
+		return fmt.Errorf("Error submitting firewall update request: %s", err)
+	}
+
+	for {
+		request, err := service.Id(*created.Id).Mask("id,applyDate").GetObject()
+		if err != nil {
+			return err
+		}
+
+		if request.ApplyDate != nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// getAccessControlListId returns the id of the access control list of the
+// outside interface of the dedicated firewall of a VLAN, for a direction
+func getAccessControlListId(sess *session.Session, vlanId int, direction string) (int, error) {
+	vlan, err := services.GetNetworkVlanService(sess).
+		Id(vlanId).
+		Mask("id,dedicatedFirewallFlag,firewallInterfaces[name,firewallContextAccessControlLists[id,direction]]").
+		GetObject()
+	if err != nil {
+		return 0, err
+	}
+
+	if sl.Get(vlan.DedicatedFirewallFlag, 0).(int) != 1 {
+		return 0, fmt.Errorf("VLAN %d has no dedicated firewall", vlanId)
+	}
+
+	for _, iface := range vlan.FirewallInterfaces {
+		if sl.Get(iface.Name, "").(string) != "outside" {
+			continue
+		}
+
+		for _, acl := range iface.FirewallContextAccessControlLists {
+			if sl.Get(acl.Direction, "").(string) == direction && acl.Id != nil {
+				return *acl.Id, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("No %s access control list found on the dedicated firewall of VLAN %d", direction, vlanId)
+}
+
+func rulesOf(rules []datatypes.Network_Vlan_Firewall_Rule) []Rule {
+	ordered := make([]datatypes.Network_Vlan_Firewall_Rule, len(rules))
+	copy(ordered, rules)
+
+	// Rules are not guaranteed to be returned in evaluation order
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return sl.Get(ordered[i].OrderValue, 0).(int) < sl.Get(ordered[j].OrderValue, 0).(int)
+	})
+
+	result := make([]Rule, 0, len(ordered))
+	for _, rule := range ordered {
+		result = append(result, Rule{
+			Action:               sl.Get(rule.Action, "").(string),
+			Protocol:             sl.Get(rule.Protocol, "").(string),
+			SourceIpAddress:      sl.Get(rule.SourceIpAddress, "").(string),
+			SourceIpCidr:         sl.Get(rule.SourceIpCidr, 0).(int),
+			DestinationIpAddress: sl.Get(rule.DestinationIpAddress, "").(string),
+			DestinationIpCidr:    sl.Get(rule.DestinationIpCidr, 0).(int),
+			DestinationPortStart: sl.Get(rule.DestinationPortRangeStart, 0).(int),
+			DestinationPortEnd:   sl.Get(rule.DestinationPortRangeEnd, 0).(int),
+			Version:              sl.Get(rule.Version, 4).(int),
+			Notes:                sl.Get(rule.Notes, "").(string),
+		})
+	}
+
+	return result
+}
+
+func updateRequestRules(rules []Rule) []datatypes.Network_Firewall_Update_Request_Rule {
+	result := make([]datatypes.Network_Firewall_Update_Request_Rule, 0, len(rules))
+	for i, r := range rules {
+		r = r.normalize()
+
+		rule := datatypes.Network_Firewall_Update_Request_Rule{
+			OrderValue:           sl.Int(i + 1),
+			Action:               sl.String(r.Action),
+			Protocol:             sl.String(r.Protocol),
+			SourceIpAddress:      sl.String(r.SourceIpAddress),
+			SourceIpCidr:         sl.Int(r.SourceIpCidr),
+			DestinationIpAddress: sl.String(r.DestinationIpAddress),
+			DestinationIpCidr:    sl.Int(r.DestinationIpCidr),
+			Version:              sl.Int(r.Version),
+		}
+
+		if r.DestinationPortStart != 0 || r.DestinationPortEnd != 0 {
+			rule.DestinationPortRangeStart = sl.Int(r.DestinationPortStart)
+			rule.DestinationPortRangeEnd = sl.Int(r.DestinationPortEnd)
+		}
+
+		if r.Notes != "" {
+			rule.Notes = sl.String(r.Notes)
+		}
+
+		result = append(result, rule)
+	}
+
+	return result
+}
+
+// normalize fills in the defaults of a rule, so that rules can be compared.
+// Notes do not take part in comparisons.
+func (r Rule) normalize() Rule {
+	if r.Version == 0 {
+		r.Version = 4
+	}
+
+	r.Notes = ""
+	return r
+}
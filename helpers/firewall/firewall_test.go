@@ -0,0 +1,82 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package firewall
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffRules(t *testing.T) {
+	ssh := Rule{Action: "permit", Protocol: "tcp", SourceIpAddress: "any", DestinationIpAddress: "any",
+		DestinationPortStart: 22, DestinationPortEnd: 22, Version: 4}
+	web := Rule{Action: "permit", Protocol: "tcp", SourceIpAddress: "any", DestinationIpAddress: "any",
+		DestinationPortStart: 443, DestinationPortEnd: 443}
+	deny := Rule{Action: "deny", Protocol: "tcp", SourceIpAddress: "any", DestinationIpAddress: "any"}
+
+	noted := web
+	noted.Notes = "https"
+
+	tests := []struct {
+		description string
+		current     []Rule
+		desired     []Rule
+		expected    Diff
+	}{
+		{
+			description: "same rules",
+			current:     []Rule{ssh, web},
+			desired:     []Rule{ssh, web},
+			expected:    Diff{Added: []Rule{}, Removed: []Rule{}},
+		},
+		{
+			description: "notes and default version are ignored",
+			current:     []Rule{web},
+			desired:     []Rule{noted},
+			expected:    Diff{Added: []Rule{}, Removed: []Rule{}},
+		},
+		{
+			description: "added and removed rules",
+			current:     []Rule{ssh, deny},
+			desired:     []Rule{ssh, web},
+			expected:    Diff{Added: []Rule{web}, Removed: []Rule{deny}},
+		},
+		{
+			description: "duplicate rules",
+			current:     []Rule{ssh, ssh},
+			desired:     []Rule{ssh},
+			expected:    Diff{Added: []Rule{}, Removed: []Rule{ssh}},
+		},
+		{
+			description: "reordered rules",
+			current:     []Rule{deny, ssh},
+			desired:     []Rule{ssh, deny},
+			expected:    Diff{Added: []Rule{}, Removed: []Rule{}, Reordered: true},
+		},
+	}
+
+	for _, test := range tests {
+		diff := DiffRules(test.current, test.desired)
+		if !reflect.DeepEqual(diff, test.expected) {
+			t.Errorf("%s: expected %+v, got %+v", test.description, test.expected, diff)
+		}
+
+		if diff.Empty() != (len(test.expected.Added) == 0 && len(test.expected.Removed) == 0 && !test.expected.Reordered) {
+			t.Errorf("%s: unexpected Empty() %t", test.description, diff.Empty())
+		}
+	}
+}
@@ -0,0 +1,142 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hardware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// CancellationReport is the result of the pre-flight checks run before
+// canceling a server.
+//
+// AuthorizedStorage and ActiveTransactions prevent the cancellation. Children
+// lists the active billing items attached to the server's billing item, which
+// are canceled along with it.
+type CancellationReport struct {
+	HardwareId         int
+	BillingItemId      int
+	PendingCancel      bool
+	AuthorizedStorage  []string
+	ActiveTransactions []string
+	Children           []string
+}
+
+// Blocked reports whether any of the pre-flight checks prevents the
+// cancellation of the server
+func (r CancellationReport) Blocked() bool {
+	return r.BillingItemId == 0 || r.PendingCancel ||
+		len(r.AuthorizedStorage) > 0 || len(r.ActiveTransactions) > 0
+}
+
+// Reasons returns a description of each check preventing the cancellation
+func (r CancellationReport) Reasons() []string {
+	reasons := []string{}
+
+	if r.BillingItemId == 0 {
+		reasons = append(reasons, "no billing item found")
+	}
+
+	if r.PendingCancel {
+		reasons = append(reasons, "cancellation already pending")
+	}
+
+	if len(r.AuthorizedStorage) > 0 {
+		reasons = append(reasons, fmt.Sprintf("authorized to storage %s", strings.Join(r.AuthorizedStorage, ", ")))
+	}
+
+	if len(r.ActiveTransactions) > 0 {
+		reasons = append(reasons, fmt.Sprintf("active transactions %s", strings.Join(r.ActiveTransactions, ", ")))
+	}
+
+	return reasons
+}
+
+// CheckHardwareCancellation runs the pre-flight checks for the cancellation
+// of the hardware server with the provided id, without canceling it.
+func CheckHardwareCancellation(sess *session.Session, id int) (CancellationReport, error) {
+	server, err := services.GetHardwareServerService(sess).
+		Id(id).
+		Mask("id,allowedNetworkStorage[id,username],allowedNetworkStorageReplicas[id,username]," +
+			"activeTransactions[id,transactionGroup[name]]," +
+			"billingItem[id,pendingCancellationFlag,activeChildren[id,description,categoryCode]]").
+		GetObject()
+	if err != nil {
+		return CancellationReport{}, err
+	}
+
+	report := CancellationReport{
+		HardwareId:         id,
+		BillingItemId:      sl.Grab(server, "BillingItem.Id", 0).(int),
+		PendingCancel:      sl.Grab(server, "BillingItem.PendingCancellationFlag", false).(bool),
+		AuthorizedStorage:  []string{},
+		ActiveTransactions: []string{},
+		Children:           []string{},
+	}
+
+	for _, storage := range append(server.AllowedNetworkStorage, server.AllowedNetworkStorageReplicas...) {
+		report.AuthorizedStorage = append(report.AuthorizedStorage, sl.Get(storage.Username, "").(string))
+	}
+
+	for _, transaction := range server.ActiveTransactions {
+		report.ActiveTransactions = append(
+			report.ActiveTransactions,
+			sl.Grab(transaction, "TransactionGroup.Name", "unknown").(string))
+	}
+
+	if server.BillingItem != nil {
+		for _, child := range server.BillingItem.ActiveChildren {
+			report.Children = append(report.Children, fmt.Sprintf(
+				"%s (%s)", sl.Get(child.Description, ""), sl.Get(child.CategoryCode, "")))
+		}
+	}
+
+	return report, nil
+}
+
+// CancelHardware cancels the hardware server with the provided id, along with
+// its associated billing items, at the end of the billing cycle. The
+// cancellation is only issued when none of the pre-flight checks run by
+// CheckHardwareCancellation prevents it. The report is returned in all cases.
+func CancelHardware(sess *session.Session, id int, reason string, comment string) (CancellationReport, error) {
+	report, err := CheckHardwareCancellation(sess, id)
+	if err != nil {
+		return report, err
+	}
+
+	if report.Blocked() {
+		return report, fmt.Errorf(
+			"Cannot cancel server %d: %s", id, strings.Join(report.Reasons(), "; "))
+	}
+
+	ok, err := services.GetBillingItemService(sess).
+		Id(report.BillingItemId).
+		CancelItem(sl.Bool(false), sl.Bool(true), sl.String(reason), sl.String(comment))
+	if err != nil {
+		return report, err
+	}
+
+	if !ok {
+		return report, fmt.Errorf("Cancellation of server %d was not accepted", id)
+	}
+
+	return report, nil
+}
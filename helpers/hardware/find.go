@@ -0,0 +1,127 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hardware
+
+import (
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+)
+
+// DefaultFindMask is the object mask used by FindHardware when none is provided
+const DefaultFindMask = "id,globalIdentifier,hostname,domain,fullyQualifiedDomainName," +
+	"primaryIpAddress,primaryBackendIpAddress,provisionDate,hardwareStatus[status]," +
+	"datacenter[name],tagReferences[tag[name]]"
+
+// Query holds the criteria used to search the hardware of an account. Empty
+// fields are ignored. Hostname and Domain match exactly, and a server must
+// carry every tag in Tags to be returned.
+type Query struct {
+	Hostname   string
+	Domain     string
+	Datacenter string
+	Tags       []string
+	PublicIp   string
+	PrivateIp  string
+}
+
+// FindHardware returns the hardware of the account matching query. An object
+// mask can be provided as an optional argument, and DefaultFindMask is used
+// otherwise. When searching by more than one tag, the mask must include
+// tagReferences[tag[name]].
+func FindHardware(sess *session.Session, query Query, mask ...string) ([]datatypes.Hardware, error) {
+	objectMask := DefaultFindMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	filters := filter.New()
+
+	if query.Hostname != "" {
+		filters = append(filters, filter.Path("hardware.hostname").Eq(query.Hostname))
+	}
+
+	if query.Domain != "" {
+		filters = append(filters, filter.Path("hardware.domain").Eq(query.Domain))
+	}
+
+	if query.Datacenter != "" {
+		filters = append(filters, filter.Path("hardware.datacenter.name").Eq(query.Datacenter))
+	}
+
+	if query.PublicIp != "" {
+		filters = append(filters, filter.Path("hardware.primaryIpAddress").Eq(query.PublicIp))
+	}
+
+	if query.PrivateIp != "" {
+		filters = append(filters, filter.Path("hardware.primaryBackendIpAddress").Eq(query.PrivateIp))
+	}
+
+	if len(query.Tags) > 0 {
+		tags := []interface{}{}
+		for _, tag := range query.Tags {
+			tags = append(tags, tag)
+		}
+
+		filters = append(filters, filter.Path("hardware.tagReferences.tag.name").In(tags...))
+	}
+
+	hardware, err := services.GetAccountService(sess).
+		Mask(objectMask).
+		Filter(filters.Build()).
+		GetHardware()
+	if err != nil {
+		return nil, err
+	}
+
+	// The object filter matches servers carrying any of the tags, so only
+	// keep the ones carrying all of them
+	if len(query.Tags) > 1 {
+		hardware = selectTagged(hardware, query.Tags)
+	}
+
+	return hardware, nil
+}
+
+// selectTagged returns the servers carrying all of the provided tags
+func selectTagged(hardware []datatypes.Hardware, tags []string) []datatypes.Hardware {
+	selected := []datatypes.Hardware{}
+
+	for _, hw := range hardware {
+		serverTags := map[string]bool{}
+		for _, ref := range hw.TagReferences {
+			if ref.Tag != nil && ref.Tag.Name != nil {
+				serverTags[*ref.Tag.Name] = true
+			}
+		}
+
+		matches := true
+		for _, tag := range tags {
+			if !serverTags[tag] {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			selected = append(selected, hw)
+		}
+	}
+
+	return selected
+}
@@ -0,0 +1,122 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hardware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// FirmwareOptions selects the components whose firmware is updated or
+// reflashed
+type FirmwareOptions struct {
+	IPMI           bool
+	RAIDController bool
+	BIOS           bool
+	HardDrive      bool
+}
+
+// CreateFirmwareUpdateTransaction updates the firmware of the selected
+// components of the hardware server with the provided id, and waits for the
+// update transaction to complete. The server must not have any active
+// transaction when this is called.
+func CreateFirmwareUpdateTransaction(ctx context.Context, sess *session.Session, id int, opts FirmwareOptions, interval time.Duration) error {
+	lastTransactionId, err := checkNoActiveTransactions(sess, id)
+	if err != nil {
+		return err
+	}
+
+	ok, err := services.GetHardwareServerService(sess).
+		Id(id).
+		CreateFirmwareUpdateTransaction(
+			sl.Int(flagValue(opts.IPMI)),
+			sl.Int(flagValue(opts.RAIDController)),
+			sl.Int(flagValue(opts.BIOS)),
+			sl.Int(flagValue(opts.HardDrive)))
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("Firmware update of server %d was not accepted", id)
+	}
+
+	_, err = waitForTransactions(ctx, sess, id, lastTransactionId, interval, nil)
+	return err
+}
+
+// CreateFirmwareReflashTransaction reflashes the firmware of the selected
+// components of the hardware server with the provided id, and waits for the
+// reflash transaction to complete. Hard drive firmware cannot be reflashed, so
+// opts.HardDrive is ignored.
+func CreateFirmwareReflashTransaction(ctx context.Context, sess *session.Session, id int, opts FirmwareOptions, interval time.Duration) error {
+	lastTransactionId, err := checkNoActiveTransactions(sess, id)
+	if err != nil {
+		return err
+	}
+
+	// createFirmwareReflashTransaction is not part of the generated services,
+	// so it is invoked directly
+	var ok bool
+	err = sess.DoRequest(
+		"SoftLayer_Hardware_Server",
+		"createFirmwareReflashTransaction",
+		[]interface{}{flagValue(opts.IPMI), flagValue(opts.RAIDController), flagValue(opts.BIOS)},
+		&sl.Options{Id: &id},
+		&ok)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("Firmware reflash of server %d was not accepted", id)
+	}
+
+	_, err = waitForTransactions(ctx, sess, id, lastTransactionId, interval, nil)
+	return err
+}
+
+// checkNoActiveTransactions returns an error if the server has any active
+// transaction. Otherwise it returns the id of its last transaction.
+func checkNoActiveTransactions(sess *session.Session, id int) (int, error) {
+	server, err := services.GetHardwareServerService(sess).
+		Id(id).
+		Mask("id,activeTransactionCount,lastTransaction[id]").
+		GetObject()
+	if err != nil {
+		return 0, err
+	}
+
+	if sl.Get(server.ActiveTransactionCount, uint(0)).(uint) > 0 {
+		return 0, fmt.Errorf("Server %d has active transactions", id)
+	}
+
+	return sl.Grab(server, "LastTransaction.Id", 0).(int), nil
+}
+
+func flagValue(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
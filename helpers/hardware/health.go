@@ -0,0 +1,171 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hardware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Sensor statuses reported by the remote management card
+const (
+	SensorStatusOk             = "ok"
+	SensorStatusNonCritical    = "nc"
+	SensorStatusCritical       = "cr"
+	SensorStatusNonRecoverable = "nr"
+	SensorStatusNotSpecified   = "ns"
+)
+
+// Sensor is a normalized sensor reading. Value is only set for numeric
+// readings.
+type Sensor struct {
+	Name    string
+	Reading string
+	Value   *float64
+	Units   string
+	Status  string
+}
+
+// Drive is a hard drive installed in a server
+type Drive struct {
+	Id           int
+	Model        string
+	SerialNumber string
+	CapacityGB   float64
+}
+
+// HealthReport summarizes the health of a hardware server. Sensors are grouped
+// by kind, based on their name and units. Healthy is false when any sensor
+// reports a non-critical, critical or non-recoverable status.
+type HealthReport struct {
+	Healthy       bool
+	PowerState    string
+	Temperatures  []Sensor
+	Fans          []Sensor
+	PowerSupplies []Sensor
+	DriveSensors  []Sensor
+	Other         []Sensor
+	Drives        []Drive
+}
+
+// GetSensorData returns the readings of all the sensors of the hardware server
+// with the provided id, as reported by its remote management card.
+func GetSensorData(sess *session.Session, id int) ([]Sensor, error) {
+	readings, err := services.GetHardwareServerService(sess).Id(id).GetSensorData()
+	if err != nil {
+		return nil, err
+	}
+
+	sensors := make([]Sensor, 0, len(readings))
+	for _, reading := range readings {
+		sensors = append(sensors, newSensor(reading))
+	}
+
+	return sensors, nil
+}
+
+// GetHealthReport assembles the sensor readings, power state and hard drives
+// of the hardware server with the provided id into a HealthReport.
+func GetHealthReport(sess *session.Session, id int) (HealthReport, error) {
+	service := services.GetHardwareServerService(sess).Id(id)
+
+	sensors, err := GetSensorData(sess, id)
+	if err != nil {
+		return HealthReport{}, err
+	}
+
+	powerState, err := service.GetServerPowerState()
+	if err != nil {
+		return HealthReport{}, err
+	}
+
+	drives, err := service.
+		Mask("id,serialNumber,capacity,hardwareComponentModel[capacity,manufacturer,name]").
+		GetHardDrives()
+	if err != nil {
+		return HealthReport{}, err
+	}
+
+	report := HealthReport{
+		Healthy:       true,
+		PowerState:    powerState,
+		Temperatures:  []Sensor{},
+		Fans:          []Sensor{},
+		PowerSupplies: []Sensor{},
+		DriveSensors:  []Sensor{},
+		Other:         []Sensor{},
+		Drives:        []Drive{},
+	}
+
+	for _, sensor := range sensors {
+		switch sensor.Status {
+		case SensorStatusNonCritical, SensorStatusCritical, SensorStatusNonRecoverable:
+			report.Healthy = false
+		}
+
+		name := strings.ToLower(sensor.Name)
+		units := strings.ToLower(sensor.Units)
+
+		switch {
+		case strings.Contains(units, "degrees"):
+			report.Temperatures = append(report.Temperatures, sensor)
+		case units == "rpm" || strings.Contains(name, "fan"):
+			report.Fans = append(report.Fans, sensor)
+		case strings.HasPrefix(name, "ps") || strings.Contains(name, "power supply"):
+			report.PowerSupplies = append(report.PowerSupplies, sensor)
+		case strings.Contains(name, "drive") || strings.Contains(name, "hdd"):
+			report.DriveSensors = append(report.DriveSensors, sensor)
+		default:
+			report.Other = append(report.Other, sensor)
+		}
+	}
+
+	for _, drive := range drives {
+		model := strings.TrimSpace(
+			sl.Grab(drive, "HardwareComponentModel.Manufacturer", "").(string) + " " +
+				sl.Grab(drive, "HardwareComponentModel.Name", "").(string))
+
+		report.Drives = append(report.Drives, Drive{
+			Id:           sl.Get(drive.Id, 0).(int),
+			Model:        model,
+			SerialNumber: sl.Get(drive.SerialNumber, "").(string),
+			CapacityGB:   driveCapacity(drive),
+		})
+	}
+
+	return report, nil
+}
+
+func newSensor(reading datatypes.Container_RemoteManagement_SensorReading) Sensor {
+	sensor := Sensor{
+		Name:    strings.TrimSpace(sl.Get(reading.SensorId, "").(string)),
+		Reading: strings.TrimSpace(sl.Get(reading.SensorReading, "").(string)),
+		Units:   strings.TrimSpace(sl.Get(reading.SensorUnits, "").(string)),
+		Status:  strings.ToLower(strings.TrimSpace(sl.Get(reading.Status, "").(string))),
+	}
+
+	if value, err := strconv.ParseFloat(sensor.Reading, 64); err == nil {
+		sensor.Value = &value
+	}
+
+	return sensor
+}
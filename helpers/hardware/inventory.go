@@ -0,0 +1,129 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hardware
+
+import (
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// componentMask is the object mask used for each kind of hardware component
+// in the inventory
+const componentMask = "id,serialNumber,capacity," +
+	"hardwareComponentModel[manufacturer,name,version,capacity,hardwareGenericComponentModel[units]]"
+
+// Inventory lists the components of a hardware server
+type Inventory struct {
+	HardwareId        int
+	Hostname          string
+	SerialNumber      string
+	Processors        []Component
+	Memory            []Component
+	Drives            []Component
+	NetworkCards      []Component
+	NetworkInterfaces []NetworkInterface
+}
+
+// Component is a single hardware component. Capacity is expressed in Units
+// (e.g. GB for drives and memory, GHz for processors).
+type Component struct {
+	Id           int
+	Manufacturer string
+	Model        string
+	Version      string
+	SerialNumber string
+	Capacity     float64
+	Units        string
+}
+
+// NetworkInterface is a network interface of a server
+type NetworkInterface struct {
+	Name       string
+	Port       int
+	MacAddress string
+	IpAddress  string
+	Speed      int
+	MaxSpeed   int
+	Status     string
+}
+
+// GetInventory returns the CPUs, DIMMs, disks, network cards and network
+// interfaces of the hardware server with the provided id, retrieved in a
+// single call.
+func GetInventory(sess *session.Session, id int) (Inventory, error) {
+	server, err := services.GetHardwareServerService(sess).
+		Id(id).
+		Mask("id,hostname,manufacturerSerialNumber," +
+			"processors[" + componentMask + "]," +
+			"memory[" + componentMask + "]," +
+			"hardDrives[" + componentMask + "]," +
+			"networkCards[" + componentMask + "]," +
+			"networkComponents[name,port,macAddress,primaryIpAddress,speed,maxSpeed,status]").
+		GetObject()
+	if err != nil {
+		return Inventory{}, err
+	}
+
+	inventory := Inventory{
+		HardwareId:        id,
+		Hostname:          sl.Get(server.Hostname, "").(string),
+		SerialNumber:      sl.Get(server.ManufacturerSerialNumber, "").(string),
+		Processors:        newComponents(server.Processors),
+		Memory:            newComponents(server.Memory),
+		Drives:            newComponents(server.HardDrives),
+		NetworkCards:      newComponents(server.NetworkCards),
+		NetworkInterfaces: []NetworkInterface{},
+	}
+
+	for _, nc := range server.NetworkComponents {
+		inventory.NetworkInterfaces = append(inventory.NetworkInterfaces, NetworkInterface{
+			Name:       sl.Get(nc.Name, "").(string),
+			Port:       sl.Get(nc.Port, 0).(int),
+			MacAddress: strings.ToLower(sl.Get(nc.MacAddress, "").(string)),
+			IpAddress:  sl.Get(nc.PrimaryIpAddress, "").(string),
+			Speed:      sl.Get(nc.Speed, 0).(int),
+			MaxSpeed:   sl.Get(nc.MaxSpeed, 0).(int),
+			Status:     sl.Get(nc.Status, "").(string),
+		})
+	}
+
+	return inventory, nil
+}
+
+func newComponents(components []datatypes.Hardware_Component) []Component {
+	result := make([]Component, 0, len(components))
+
+	for _, c := range components {
+		capacity := sl.Get(c.Capacity, sl.Grab(c, "HardwareComponentModel.Capacity", datatypes.Float64(0))).(datatypes.Float64)
+
+		result = append(result, Component{
+			Id:           sl.Get(c.Id, 0).(int),
+			Manufacturer: sl.Grab(c, "HardwareComponentModel.Manufacturer", "").(string),
+			Model:        sl.Grab(c, "HardwareComponentModel.Name", "").(string),
+			Version:      sl.Grab(c, "HardwareComponentModel.Version", "").(string),
+			SerialNumber: sl.Get(c.SerialNumber, "").(string),
+			Capacity:     float64(capacity),
+			Units:        sl.Grab(c, "HardwareComponentModel.HardwareGenericComponentModel.Units", "").(string),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,247 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hardware
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/helpers/location"
+	"github.com/softlayer/softlayer-go/helpers/product"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// FastProvisionPackageType is the package type holding the preset (fixed
+// configuration) bare metal servers
+const FastProvisionPackageType = "BARE_METAL_CPU_FAST_PROVISION"
+
+// DefaultRAIDKeyName is the disk controller ordered when no RAID
+// configuration is specified
+const DefaultRAIDKeyName = "DISK_CONTROLLER_NONRAID"
+
+// defaultBareMetalItems maps the category codes required by a bare metal
+// order, other than the ones derived from BareMetalConfig, to the key name of
+// the item ordered by default
+var defaultBareMetalItems = map[string]string{
+	"bandwidth":             "BANDWIDTH_0_GB_2",
+	"pri_ip_addresses":      "1_IP_ADDRESS",
+	"remote_management":     "REBOOT_KVM_OVER_IP",
+	"vpn_management":        "UNLIMITED_SSL_VPN_USERS_1_PPTP_VPN_USER_PER_ACCOUNT",
+	"monitoring":            "MONITORING_HOST_PING",
+	"notification":          "NOTIFICATION_EMAIL_AND_TICKET",
+	"response":              "AUTOMATED_NOTIFICATION",
+	"vulnerability_scanner": "NESSUS_VULNERABILITY_ASSESSMENT_REPORTING",
+}
+
+// BareMetalConfig describes a fast-provision (preset) bare metal server order.
+//
+// PresetKeyName is the key name of an active preset of the fast-provision
+// package (e.g. "S1270_32GB_1X1TBSATA_NORAID"), OSKeyName the key name of the
+// operating system item (e.g. "OS_UBUNTU_16_04_LTS_XENIAL_XERUS_64_BIT") and
+// NetworkSpeed the port speed in Mbps. RAID is the key name of the disk
+// controller item, and defaults to DefaultRAIDKeyName.
+type BareMetalConfig struct {
+	PresetKeyName string
+	OSKeyName     string
+	Datacenter    string
+	Hostname      string
+	Domain        string
+	NetworkSpeed  int
+	RAID          string
+	Hourly        bool
+}
+
+// OrderBareMetal places an order for the fast-provision bare metal server
+// described by config. The package, preset and item prices are resolved from
+// the key names provided.
+func OrderBareMetal(sess *session.Session, config BareMetalConfig) (datatypes.Container_Product_Order_Receipt, error) {
+	order, err := buildBareMetalOrder(sess, config)
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	return services.GetProductOrderService(sess).PlaceOrder(&order, sl.Bool(false))
+}
+
+// VerifyBareMetalOrder verifies the order for the fast-provision bare metal
+// server described by config without placing it. The returned container holds
+// the prices and totals of the order, and can be used as a cost preview.
+func VerifyBareMetalOrder(sess *session.Session, config BareMetalConfig) (datatypes.Container_Product_Order, error) {
+	order, err := buildBareMetalOrder(sess, config)
+	if err != nil {
+		return datatypes.Container_Product_Order{}, err
+	}
+
+	return services.GetProductOrderService(sess).VerifyOrder(&order)
+}
+
+func buildBareMetalOrder(sess *session.Session, config BareMetalConfig) (datatypes.Container_Product_Order_Hardware_Server, error) {
+	if config.Hostname == "" || config.Domain == "" {
+		return datatypes.Container_Product_Order_Hardware_Server{},
+			fmt.Errorf("Hostname and domain are required to order a bare metal server")
+	}
+
+	pkg, err := product.GetPackageByType(sess, FastProvisionPackageType)
+	if err != nil {
+		return datatypes.Container_Product_Order_Hardware_Server{}, err
+	}
+
+	presets, err := services.GetProductPackageService(sess).
+		Id(*pkg.Id).
+		Mask("id,keyName").
+		GetActivePresets()
+	if err != nil {
+		return datatypes.Container_Product_Order_Hardware_Server{}, err
+	}
+
+	var presetId *int
+	for _, preset := range presets {
+		if preset.KeyName != nil && *preset.KeyName == config.PresetKeyName {
+			presetId = preset.Id
+			break
+		}
+	}
+
+	if presetId == nil {
+		return datatypes.Container_Product_Order_Hardware_Server{},
+			fmt.Errorf("No active preset found with key name of %s", config.PresetKeyName)
+	}
+
+	dc, err := location.GetLocationByName(sess, config.Datacenter, "id")
+	if err != nil {
+		return datatypes.Container_Product_Order_Hardware_Server{}, err
+	}
+
+	items, err := product.GetPackageProducts(
+		sess, *pkg.Id,
+		"id,keyName,capacity,description,itemCategory[categoryCode],"+
+			"prices[id,locationGroupId,hourlyRecurringFee,recurringFee,categories[categoryCode]]")
+	if err != nil {
+		return datatypes.Container_Product_Order_Hardware_Server{}, err
+	}
+
+	raid := config.RAID
+	if raid == "" {
+		raid = DefaultRAIDKeyName
+	}
+
+	keyNames := map[string]string{
+		"os":              config.OSKeyName,
+		"disk_controller": raid,
+	}
+	for category, keyName := range defaultBareMetalItems {
+		keyNames[category] = keyName
+	}
+
+	prices := []datatypes.Product_Item_Price{}
+	for category, keyName := range keyNames {
+		price, err := selectItemPrice(items, keyName, config.Hourly)
+		if err != nil {
+			return datatypes.Container_Product_Order_Hardware_Server{},
+				fmt.Errorf("Could not resolve the %s price: %s", category, err)
+		}
+
+		prices = append(prices, price)
+	}
+
+	portSpeed, err := selectPortSpeedPrice(items, config.NetworkSpeed, config.Hourly)
+	if err != nil {
+		return datatypes.Container_Product_Order_Hardware_Server{}, err
+	}
+	prices = append(prices, portSpeed)
+
+	return datatypes.Container_Product_Order_Hardware_Server{
+		Container_Product_Order: datatypes.Container_Product_Order{
+			PackageId:        pkg.Id,
+			PresetId:         presetId,
+			Location:         sl.String(strconv.Itoa(*dc.Id)),
+			UseHourlyPricing: sl.Bool(config.Hourly),
+			Quantity:         sl.Int(1),
+			Hardware: []datatypes.Hardware{
+				{
+					Hostname: sl.String(config.Hostname),
+					Domain:   sl.String(config.Domain),
+				},
+			},
+			Prices: prices,
+		},
+	}, nil
+}
+
+// selectItemPrice returns the standard (not location specific) price of the
+// product item with the provided key name
+func selectItemPrice(items []datatypes.Product_Item, keyName string, hourly bool) (datatypes.Product_Item_Price, error) {
+	for _, item := range items {
+		if item.KeyName == nil || *item.KeyName != keyName {
+			continue
+		}
+
+		if price, ok := standardPrice(item, hourly); ok {
+			return price, nil
+		}
+	}
+
+	return datatypes.Product_Item_Price{}, fmt.Errorf("No price found for item %s", keyName)
+}
+
+// selectPortSpeedPrice returns the standard price of the non-redundant public
+// and private port speed item with the provided capacity
+func selectPortSpeedPrice(items []datatypes.Product_Item, speed int, hourly bool) (datatypes.Product_Item_Price, error) {
+	for _, item := range items {
+		category := sl.Grab(item, "ItemCategory.CategoryCode", "").(string)
+		if category != product.NICSpeedCategoryCode || item.Capacity == nil {
+			continue
+		}
+
+		if *item.Capacity != datatypes.Float64(speed) {
+			continue
+		}
+
+		keyName := sl.Get(item.KeyName, "").(string)
+		if strings.Contains(keyName, "REDUNDANT") || !strings.Contains(keyName, "PUBLIC") {
+			continue
+		}
+
+		if price, ok := standardPrice(item, hourly); ok {
+			return price, nil
+		}
+	}
+
+	return datatypes.Product_Item_Price{}, fmt.Errorf("No port speed price found for %d Mbps", speed)
+}
+
+// standardPrice returns the price of item which is not tied to a location
+// group, and which matches the billing type requested
+func standardPrice(item datatypes.Product_Item, hourly bool) (datatypes.Product_Item_Price, bool) {
+	for _, price := range item.Prices {
+		if price.LocationGroupId != nil {
+			continue
+		}
+
+		if hourly && price.HourlyRecurringFee == nil {
+			continue
+		}
+
+		return datatypes.Product_Item_Price{Id: price.Id}, true
+	}
+
+	return datatypes.Product_Item_Price{}, false
+}
@@ -0,0 +1,146 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hardware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Redundancy selects how the uplinks of a redundant network interface are used
+// after a port speed change
+type Redundancy string
+
+const (
+	// RedundancyUnchanged keeps the current redundancy of the interface
+	RedundancyUnchanged Redundancy = ""
+	// Redundant uses both uplinks of the interface
+	Redundant Redundancy = "redundant"
+	// Degraded only uses one of the uplinks of the interface
+	Degraded Redundancy = "degraded"
+)
+
+// validPortSpeeds are the speeds, in Mbps, an interface can be set to. A speed
+// of 0 disconnects the interface.
+var validPortSpeeds = map[int]bool{0: true, 10: true, 100: true, 1000: true, 10000: true}
+
+// SetPublicPortSpeed sets the speed, in Mbps, and optionally the redundancy of
+// the public network interface of the hardware server with the provided id.
+// It then waits until no transaction is active and the interface reports the
+// new speed.
+func SetPublicPortSpeed(ctx context.Context, sess *session.Session, id int, speed int, redundancy Redundancy, interval time.Duration) error {
+	return setPortSpeed(ctx, sess, id, true, speed, redundancy, interval)
+}
+
+// SetPrivatePortSpeed is the equivalent of SetPublicPortSpeed for the private
+// network interface of the server.
+func SetPrivatePortSpeed(ctx context.Context, sess *session.Session, id int, speed int, redundancy Redundancy, interval time.Duration) error {
+	return setPortSpeed(ctx, sess, id, false, speed, redundancy, interval)
+}
+
+// setPortSpeed changes the speed of the public or private interface of a
+// server, and waits for the change to settle
+func setPortSpeed(
+	ctx context.Context,
+	sess *session.Session,
+	id int,
+	public bool,
+	speed int,
+	redundancy Redundancy,
+	interval time.Duration,
+) error {
+
+	if !validPortSpeeds[speed] {
+		return fmt.Errorf("Invalid port speed %d, must be one of 0, 10, 100, 1000 or 10000", speed)
+	}
+
+	component := "primaryBackendNetworkComponent"
+	method := "setPrivateNetworkInterfaceSpeed"
+	if public {
+		component = "primaryNetworkComponent"
+		method = "setPublicNetworkInterfaceSpeed"
+	}
+
+	mask := fmt.Sprintf(
+		"id,activeTransaction[id,transactionGroup[name],transactionStatus[name]],"+
+			"%s[maxSpeed,speed,redundancyCapableFlag,redundancyEnabledFlag]", component)
+
+	server, err := services.GetHardwareServerService(sess).Id(id).Mask(mask).GetObject()
+	if err != nil {
+		return err
+	}
+
+	current := networkComponent(server, public)
+	if current.MaxSpeed != nil && speed > *current.MaxSpeed {
+		return fmt.Errorf("Port speed %d exceeds the maximum speed %d of the interface", speed, *current.MaxSpeed)
+	}
+
+	if redundancy == Redundant && !sl.Get(current.RedundancyCapableFlag, false).(bool) {
+		return fmt.Errorf("The interface of server %d is not redundancy capable", id)
+	}
+
+	// The generated methods do not take the redundancy parameter, so the
+	// request is issued directly
+	args := []interface{}{speed}
+	if redundancy != RedundancyUnchanged {
+		args = append(args, string(redundancy))
+	}
+
+	var ok bool
+	err = sess.DoRequest("SoftLayer_Hardware_Server", method, args, &sl.Options{Id: &id}, &ok)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("Port speed change of server %d was not accepted", id)
+	}
+
+	_, err = pollServer(ctx, sess, id, mask, interval, nil, func(server datatypes.Hardware_Server) bool {
+		nc := networkComponent(server, public)
+		if server.ActiveTransaction != nil || sl.Get(nc.Speed, 0).(int) != speed {
+			return false
+		}
+
+		switch redundancy {
+		case Redundant:
+			return sl.Get(nc.RedundancyEnabledFlag, false).(bool)
+		case Degraded:
+			return !sl.Get(nc.RedundancyEnabledFlag, false).(bool)
+		}
+
+		return true
+	})
+
+	return err
+}
+
+// networkComponent returns the primary public or private network component of
+// the server
+func networkComponent(server datatypes.Hardware_Server, public bool) datatypes.Network_Component {
+	if public {
+		return sl.Get(server.PrimaryNetworkComponent, datatypes.Network_Component{}).(datatypes.Network_Component)
+	}
+
+	return sl.Get(server.PrimaryBackendNetworkComponent, datatypes.Network_Component{}).(datatypes.Network_Component)
+}
@@ -0,0 +1,224 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hardware
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// ReloadConfig describes the operating system reload of a bare metal server.
+//
+// ArrayTypeId is the id of the Configuration_Storage_Group_Array_Type (RAID
+// level) to set the server's drives up with. The number of drives in the
+// server is validated against it before the reload is issued.
+//
+// Drives lists the partition layout for each hard drive, by disk number (the
+// position of the drive in the server's hard drive list). Drives that are not
+// listed keep the default layout of the operating system.
+type ReloadConfig struct {
+	ArrayTypeId          int
+	Drives               []DriveLayout
+	SshKeyIds            []int
+	PostInstallScriptURI string
+	ImageTemplateId      int
+	LVM                  bool
+	EraseHardDrives      bool
+}
+
+// DriveLayout is the partition layout of a single hard drive
+type DriveLayout struct {
+	DiskNumber int
+	Partitions []PartitionLayout
+}
+
+// PartitionLayout describes a partition. SizeGB is the minimum size of the
+// partition. When Grow is set, the partition fills all remaining space on the
+// drive; there can only be one such partition per drive.
+type PartitionLayout struct {
+	Name   string
+	SizeGB float64
+	Grow   bool
+}
+
+// ReloadOperatingSystem reloads the operating system of the hardware server
+// with the provided id, using the partition, RAID, ssh key and post-install
+// script settings in config. The configuration is validated against the disk
+// inventory of the server before the reload is issued.
+func ReloadOperatingSystem(sess *session.Session, id int, config ReloadConfig) error {
+	service := services.GetHardwareServerService(sess)
+
+	server, err := service.
+		Id(id).
+		Mask("id,hardDrives[id,capacity,hardwareComponentModel[capacity]]").
+		GetObject()
+	if err != nil {
+		return err
+	}
+
+	if err = validateReloadConfig(sess, server, config); err != nil {
+		return err
+	}
+
+	reloadConfig := hardwareServerConfiguration{
+		Container_Hardware_Server_Configuration: datatypes.Container_Hardware_Server_Configuration{
+			SshKeyIds: config.SshKeyIds,
+			LvmFlag:   sl.Bool(config.LVM),
+		},
+	}
+
+	if config.PostInstallScriptURI != "" {
+		reloadConfig.CustomProvisionScriptUri = sl.String(config.PostInstallScriptURI)
+	}
+
+	if config.ImageTemplateId != 0 {
+		reloadConfig.ImageTemplateId = sl.Int(config.ImageTemplateId)
+	}
+
+	if config.EraseHardDrives {
+		reloadConfig.EraseHardDrives = sl.Int(1)
+	}
+
+	if config.ArrayTypeId != 0 {
+		reloadConfig.ArrayTypeId = sl.Int(config.ArrayTypeId)
+	}
+
+	for _, drive := range config.Drives {
+		hardDrive := datatypes.Hardware_Component_HardDrive{
+			Hardware_Component: datatypes.Hardware_Component{
+				Id: server.HardDrives[drive.DiskNumber].Id,
+			},
+			Partitions: []datatypes.Hardware_Component_Partition{},
+		}
+
+		for _, partition := range drive.Partitions {
+			grow := 0
+			if partition.Grow {
+				grow = 1
+			}
+
+			hardDrive.Partitions = append(hardDrive.Partitions, datatypes.Hardware_Component_Partition{
+				Name:        sl.String(partition.Name),
+				MinimumSize: sl.Float(partition.SizeGB),
+				Grow:        sl.Int(grow),
+				DiskNumber:  sl.Int(drive.DiskNumber),
+			})
+		}
+
+		reloadConfig.HardDrives = append(reloadConfig.HardDrives, hardDrive)
+	}
+
+	// The generated ReloadOperatingSystem() only accepts plain hardware
+	// components, which cannot carry partitions, nor the array type, so the
+	// request is issued directly with the extended configuration
+	var resp string
+	return sess.DoRequest(
+		"SoftLayer_Hardware_Server",
+		"reloadOperatingSystem",
+		[]interface{}{"FORCE", &reloadConfig},
+		&sl.Options{Id: &id},
+		&resp)
+}
+
+// hardwareServerConfiguration is a Container_Hardware_Server_Configuration
+// whose hard drives can hold a partition layout, carrying the RAID array type
+// which the generated datatype lacks
+type hardwareServerConfiguration struct {
+	datatypes.Container_Hardware_Server_Configuration
+
+	ArrayTypeId *int                                     `json:"arrayTypeId,omitempty" xmlrpc:"arrayTypeId,omitempty"`
+	HardDrives  []datatypes.Hardware_Component_HardDrive `json:"hardDrives,omitempty" xmlrpc:"hardDrives,omitempty"`
+}
+
+// validateReloadConfig checks the reload configuration against the hard drives
+// of the server
+func validateReloadConfig(sess *session.Session, server datatypes.Hardware_Server, config ReloadConfig) error {
+	if config.ArrayTypeId != 0 {
+		arrayType, err := services.GetConfigurationStorageGroupArrayTypeService(sess).
+			Id(config.ArrayTypeId).
+			GetObject()
+		if err != nil {
+			return err
+		}
+
+		driveCount := len(server.HardDrives)
+		if arrayType.MinimumDrives != nil && driveCount < *arrayType.MinimumDrives {
+			return fmt.Errorf(
+				"%s requires at least %d drives, but server %d has %d",
+				sl.Get(arrayType.Name), *arrayType.MinimumDrives, *server.Id, driveCount)
+		}
+
+		if arrayType.MaximumDrives != nil && *arrayType.MaximumDrives > 0 && driveCount > *arrayType.MaximumDrives {
+			return fmt.Errorf(
+				"%s supports at most %d drives, but server %d has %d",
+				sl.Get(arrayType.Name), *arrayType.MaximumDrives, *server.Id, driveCount)
+		}
+	}
+
+	seen := map[int]bool{}
+	for _, drive := range config.Drives {
+		if drive.DiskNumber < 0 || drive.DiskNumber >= len(server.HardDrives) {
+			return fmt.Errorf("Server %d has no disk number %d", *server.Id, drive.DiskNumber)
+		}
+
+		if seen[drive.DiskNumber] {
+			return fmt.Errorf("Disk number %d is configured more than once", drive.DiskNumber)
+		}
+		seen[drive.DiskNumber] = true
+
+		capacity := driveCapacity(server.HardDrives[drive.DiskNumber])
+
+		var total float64
+		growCount := 0
+		for _, partition := range drive.Partitions {
+			if partition.Name == "" {
+				return fmt.Errorf("A partition on disk %d has no name", drive.DiskNumber)
+			}
+
+			if partition.Grow {
+				growCount++
+			}
+
+			total += partition.SizeGB
+		}
+
+		if growCount > 1 {
+			return fmt.Errorf("Disk %d can only have one grow partition", drive.DiskNumber)
+		}
+
+		if capacity > 0 && total > capacity {
+			return fmt.Errorf(
+				"Partitions on disk %d require %.0f GB, but the drive only has %.0f GB",
+				drive.DiskNumber, total, capacity)
+		}
+	}
+
+	return nil
+}
+
+// driveCapacity returns the capacity of a hard drive in GB, or 0 if unknown
+func driveCapacity(drive datatypes.Hardware_Component) float64 {
+	if drive.Capacity != nil {
+		return float64(*drive.Capacity)
+	}
+
+	return float64(sl.Grab(drive, "HardwareComponentModel.Capacity", datatypes.Float64(0)).(datatypes.Float64))
+}
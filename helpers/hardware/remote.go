@@ -0,0 +1,126 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hardware
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// RebootType selects how a server is rebooted through its remote management
+// card
+type RebootType string
+
+const (
+	// RebootDefault attempts a soft reboot, and falls back to a power cycle
+	RebootDefault RebootType = "default"
+	// RebootSoft gracefully reboots the server
+	RebootSoft RebootType = "soft"
+	// RebootHard resets the server, without waiting for processes to shut down
+	RebootHard RebootType = "hard"
+	// PowerCycle powers the server off and back on through the powerstrip
+	PowerCycle RebootType = "cycle"
+)
+
+// RemoteManagement holds the address and credentials of a server's remote
+// management (IPMI) card
+type RemoteManagement struct {
+	IpAddress  string
+	MacAddress string
+	Accounts   []RemoteManagementAccount
+}
+
+// RemoteManagementAccount is a user of a remote management card
+type RemoteManagementAccount struct {
+	Username string
+	Password string
+}
+
+// GetRemoteManagement returns the IPMI address and credentials of the hardware
+// server with the provided id.
+func GetRemoteManagement(sess *session.Session, id int) (RemoteManagement, error) {
+	server, err := services.GetHardwareServerService(sess).
+		Id(id).
+		Mask("id,networkManagementIpAddress,remoteManagementComponent[ipmiIpAddress,ipmiMacAddress],remoteManagementAccounts[username,password]").
+		GetObject()
+	if err != nil {
+		return RemoteManagement{}, err
+	}
+
+	rm := RemoteManagement{
+		IpAddress:  sl.Grab(server, "RemoteManagementComponent.IpmiIpAddress", "").(string),
+		MacAddress: sl.Grab(server, "RemoteManagementComponent.IpmiMacAddress", "").(string),
+		Accounts:   []RemoteManagementAccount{},
+	}
+
+	if rm.IpAddress == "" {
+		rm.IpAddress = sl.Get(server.NetworkManagementIpAddress, "").(string)
+	}
+
+	for _, account := range server.RemoteManagementAccounts {
+		rm.Accounts = append(rm.Accounts, RemoteManagementAccount{
+			Username: sl.Get(account.Username, "").(string),
+			Password: sl.Get(account.Password, "").(string),
+		})
+	}
+
+	return rm, nil
+}
+
+// GetPowerState returns the power state ("on" or "off") of the hardware server
+// with the provided id, as reported by its remote management card.
+func GetPowerState(sess *session.Session, id int) (string, error) {
+	return services.GetHardwareServerService(sess).Id(id).GetServerPowerState()
+}
+
+// RemoteReboot reboots the hardware server with the provided id through its
+// remote management card. Note that the API refuses a new remote management
+// command within 20 minutes of a successful reboot.
+func RemoteReboot(sess *session.Session, id int, rebootType RebootType) error {
+	service := services.GetHardwareServerService(sess).Id(id)
+
+	var (
+		ok  bool
+		err error
+	)
+
+	switch rebootType {
+	case RebootDefault:
+		ok, err = service.RebootDefault()
+	case RebootSoft:
+		ok, err = service.RebootSoft()
+	case RebootHard:
+		ok, err = service.RebootHard()
+	case PowerCycle:
+		ok, err = service.PowerCycle()
+	default:
+		return fmt.Errorf("Unknown reboot type %s", rebootType)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("Remote management refused the %s reboot of server %d", rebootType, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hardware
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// ListVlanTrunks returns the VLANs trunked to the primary public (or private,
+// when public is false) network component of the hardware server with the
+// provided id.
+func ListVlanTrunks(sess *session.Session, id int, public bool) ([]datatypes.Network_Vlan, error) {
+	componentId, err := getNetworkComponentId(sess, id, public)
+	if err != nil {
+		return nil, err
+	}
+
+	trunks, err := services.GetNetworkComponentService(sess).
+		Id(componentId).
+		Mask("id,networkVlan[id,vlanNumber,name,primaryRouter[hostname]]").
+		GetNetworkVlanTrunks()
+	if err != nil {
+		return nil, err
+	}
+
+	vlans := []datatypes.Network_Vlan{}
+	for _, trunk := range trunks {
+		if trunk.NetworkVlan != nil {
+			vlans = append(vlans, *trunk.NetworkVlan)
+		}
+	}
+
+	return vlans, nil
+}
+
+// AddVlanTrunks trunks the VLANs with the provided ids to the primary public
+// (or private, when public is false) network component of the hardware server
+// with the provided id. It returns the VLANs that were added; VLANs that are
+// already trunked are ignored.
+//
+// The network hardware is configured asynchronously, so the VLANs may not be
+// reachable right away.
+func AddVlanTrunks(sess *session.Session, id int, public bool, vlanIds []int) ([]datatypes.Network_Vlan, error) {
+	componentId, err := getNetworkComponentId(sess, id, public)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.GetNetworkComponentService(sess).
+		Id(componentId).
+		AddNetworkVlanTrunks(vlanTemplates(vlanIds))
+}
+
+// RemoveVlanTrunks removes the VLANs with the provided ids from the trunks of
+// the primary public (or private, when public is false) network component of
+// the hardware server with the provided id. It returns the VLANs that were
+// removed.
+func RemoveVlanTrunks(sess *session.Session, id int, public bool, vlanIds []int) ([]datatypes.Network_Vlan, error) {
+	componentId, err := getNetworkComponentId(sess, id, public)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.GetNetworkComponentService(sess).
+		Id(componentId).
+		RemoveNetworkVlanTrunks(vlanTemplates(vlanIds))
+}
+
+// getNetworkComponentId returns the id of the primary public or private
+// network component of a server
+func getNetworkComponentId(sess *session.Session, id int, public bool) (int, error) {
+	server, err := services.GetHardwareServerService(sess).
+		Id(id).
+		Mask("id,primaryNetworkComponent[id],primaryBackendNetworkComponent[id]").
+		GetObject()
+	if err != nil {
+		return 0, err
+	}
+
+	componentId := sl.Get(networkComponent(server, public).Id, 0).(int)
+	if componentId == 0 {
+		return 0, fmt.Errorf("No primary network component found for server %d", id)
+	}
+
+	return componentId, nil
+}
+
+func vlanTemplates(vlanIds []int) []datatypes.Network_Vlan {
+	vlans := make([]datatypes.Network_Vlan, 0, len(vlanIds))
+	for _, vlanId := range vlanIds {
+		vlans = append(vlans, datatypes.Network_Vlan{Id: sl.Int(vlanId)})
+	}
+
+	return vlans
+}
@@ -0,0 +1,133 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hardware
+
+import (
+	"context"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DefaultPollInterval is the time waited between two checks of a server's
+// state by the functions of this package which wait for a change, when they
+// are passed a zero interval. These functions stop waiting once their context
+// is done, and return the context's error.
+const DefaultPollInterval = 30 * time.Second
+
+// readyMask is the object mask used to check the provisioning state of a server
+const readyMask = "id,hostname,domain,provisionDate," +
+	"activeTransaction[id,transactionGroup[name],transactionStatus[name]]," +
+	"lastTransaction[id,transactionGroup[name],transactionStatus[name]]"
+
+// ProgressFunc is called on every poll of a server with the name of the
+// transaction group and status of the transaction currently running on it.
+// Both are empty when no transaction is active. It may be nil when no
+// progress needs to be reported.
+type ProgressFunc func(transactionGroup string, transactionStatus string)
+
+// WaitForHardwareReady polls the Hardware_Server with the provided id until it
+// has finished provisioning: the server has a provision date, no active
+// transaction, and its last transaction is complete.
+func WaitForHardwareReady(
+	ctx context.Context,
+	sess *session.Session,
+	id int,
+	interval time.Duration,
+	progress ProgressFunc,
+) (datatypes.Hardware_Server, error) {
+
+	return pollServer(ctx, sess, id, readyMask, interval, progress, isReady)
+}
+
+// waitForTransactions polls the server with the provided id until a
+// transaction more recent than lastTransactionId has run, and no transaction
+// is active anymore
+func waitForTransactions(
+	ctx context.Context,
+	sess *session.Session,
+	id int,
+	lastTransactionId int,
+	interval time.Duration,
+	progress ProgressFunc,
+) (datatypes.Hardware_Server, error) {
+
+	return pollServer(ctx, sess, id, readyMask, interval, progress, func(server datatypes.Hardware_Server) bool {
+		return server.ActiveTransaction == nil &&
+			sl.Grab(server, "LastTransaction.Id", 0).(int) != lastTransactionId
+	})
+}
+
+// pollServer retrieves the server with the provided id and object mask every
+// interval, until done returns true for it or ctx is done. The mask must
+// include the active transaction for progress to be reported.
+func pollServer(
+	ctx context.Context,
+	sess *session.Session,
+	id int,
+	mask string,
+	interval time.Duration,
+	progress ProgressFunc,
+	done func(datatypes.Hardware_Server) bool,
+) (datatypes.Hardware_Server, error) {
+
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	service := services.GetHardwareServerService(sess)
+
+	for {
+		server, err := service.Id(id).Mask(mask).GetObject()
+		if err != nil {
+			return datatypes.Hardware_Server{}, err
+		}
+
+		if progress != nil {
+			progress(
+				sl.Grab(server, "ActiveTransaction.TransactionGroup.Name", "").(string),
+				sl.Grab(server, "ActiveTransaction.TransactionStatus.Name", "").(string),
+			)
+		}
+
+		if done(server) {
+			return server, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return datatypes.Hardware_Server{}, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// isReady reports whether the server is provisioned and idle
+func isReady(server datatypes.Hardware_Server) bool {
+	if server.ProvisionDate == nil || server.ActiveTransaction != nil {
+		return false
+	}
+
+	if server.LastTransaction == nil {
+		return true
+	}
+
+	return sl.Grab(server, "LastTransaction.TransactionStatus.Name", "").(string) == "COMPLETE"
+}
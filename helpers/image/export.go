@@ -0,0 +1,75 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// ExportImage copies the image template with the provided id to the provided
+// URI, waits until the copy is complete, and returns the image template,
+// retrieved with ImageMask. The URI is a Cloud Object Storage location (e.g.
+// "cos://us-south/bucket/image.vhd"), written with the provided IBM Cloud API
+// key, or a Swift object storage location (e.g.
+// "swift://account@dal05/container/image.vhd"), in which case apiKey is
+// ignored.
+func ExportImage(
+	ctx context.Context,
+	sess *session.Session,
+	imageId int,
+	uri string,
+	apiKey string,
+	interval time.Duration,
+	progress ProgressFunc,
+) (datatypes.Virtual_Guest_Block_Device_Template_Group, error) {
+
+	configuration := templateConfiguration{
+		Container_Virtual_Guest_Block_Device_Template_Configuration: datatypes.Container_Virtual_Guest_Block_Device_Template_Configuration{
+			Uri: sl.String(uri),
+		},
+	}
+
+	if apiKey != "" {
+		configuration.IbmApiKey = sl.String(apiKey)
+	}
+
+	// Virtual_Guest_Block_Device_Template_Group::copyToExternalSource() is
+	// called directly, as the generated datatype of the configuration cannot
+	// carry the API key
+	var copied bool
+	err := sess.DoRequest(
+		"SoftLayer_Virtual_Guest_Block_Device_Template_Group",
+		"copyToExternalSource",
+		[]interface{}{&configuration},
+		&sl.Options{Id: &imageId},
+		&copied)
+	if err != nil {
+		return datatypes.Virtual_Guest_Block_Device_Template_Group{}, fmt.Errorf("Error exporting image %d to %s: %s", imageId, uri, err)
+	}
+
+	if !copied {
+		return datatypes.Virtual_Guest_Block_Device_Template_Group{}, fmt.Errorf("Export of image %d to %s was not accepted", imageId, uri)
+	}
+
+	return waitForImage(ctx, sess, imageId, interval, progress)
+}
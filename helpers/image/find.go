@@ -0,0 +1,137 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package image
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Visibilities of image templates
+const (
+	VisibilityPrivate = "private"
+	VisibilityPublic  = "public"
+)
+
+// Query holds the criteria used to search the image templates of the account.
+// Zero values are ignored. NamePattern is a shell pattern (e.g. "web-*"),
+// matched as by path.Match. OlderThan selects the images created longer than
+// that ago. Visibility is one of VisibilityPrivate or VisibilityPublic.
+type Query struct {
+	NamePattern string
+	OlderThan   time.Duration
+	Visibility  string
+}
+
+// FindImages returns the image templates of the account matching query,
+// oldest first. The copies of the images in their datacenters are left out.
+// An object mask can be provided as an optional argument, and ImageMask is
+// used otherwise; it must include the name and creation date of the images.
+func FindImages(sess *session.Session, query Query, mask ...string) ([]datatypes.Virtual_Guest_Block_Device_Template_Group, error) {
+	objectMask := ImageMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	if query.NamePattern != "" {
+		if _, err := path.Match(query.NamePattern, ""); err != nil {
+			return nil, fmt.Errorf("Invalid image name pattern %s: %s", query.NamePattern, err)
+		}
+	}
+
+	filters := filter.New(filter.Path("blockDeviceTemplateGroups.parentId").IsNull())
+
+	switch query.Visibility {
+	case "":
+	case VisibilityPrivate:
+		filters = append(filters, filter.Path("blockDeviceTemplateGroups.publicFlag").Eq(0))
+	case VisibilityPublic:
+		filters = append(filters, filter.Path("blockDeviceTemplateGroups.publicFlag").Eq(1))
+	default:
+		return nil, fmt.Errorf("Invalid image visibility %s", query.Visibility)
+	}
+
+	images, err := services.GetAccountService(sess).
+		Mask(objectMask).
+		Filter(filters.Build()).
+		GetBlockDeviceTemplateGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-query.OlderThan)
+
+	result := []datatypes.Virtual_Guest_Block_Device_Template_Group{}
+	for _, image := range images {
+		if query.NamePattern != "" {
+			if matched, _ := path.Match(query.NamePattern, sl.Get(image.Name, "").(string)); !matched {
+				continue
+			}
+		}
+
+		if query.OlderThan != 0 && (image.CreateDate == nil || !image.CreateDate.Before(cutoff)) {
+			continue
+		}
+
+		result = append(result, image)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreateDate != nil && result[j].CreateDate != nil && result[i].CreateDate.Before(result[j].CreateDate.Time)
+	})
+
+	return result, nil
+}
+
+// DeleteImages deletes the image templates with the provided ids, and returns
+// the ids of the deleted images. Images with a transaction in progress, such
+// as an import, export or replication, are skipped. When dryRun is set,
+// nothing is deleted, and the ids of the images which would be deleted are
+// returned.
+func DeleteImages(sess *session.Session, imageIds []int, dryRun bool) ([]int, error) {
+	service := services.GetVirtualGuestBlockDeviceTemplateGroupService(sess)
+
+	deleted := []int{}
+	for _, imageId := range imageIds {
+		image, err := service.Id(imageId).Mask("id,transaction[id]").GetObject()
+		if err != nil {
+			return deleted, err
+		}
+
+		if image.Transaction != nil {
+			continue
+		}
+
+		if !dryRun {
+			if _, err := service.Id(imageId).DeleteObject(); err != nil {
+				return deleted, fmt.Errorf("Error deleting image %d: %s", imageId, err)
+			}
+		}
+
+		deleted = append(deleted, imageId)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,234 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DefaultPollInterval is the time waited between two checks of an image by
+// the functions of this package which wait on its transactions, when they are
+// passed a zero interval. Canceling their context, or letting its deadline
+// pass, stops the wait, and the context's error is returned.
+const DefaultPollInterval = 30 * time.Second
+
+// TransactionGracePeriod is the time waited for the transaction of a request
+// on an image to show, after which the request is assumed to be complete
+const TransactionGracePeriod = 5 * time.Minute
+
+// ImageMask is the default object mask for image templates
+const ImageMask = "id,globalIdentifier,name,note,createDate,publicFlag,status[keyName,name],datacenters[name]," +
+	"transaction[id,transactionGroup[name],transactionStatus[name]]"
+
+// Boot modes of imported images
+const (
+	BootModeHVM = "HVM"
+	BootModePV  = "PV"
+)
+
+// ProgressFunc is called on every poll of an image with the name of the
+// transaction group and status of the transaction currently running on it.
+// Both are empty when no transaction is active. The functions taking a
+// ProgressFunc accept a nil one.
+type ProgressFunc func(transactionGroup string, transactionStatus string)
+
+// ImportConfig describes an image to import.
+//
+// Uri is the location of the image file, either in Cloud Object Storage (e.g.
+// "cos://us-south/bucket/image.vhd"), in which case ApiKey is the IBM Cloud API
+// key used to read it, or in Swift object storage (e.g.
+// "swift://account@dal05/container/image.vhd"). OSCode is the reference code
+// of the operating system of the image (e.g. "UBUNTU_16_64"). An encrypted
+// image is decrypted with the data encryption key WrappedDek, wrapped by the
+// Key Protect root key RootKeyCrn.
+type ImportConfig struct {
+	Name        string
+	Note        string
+	Uri         string
+	ApiKey      string
+	OSCode      string
+	BootMode    string
+	CloudInit   bool
+	IsEncrypted bool
+	RootKeyCrn  string
+	WrappedDek  string
+}
+
+// templateConfiguration is a
+// Container_Virtual_Guest_Block_Device_Template_Configuration carrying the
+// API key and encryption properties, which the generated datatype lacks
+type templateConfiguration struct {
+	datatypes.Container_Virtual_Guest_Block_Device_Template_Configuration
+
+	IbmApiKey   *string `json:"ibmApiKey,omitempty" xmlrpc:"ibmApiKey,omitempty"`
+	IsEncrypted *bool   `json:"isEncrypted,omitempty" xmlrpc:"isEncrypted,omitempty"`
+	CrkCrn      *string `json:"crkCrn,omitempty" xmlrpc:"crkCrn,omitempty"`
+	WrappedDek  *string `json:"wrappedDek,omitempty" xmlrpc:"wrappedDek,omitempty"`
+}
+
+// ImportImage imports the image described by config, waits until the import
+// is complete, and returns the image template, retrieved with ImageMask.
+func ImportImage(
+	ctx context.Context,
+	sess *session.Session,
+	config ImportConfig,
+	interval time.Duration,
+	progress ProgressFunc,
+) (datatypes.Virtual_Guest_Block_Device_Template_Group, error) {
+
+	if config.Name == "" || config.Uri == "" || config.OSCode == "" {
+		return datatypes.Virtual_Guest_Block_Device_Template_Group{}, fmt.Errorf(
+			"A name, URI and operating system are required to import an image")
+	}
+
+	if config.IsEncrypted && (config.RootKeyCrn == "" || config.WrappedDek == "") {
+		return datatypes.Virtual_Guest_Block_Device_Template_Group{}, fmt.Errorf(
+			"A root key CRN and wrapped data encryption key are required to import an encrypted image")
+	}
+
+	configuration := templateConfiguration{
+		Container_Virtual_Guest_Block_Device_Template_Configuration: datatypes.Container_Virtual_Guest_Block_Device_Template_Configuration{
+			Name:                         sl.String(config.Name),
+			Uri:                          sl.String(config.Uri),
+			OperatingSystemReferenceCode: sl.String(config.OSCode),
+			CloudInit:                    sl.Bool(config.CloudInit),
+		},
+		IsEncrypted: sl.Bool(config.IsEncrypted),
+	}
+
+	if config.Note != "" {
+		configuration.Note = sl.String(config.Note)
+	}
+
+	if config.BootMode != "" {
+		configuration.BootMode = sl.String(config.BootMode)
+	}
+
+	if config.ApiKey != "" {
+		configuration.IbmApiKey = sl.String(config.ApiKey)
+	}
+
+	if config.IsEncrypted {
+		configuration.CrkCrn = sl.String(config.RootKeyCrn)
+		configuration.WrappedDek = sl.String(config.WrappedDek)
+	}
+
+	// Virtual_Guest_Block_Device_Template_Group::createFromExternalSource()
+	// is called directly, as the generated datatype of the configuration
+	// cannot carry the API key and encryption properties
+	var image datatypes.Virtual_Guest_Block_Device_Template_Group
+	err := sess.DoRequest(
+		"SoftLayer_Virtual_Guest_Block_Device_Template_Group",
+		"createFromExternalSource",
+		[]interface{}{&configuration},
+		&sl.Options{},
+		&image)
+	if err != nil {
+		return datatypes.Virtual_Guest_Block_Device_Template_Group{}, fmt.Errorf("Error importing image %s: %s", config.Name, err)
+	}
+
+	if image.Id == nil {
+		return datatypes.Virtual_Guest_Block_Device_Template_Group{}, fmt.Errorf("No image was created importing %s", config.Name)
+	}
+
+	return waitForImage(ctx, sess, *image.Id, interval, progress)
+}
+
+// GetImage returns the image template with the provided id. An object mask
+// can be provided as an optional argument, and ImageMask is used otherwise.
+func GetImage(sess *session.Session, imageId int, mask ...string) (datatypes.Virtual_Guest_Block_Device_Template_Group, error) {
+	objectMask := ImageMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	return services.GetVirtualGuestBlockDeviceTemplateGroupService(sess).Id(imageId).Mask(objectMask).GetObject()
+}
+
+// waitForImage polls the image template with the provided id until the
+// transaction started by a request on it is over. The transaction is not
+// attached to the image right after the request, so the image is only deemed
+// ready once a transaction was seen and is over, or when none showed within
+// TransactionGracePeriod.
+func waitForImage(
+	ctx context.Context,
+	sess *session.Session,
+	imageId int,
+	interval time.Duration,
+	progress ProgressFunc,
+) (datatypes.Virtual_Guest_Block_Device_Template_Group, error) {
+
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	service := services.GetVirtualGuestBlockDeviceTemplateGroupService(sess).Id(imageId).Mask(ImageMask)
+	watch := newTransactionWatch()
+
+	for {
+		image, err := service.GetObject()
+		if err != nil {
+			return datatypes.Virtual_Guest_Block_Device_Template_Group{}, err
+		}
+
+		if progress != nil {
+			progress(
+				sl.Grab(image, "Transaction.TransactionGroup.Name", "").(string),
+				sl.Grab(image, "Transaction.TransactionStatus.Name", "").(string),
+			)
+		}
+
+		if watch.done(image.Transaction != nil) {
+			return image, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return datatypes.Virtual_Guest_Block_Device_Template_Group{}, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// transactionWatch tells when the transaction started by a request on an
+// image is over
+type transactionWatch struct {
+	seen     bool
+	deadline time.Time
+}
+
+func newTransactionWatch() *transactionWatch {
+	return &transactionWatch{deadline: time.Now().Add(TransactionGracePeriod)}
+}
+
+// done is called on every poll with whether a transaction runs on the image,
+// and returns whether the transaction is over
+func (w *transactionWatch) done(running bool) bool {
+	if running {
+		w.seen = true
+		return false
+	}
+
+	return w.seen || time.Now().After(w.deadline)
+}
@@ -0,0 +1,277 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/helpers/location"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// replicationMask is the object mask used to check the replication of an
+// image to its datacenters, which is carried out on its child images
+const replicationMask = "id,datacenters[name],transaction[id,transactionGroup[name],transactionStatus[name]]," +
+	"children[id,datacenter[name],transaction[id,transactionGroup[name],transactionStatus[name]]]"
+
+// ListSharedAccounts returns the ids of the accounts the image template with
+// the provided id is shared with
+func ListSharedAccounts(sess *session.Session, imageId int) ([]int, error) {
+	references, err := services.GetVirtualGuestBlockDeviceTemplateGroupService(sess).
+		Id(imageId).
+		Mask("accountId").
+		GetAccountReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(references))
+	for _, reference := range references {
+		ids = append(ids, sl.Get(reference.AccountId, 0).(int))
+	}
+
+	return ids, nil
+}
+
+// ShareImage shares the image template with the provided id with the accounts
+// with the provided ids, skipping the accounts it is already shared with
+func ShareImage(sess *session.Session, imageId int, accountIds ...int) error {
+	shared, err := sharedAccounts(sess, imageId)
+	if err != nil {
+		return err
+	}
+
+	service := services.GetVirtualGuestBlockDeviceTemplateGroupService(sess).Id(imageId)
+	for _, accountId := range accountIds {
+		if shared[accountId] {
+			continue
+		}
+
+		if _, err := service.PermitSharingAccess(sl.Int(accountId)); err != nil {
+			return fmt.Errorf("Error sharing image %d with account %d: %s", imageId, accountId, err)
+		}
+	}
+
+	return nil
+}
+
+// UnshareImage stops sharing the image template with the provided id with the
+// accounts with the provided ids, skipping the accounts it is not shared with
+func UnshareImage(sess *session.Session, imageId int, accountIds ...int) error {
+	shared, err := sharedAccounts(sess, imageId)
+	if err != nil {
+		return err
+	}
+
+	service := services.GetVirtualGuestBlockDeviceTemplateGroupService(sess).Id(imageId)
+	for _, accountId := range accountIds {
+		if !shared[accountId] {
+			continue
+		}
+
+		if _, err := service.DenySharingAccess(sl.Int(accountId)); err != nil {
+			return fmt.Errorf("Error unsharing image %d with account %d: %s", imageId, accountId, err)
+		}
+	}
+
+	return nil
+}
+
+// AddLocations makes the image template with the provided id available in
+// the provided datacenters (e.g. "dal13"), skipping the ones it is already
+// available in, waits until the image is replicated to them, and returns it,
+// retrieved with ImageMask.
+func AddLocations(
+	ctx context.Context,
+	sess *session.Session,
+	imageId int,
+	datacenters []string,
+	interval time.Duration,
+	progress ProgressFunc,
+) (datatypes.Virtual_Guest_Block_Device_Template_Group, error) {
+
+	locations, err := imageLocations(sess, imageId, datacenters, false)
+	if err != nil {
+		return datatypes.Virtual_Guest_Block_Device_Template_Group{}, err
+	}
+
+	if len(locations) > 0 {
+		_, err := services.GetVirtualGuestBlockDeviceTemplateGroupService(sess).Id(imageId).AddLocations(locations)
+		if err != nil {
+			return datatypes.Virtual_Guest_Block_Device_Template_Group{}, fmt.Errorf("Error adding locations to image %d: %s", imageId, err)
+		}
+	}
+
+	return waitForReplication(ctx, sess, imageId, datacenters, true, len(locations) > 0, interval, progress)
+}
+
+// RemoveLocations removes the provided datacenters from the ones the image
+// template with the provided id is available in, skipping the ones it is not
+// available in, waits until the image is removed from them, and returns it,
+// retrieved with ImageMask.
+func RemoveLocations(
+	ctx context.Context,
+	sess *session.Session,
+	imageId int,
+	datacenters []string,
+	interval time.Duration,
+	progress ProgressFunc,
+) (datatypes.Virtual_Guest_Block_Device_Template_Group, error) {
+
+	locations, err := imageLocations(sess, imageId, datacenters, true)
+	if err != nil {
+		return datatypes.Virtual_Guest_Block_Device_Template_Group{}, err
+	}
+
+	if len(locations) > 0 {
+		_, err := services.GetVirtualGuestBlockDeviceTemplateGroupService(sess).Id(imageId).RemoveLocations(locations)
+		if err != nil {
+			return datatypes.Virtual_Guest_Block_Device_Template_Group{}, fmt.Errorf("Error removing locations from image %d: %s", imageId, err)
+		}
+	}
+
+	return waitForReplication(ctx, sess, imageId, datacenters, false, len(locations) > 0, interval, progress)
+}
+
+func sharedAccounts(sess *session.Session, imageId int) (map[int]bool, error) {
+	ids, err := ListSharedAccounts(sess, imageId)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := map[int]bool{}
+	for _, id := range ids {
+		shared[id] = true
+	}
+
+	return shared, nil
+}
+
+// imageLocations resolves the provided datacenters which the image template is
+// available in, when available is set, or the ones it is not available in
+// otherwise
+func imageLocations(sess *session.Session, imageId int, datacenters []string, available bool) ([]datatypes.Location, error) {
+	image, err := services.GetVirtualGuestBlockDeviceTemplateGroupService(sess).
+		Id(imageId).
+		Mask("id,datacenters[name]").
+		GetObject()
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[string]bool{}
+	for _, dc := range image.Datacenters {
+		current[sl.Get(dc.Name, "").(string)] = true
+	}
+
+	locations := []datatypes.Location{}
+	for _, datacenter := range datacenters {
+		if current[datacenter] != available {
+			continue
+		}
+
+		dc, err := location.GetLocationByName(sess, datacenter, "id")
+		if err != nil {
+			return nil, err
+		}
+
+		locations = append(locations, datatypes.Location{Id: dc.Id})
+	}
+
+	return locations, nil
+}
+
+// waitForReplication polls the image template with the provided id until it
+// is available in all the provided datacenters, when available is set, or in
+// none of them otherwise, and no transaction runs on it or on its child
+// images, and returns it, retrieved with ImageMask. When changed is set, a
+// transaction must also have been seen, or TransactionGracePeriod have
+// elapsed, as the replication does not start right after the request.
+func waitForReplication(
+	ctx context.Context,
+	sess *session.Session,
+	imageId int,
+	datacenters []string,
+	available bool,
+	changed bool,
+	interval time.Duration,
+	progress ProgressFunc,
+) (datatypes.Virtual_Guest_Block_Device_Template_Group, error) {
+
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	service := services.GetVirtualGuestBlockDeviceTemplateGroupService(sess).Id(imageId).Mask(replicationMask)
+	watch := newTransactionWatch()
+
+	for {
+		image, err := service.GetObject()
+		if err != nil {
+			return datatypes.Virtual_Guest_Block_Device_Template_Group{}, err
+		}
+
+		transaction := image.Transaction
+		for _, child := range image.Children {
+			if transaction == nil {
+				transaction = child.Transaction
+			}
+		}
+
+		if progress != nil {
+			group, status := "", ""
+			if transaction != nil {
+				group = sl.Grab(*transaction, "TransactionGroup.Name", "").(string)
+				status = sl.Grab(*transaction, "TransactionStatus.Name", "").(string)
+			}
+			progress(group, status)
+		}
+
+		over := watch.done(transaction != nil) || (!changed && transaction == nil)
+		if over && hasDatacenters(image, datacenters, available) {
+			return GetImage(sess, imageId)
+		}
+
+		select {
+		case <-ctx.Done():
+			return datatypes.Virtual_Guest_Block_Device_Template_Group{}, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// hasDatacenters returns whether the image template is available in all the
+// provided datacenters, when available is set, or in none of them otherwise
+func hasDatacenters(image datatypes.Virtual_Guest_Block_Device_Template_Group, datacenters []string, available bool) bool {
+	current := map[string]bool{}
+	for _, dc := range image.Datacenters {
+		current[sl.Get(dc.Name, "").(string)] = true
+	}
+
+	for _, datacenter := range datacenters {
+		if current[datacenter] != available {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,247 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ipsec
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DefaultPollInterval is the time waited between two checks of a tunnel
+// context by ApplyConfiguration, when it is passed a zero interval. Canceling
+// the context stops the wait, which then returns the context's error.
+const DefaultPollInterval = 30 * time.Second
+
+// Phase holds the IKE parameters of one phase of the tunnel negotiation. Zero
+// values, and a nil PerfectForwardSecrecy, leave the current parameters of the
+// tunnel unchanged. PerfectForwardSecrecy only applies to phase two.
+type Phase struct {
+	Authentication        string
+	Encryption            string
+	DiffieHellmanGroup    int
+	Keylife               int
+	PerfectForwardSecrecy *bool
+}
+
+// TunnelConfig describes the configuration of an IPsec tunnel context.
+//
+// RemoteSubnets holds the CIDR blocks of the customer side of the tunnel,
+// while ServiceSubnetIds and PrivateSubnetIds hold the ids of the SoftLayer
+// subnets reachable through it. Subnets already attached to the tunnel are
+// left as they are.
+type TunnelConfig struct {
+	CustomerPeerIpAddress string
+	PresharedKey          string
+	PhaseOne              Phase
+	PhaseTwo              Phase
+	RemoteSubnets         []string
+	ServiceSubnetIds      []int
+	PrivateSubnetIds      []int
+}
+
+// ConfigureTunnel updates the parameters and subnets of the tunnel context
+// with the provided id. The configuration only reaches the tunnel device once
+// applied, see ApplyConfiguration.
+func ConfigureTunnel(sess *session.Session, contextId int, config TunnelConfig) error {
+	service := services.GetNetworkTunnelModuleContextService(sess).Id(contextId)
+
+	tunnel, err := service.
+		Mask("id,accountId,customerSubnets[id,networkIdentifier,cidr],serviceSubnets[id],internalSubnets[id]").
+		GetObject()
+	if err != nil {
+		return err
+	}
+
+	template := datatypes.Network_Tunnel_Module_Context{}
+	if config.CustomerPeerIpAddress != "" {
+		template.CustomerPeerIpAddress = sl.String(config.CustomerPeerIpAddress)
+	}
+	if config.PresharedKey != "" {
+		template.PresharedKey = sl.String(config.PresharedKey)
+	}
+	setPhaseOne(&template, config.PhaseOne)
+	setPhaseTwo(&template, config.PhaseTwo)
+
+	if _, err := service.EditObject(&template); err != nil {
+		return fmt.Errorf("Error editing tunnel context %d: %s", contextId, err)
+	}
+
+	remote := map[string]bool{}
+	for _, subnet := range tunnel.CustomerSubnets {
+		remote[fmt.Sprintf("%s/%d", sl.Get(subnet.NetworkIdentifier, ""), sl.Get(subnet.Cidr, 0))] = true
+	}
+
+	for _, cidr := range config.RemoteSubnets {
+		if remote[cidr] {
+			continue
+		}
+
+		if err := addRemoteSubnet(sess, service, sl.Get(tunnel.AccountId, 0).(int), cidr); err != nil {
+			return err
+		}
+	}
+
+	for _, subnetId := range config.ServiceSubnetIds {
+		if hasSubnet(tunnel.ServiceSubnets, subnetId) {
+			continue
+		}
+
+		if _, err := service.AddServiceSubnetToNetworkTunnel(sl.Int(subnetId)); err != nil {
+			return fmt.Errorf("Error adding service subnet %d to tunnel context %d: %s", subnetId, contextId, err)
+		}
+	}
+
+	for _, subnetId := range config.PrivateSubnetIds {
+		if hasSubnet(tunnel.InternalSubnets, subnetId) {
+			continue
+		}
+
+		if _, err := service.AddPrivateSubnetToNetworkTunnel(sl.Int(subnetId)); err != nil {
+			return fmt.Errorf("Error adding private subnet %d to tunnel context %d: %s", subnetId, contextId, err)
+		}
+	}
+
+	return nil
+}
+
+// ApplyConfiguration applies the configuration of the tunnel context with the
+// provided id to the tunnel device, and waits until the transaction applying
+// it is over. The transaction is told apart from the previous ones by the
+// transaction history of the tunnel, as it may not be queued yet right after
+// the request.
+func ApplyConfiguration(ctx context.Context, sess *session.Session, contextId int, interval time.Duration) error {
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	service := services.GetNetworkTunnelModuleContextService(sess).Id(contextId)
+	mask := "id,transactionHistoryCount,activeTransaction[id,transactionStatus[name]]"
+
+	tunnel, err := service.Mask(mask).GetObject()
+	if err != nil {
+		return err
+	}
+	previous := sl.Get(tunnel.TransactionHistoryCount, uint(0)).(uint)
+
+	if _, err := service.ApplyConfigurationsToDevice(); err != nil {
+		return err
+	}
+
+	for {
+		tunnel, err := service.Mask(mask).GetObject()
+		if err != nil {
+			return err
+		}
+
+		if tunnel.ActiveTransaction == nil && sl.Get(tunnel.TransactionHistoryCount, uint(0)).(uint) > previous {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// addRemoteSubnet registers the customer subnet with the provided CIDR block,
+// and adds it to the tunnel context
+func addRemoteSubnet(
+	sess *session.Session,
+	service services.Network_Tunnel_Module_Context,
+	accountId int,
+	cidr string,
+) error {
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return fmt.Errorf("Invalid remote subnet %s: %s", cidr, err)
+	}
+
+	size, _ := network.Mask.Size()
+
+	subnet, err := services.GetNetworkCustomerSubnetService(sess).CreateObject(&datatypes.Network_Customer_Subnet{
+		AccountId:         sl.Int(accountId),
+		NetworkIdentifier: sl.String(network.IP.String()),
+		Cidr:              sl.Int(size),
+	})
+	if err != nil {
+		return fmt.Errorf("Error creating remote subnet %s: %s", cidr, err)
+	}
+
+	if _, err := service.AddCustomerSubnetToNetworkTunnel(subnet.Id); err != nil {
+		return fmt.Errorf("Error adding remote subnet %s to the tunnel context: %s", cidr, err)
+	}
+
+	return nil
+}
+
+func setPhaseOne(template *datatypes.Network_Tunnel_Module_Context, phase Phase) {
+	if phase.Authentication != "" {
+		template.PhaseOneAuthentication = sl.String(phase.Authentication)
+	}
+	if phase.Encryption != "" {
+		template.PhaseOneEncryption = sl.String(phase.Encryption)
+	}
+	if phase.DiffieHellmanGroup != 0 {
+		template.PhaseOneDiffieHellmanGroup = sl.Int(phase.DiffieHellmanGroup)
+	}
+	if phase.Keylife != 0 {
+		template.PhaseOneKeylife = sl.Int(phase.Keylife)
+	}
+}
+
+func setPhaseTwo(template *datatypes.Network_Tunnel_Module_Context, phase Phase) {
+	if phase.Authentication != "" {
+		template.PhaseTwoAuthentication = sl.String(phase.Authentication)
+	}
+	if phase.Encryption != "" {
+		template.PhaseTwoEncryption = sl.String(phase.Encryption)
+	}
+	if phase.DiffieHellmanGroup != 0 {
+		template.PhaseTwoDiffieHellmanGroup = sl.Int(phase.DiffieHellmanGroup)
+	}
+	if phase.Keylife != 0 {
+		template.PhaseTwoKeylife = sl.Int(phase.Keylife)
+	}
+
+	if phase.PerfectForwardSecrecy != nil {
+		pfs := 0
+		if *phase.PerfectForwardSecrecy {
+			pfs = 1
+		}
+		template.PhaseTwoPerfectForwardSecrecy = sl.Int(pfs)
+	}
+}
+
+func hasSubnet(subnets []datatypes.Network_Subnet, id int) bool {
+	for _, subnet := range subnets {
+		if subnet.Id != nil && *subnet.Id == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,264 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalancer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// HealthMonitor holds the parameters of the health checks of a pool of
+// members. Empty fields keep their current value. UrlPath only applies to
+// HTTP health checks.
+type HealthMonitor struct {
+	Interval   time.Duration
+	Timeout    time.Duration
+	MaxRetries int
+	UrlPath    string
+}
+
+// lbaasHealthMonitor is the SoftLayer_Network_LBaaS_HealthMonitor type, which
+// is not part of the generated datatypes
+type lbaasHealthMonitor struct {
+	Uuid       *string `json:"uuid,omitempty" xmlrpc:"uuid,omitempty"`
+	Interval   *int    `json:"interval,omitempty" xmlrpc:"interval,omitempty"`
+	Timeout    *int    `json:"timeout,omitempty" xmlrpc:"timeout,omitempty"`
+	MaxRetries *int    `json:"maxRetries,omitempty" xmlrpc:"maxRetries,omitempty"`
+	UrlPath    *string `json:"urlPath,omitempty" xmlrpc:"urlPath,omitempty"`
+}
+
+// lbaasHealthMonitorLoadBalancer is a load balancer whose pools carry their
+// health monitor, which the generated datatypes lack
+type lbaasHealthMonitorLoadBalancer struct {
+	Listeners []struct {
+		DefaultPool *struct {
+			Protocol      *string             `json:"protocol,omitempty" xmlrpc:"protocol,omitempty"`
+			ProtocolPort  *int                `json:"protocolPort,omitempty" xmlrpc:"protocolPort,omitempty"`
+			HealthMonitor *lbaasHealthMonitor `json:"healthMonitor,omitempty" xmlrpc:"healthMonitor,omitempty"`
+		} `json:"defaultPool,omitempty" xmlrpc:"defaultPool,omitempty"`
+	} `json:"listeners,omitempty" xmlrpc:"listeners,omitempty"`
+}
+
+// lbaasHealthMonitorConfiguration is the
+// SoftLayer_Network_LBaaS_LoadBalancerHealthMonitorConfiguration type, which
+// is not part of the generated datatypes
+type lbaasHealthMonitorConfiguration struct {
+	BackendPort       *int    `json:"backendPort,omitempty" xmlrpc:"backendPort,omitempty"`
+	BackendProtocol   *string `json:"backendProtocol,omitempty" xmlrpc:"backendProtocol,omitempty"`
+	HealthMonitorUuid *string `json:"healthMonitorUuid,omitempty" xmlrpc:"healthMonitorUuid,omitempty"`
+	Interval          *int    `json:"interval,omitempty" xmlrpc:"interval,omitempty"`
+	MaxRetries        *int    `json:"maxRetries,omitempty" xmlrpc:"maxRetries,omitempty"`
+	Timeout           *int    `json:"timeout,omitempty" xmlrpc:"timeout,omitempty"`
+	UrlPath           *string `json:"urlPath,omitempty" xmlrpc:"urlPath,omitempty"`
+}
+
+// Tokens of the key names of the attributes of the legacy health checks
+// holding each parameter
+var healthAttributeTokens = map[string]string{
+	"interval":   "INTERVAL",
+	"timeout":    "TIMEOUT",
+	"maxRetries": "RETRIES",
+	"urlPath":    "URL",
+}
+
+// UpdateHealthMonitor updates the health monitor of the pool of the load
+// balancer with the provided UUID which forwards the traffic to backendPort
+func UpdateHealthMonitor(sess *session.Session, uuid string, backendPort int, monitor HealthMonitor) error {
+	current, err := healthMonitors(sess, uuid)
+	if err != nil {
+		return err
+	}
+
+	configuration, ok := current[backendPort]
+	if !ok {
+		return fmt.Errorf("No pool with a health monitor found for port %d of load balancer %s", backendPort, uuid)
+	}
+
+	return updateHealthMonitors(sess, uuid, []lbaasHealthMonitorConfiguration{monitor.apply(configuration)})
+}
+
+// healthMonitors returns the current configuration of the health monitors of
+// the pools of the load balancer with the provided UUID, by backend port
+func healthMonitors(sess *session.Session, uuid string) (map[int]lbaasHealthMonitorConfiguration, error) {
+	// Network_LBaaS_LoadBalancer::getLoadBalancer() is called directly, as
+	// the generated datatypes lack the health monitors of the pools
+	var lb lbaasHealthMonitorLoadBalancer
+	err := sess.DoRequest(
+		"SoftLayer_Network_LBaaS_LoadBalancer",
+		"getLoadBalancer",
+		[]interface{}{uuid},
+		&sl.Options{Mask: "mask[listeners[defaultPool[protocol,protocolPort," +
+			"healthMonitor[uuid,interval,timeout,maxRetries,urlPath]]]]"},
+		&lb)
+	if err != nil {
+		return nil, err
+	}
+
+	configurations := map[int]lbaasHealthMonitorConfiguration{}
+	for _, listener := range lb.Listeners {
+		pool := listener.DefaultPool
+		if pool == nil || pool.HealthMonitor == nil {
+			continue
+		}
+
+		current := pool.HealthMonitor
+		configurations[sl.Get(pool.ProtocolPort, 0).(int)] = lbaasHealthMonitorConfiguration{
+			BackendPort:       pool.ProtocolPort,
+			BackendProtocol:   pool.Protocol,
+			HealthMonitorUuid: current.Uuid,
+			Interval:          current.Interval,
+			Timeout:           current.Timeout,
+			MaxRetries:        current.MaxRetries,
+			UrlPath:           current.UrlPath,
+		}
+	}
+
+	return configurations, nil
+}
+
+// apply returns the configuration with the non-empty fields of the monitor
+// applied to it
+func (monitor HealthMonitor) apply(configuration lbaasHealthMonitorConfiguration) lbaasHealthMonitorConfiguration {
+	if monitor.Interval != 0 {
+		configuration.Interval = sl.Int(int(monitor.Interval.Seconds()))
+	}
+
+	if monitor.Timeout != 0 {
+		configuration.Timeout = sl.Int(int(monitor.Timeout.Seconds()))
+	}
+
+	if monitor.MaxRetries != 0 {
+		configuration.MaxRetries = sl.Int(monitor.MaxRetries)
+	}
+
+	if monitor.UrlPath != "" {
+		configuration.UrlPath = sl.String(monitor.UrlPath)
+	}
+
+	return configuration
+}
+
+func updateHealthMonitors(sess *session.Session, uuid string, configurations []lbaasHealthMonitorConfiguration) error {
+	// Network_LBaaS_HealthMonitor::updateLoadBalancerHealthMonitors() is
+	// called directly, as the service is missing from the generated services
+	var result interface{}
+	err := sess.DoRequest(
+		"SoftLayer_Network_LBaaS_HealthMonitor",
+		"updateLoadBalancerHealthMonitors",
+		[]interface{}{uuid, configurations},
+		&sl.Options{},
+		&result)
+	if err != nil {
+		return fmt.Errorf("Error updating the health monitors of load balancer %s: %s", uuid, err)
+	}
+
+	return nil
+}
+
+// UpdateLocalHealthChecks updates the health checks of the services of the
+// service group with the provided id, of the legacy local load balancer whose
+// virtual IP address has the provided id. An error is returned, without
+// updating the health checks, when a parameter is set which they do not
+// support.
+func UpdateLocalHealthChecks(sess *session.Session, vipId int, serviceGroupId int, monitor HealthMonitor) error {
+	service := services.GetNetworkApplicationDeliveryControllerLoadBalancerVirtualIpAddressService(sess).Id(vipId)
+
+	vip, err := service.
+		Mask("id,virtualServers[id,port,allocation,routingMethodId,serviceGroups[id,routingMethodId,routingTypeId," +
+			"services[id,enabled,port,ipAddressId,healthChecks[id,healthCheckTypeId," +
+			"attributes[id,healthAttributeTypeId,value,type[keyname]]]]]]").
+		GetObject()
+	if err != nil {
+		return err
+	}
+
+	values := map[string]string{}
+	if monitor.Interval != 0 {
+		values["interval"] = strconv.Itoa(int(monitor.Interval.Seconds()))
+	}
+
+	if monitor.Timeout != 0 {
+		values["timeout"] = strconv.Itoa(int(monitor.Timeout.Seconds()))
+	}
+
+	if monitor.MaxRetries != 0 {
+		values["maxRetries"] = strconv.Itoa(monitor.MaxRetries)
+	}
+
+	if monitor.UrlPath != "" {
+		values["urlPath"] = monitor.UrlPath
+	}
+
+	// The attributes are updated in place, as the copies made by the loops
+	// share them with vip, which is then sent back as a whole
+	found := false
+	applied := map[string]bool{}
+
+	for _, server := range vip.VirtualServers {
+		for _, group := range server.ServiceGroups {
+			if sl.Get(group.Id, 0).(int) != serviceGroupId {
+				continue
+			}
+			found = true
+
+			for _, svc := range group.Services {
+				for _, check := range svc.HealthChecks {
+					for i, attribute := range check.Attributes {
+						keyName := strings.ToUpper(sl.Grab(attribute, "Type.Keyname", "").(string))
+
+						for parameter, value := range values {
+							if strings.Contains(keyName, healthAttributeTokens[parameter]) {
+								check.Attributes[i].Value = sl.String(value)
+								applied[parameter] = true
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("No service group %d found in load balancer %d", serviceGroupId, vipId)
+	}
+
+	unsupported := []string{}
+	for parameter := range values {
+		if !applied[parameter] {
+			unsupported = append(unsupported, parameter)
+		}
+	}
+	sort.Strings(unsupported)
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("The health checks of service group %d do not support %s",
+			serviceGroupId, strings.Join(unsupported, ", "))
+	}
+
+	if _, err := service.EditObject(&vip); err != nil {
+		return fmt.Errorf("Error updating the health checks of load balancer %d: %s", vipId, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/helpers/product"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// PackageKeyName is the key name of the package load balancers are ordered
+// from
+const PackageKeyName = "LBAAS"
+
+// DefaultPollInterval is the time waited between two checks of a load
+// balancer's state by CreateLoadBalancer, when it is passed a zero interval.
+// The wait ends with the context's error once the context is done.
+const DefaultPollInterval = 30 * time.Second
+
+// LoadBalancerMask is the object mask used to retrieve load balancers
+const LoadBalancerMask = "id,uuid,name,description,isPublic,provisioningStatus,operatingStatus," +
+	"datacenter[name],ipAddress[ipAddress]"
+
+// Listener describes how a load balancer forwards the traffic received on a
+// frontend port to its members. Method is the load balancing method (e.g.
+// "ROUNDROBIN", "LEASTCONNECTION" or "WEIGHTED_RR").
+type Listener struct {
+	FrontendProtocol string
+	FrontendPort     int
+	BackendProtocol  string
+	BackendPort      int
+	Method           string
+	MaxConn          int
+}
+
+// LBConfig describes a load balancer to create. SubnetIds holds the ids of
+// the private subnets the load balancer is attached to.
+type LBConfig struct {
+	Name        string
+	Description string
+	Datacenter  string
+	Public      bool
+	SubnetIds   []int
+	Listeners   []Listener
+}
+
+// lbaasOrder is a Container_Product_Order_Network_LoadBalancer_AsAService
+// carrying the public flag of the load balancer, which the generated datatype
+// lacks
+type lbaasOrder struct {
+	datatypes.Container_Product_Order_Network_LoadBalancer_AsAService
+
+	IsPublic *bool `json:"isPublic,omitempty" xmlrpc:"isPublic,omitempty"`
+}
+
+// CreateLoadBalancer orders a load balancer, waits until it is active, and
+// returns it. Its UUID and address are held in the Uuid and
+// IpAddress.IpAddress properties.
+func CreateLoadBalancer(
+	ctx context.Context,
+	sess *session.Session,
+	config LBConfig,
+	interval time.Duration,
+) (datatypes.Network_LBaaS_LoadBalancer, error) {
+
+	pkg, err := product.GetPackageByKeyName(sess, PackageKeyName, "id")
+	if err != nil {
+		return datatypes.Network_LBaaS_LoadBalancer{}, err
+	}
+
+	items, err := product.GetCachedPackageItems(sess, *pkg.Id)
+	if err != nil {
+		return datatypes.Network_LBaaS_LoadBalancer{}, err
+	}
+
+	// A load balancer is billed for all the items of the package
+	prices := []datatypes.Product_Item_Price{}
+	for _, item := range items {
+		if price, ok := product.SelectItemPrice(item, nil, "", false); ok {
+			prices = append(prices, datatypes.Product_Item_Price{Id: price.Id})
+		}
+	}
+
+	order := lbaasOrder{IsPublic: sl.Bool(config.Public)}
+	order.ComplexType = sl.String("SoftLayer_Container_Product_Order_Network_LoadBalancer_AsAService")
+	order.PackageId = pkg.Id
+	order.Location = sl.String(config.Datacenter)
+	order.UseHourlyPricing = sl.Bool(true)
+	order.Prices = prices
+	order.Name = sl.String(config.Name)
+	order.Description = sl.String(config.Description)
+
+	for _, subnetId := range config.SubnetIds {
+		order.Subnets = append(order.Subnets, datatypes.Network_Subnet{Id: sl.Int(subnetId)})
+	}
+
+	for _, listener := range config.Listeners {
+		order.ProtocolConfigurations = append(order.ProtocolConfigurations, listener.protocolConfiguration())
+	}
+
+	// Load balancer names are not unique: the load balancers already bearing
+	// the name are told apart from the one being ordered by their UUID
+	existing, err := getLoadBalancersByName(sess, config.Name)
+	if err != nil {
+		return datatypes.Network_LBaaS_LoadBalancer{}, err
+	}
+
+	known := map[string]bool{}
+	for _, lb := range existing {
+		known[sl.Get(lb.Uuid, "").(string)] = true
+	}
+
+	// Product_Order::placeOrder() is called directly, as the generated
+	// datatype of the order cannot carry the public flag
+	var receipt datatypes.Container_Product_Order_Receipt
+	err = sess.DoRequest("SoftLayer_Product_Order", "placeOrder", []interface{}{&order, false}, &sl.Options{}, &receipt)
+	if err != nil {
+		return datatypes.Network_LBaaS_LoadBalancer{}, err
+	}
+
+	return waitForActive(ctx, sess, config.Name, known, interval)
+}
+
+// GetLoadBalancer returns the load balancer with the provided UUID
+func GetLoadBalancer(sess *session.Session, uuid string) (datatypes.Network_LBaaS_LoadBalancer, error) {
+	return services.GetNetworkLBaaSLoadBalancerService(sess).
+		Mask(LoadBalancerMask).
+		GetLoadBalancer(sl.String(uuid))
+}
+
+// getLoadBalancersByName returns the load balancers of the account with the
+// provided name
+func getLoadBalancersByName(sess *session.Session, name string) ([]datatypes.Network_LBaaS_LoadBalancer, error) {
+	return services.GetNetworkLBaaSLoadBalancerService(sess).
+		Mask(LoadBalancerMask).
+		Filter(filter.Build(filter.Path("name").Eq(name))).
+		GetAllObjects()
+}
+
+// waitForActive polls the load balancers of the account until the one with
+// the provided name, and a UUID other than the known ones, is active
+func waitForActive(
+	ctx context.Context,
+	sess *session.Session,
+	name string,
+	known map[string]bool,
+	interval time.Duration,
+) (datatypes.Network_LBaaS_LoadBalancer, error) {
+
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	for {
+		lbs, err := getLoadBalancersByName(sess, name)
+		if err != nil {
+			return datatypes.Network_LBaaS_LoadBalancer{}, err
+		}
+
+		var created []datatypes.Network_LBaaS_LoadBalancer
+		for _, lb := range lbs {
+			if !known[sl.Get(lb.Uuid, "").(string)] {
+				created = append(created, lb)
+			}
+		}
+
+		if len(created) > 1 {
+			return datatypes.Network_LBaaS_LoadBalancer{},
+				fmt.Errorf("Several load balancers named %s were created while waiting for the order", name)
+		}
+
+		if len(created) == 1 {
+			switch sl.Get(created[0].ProvisioningStatus, "").(string) {
+			case "ACTIVE":
+				return created[0], nil
+			case "ERROR":
+				return datatypes.Network_LBaaS_LoadBalancer{},
+					fmt.Errorf("Load balancer %s failed to provision: %s", name, sl.Get(created[0].PreviousErrorText, ""))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return datatypes.Network_LBaaS_LoadBalancer{}, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (l Listener) protocolConfiguration() datatypes.Network_LBaaS_LoadBalancerProtocolConfiguration {
+	configuration := datatypes.Network_LBaaS_LoadBalancerProtocolConfiguration{
+		FrontendProtocol: sl.String(l.FrontendProtocol),
+		FrontendPort:     sl.Int(l.FrontendPort),
+		BackendProtocol:  sl.String(l.BackendProtocol),
+		BackendPort:      sl.Int(l.BackendPort),
+	}
+
+	if l.Method != "" {
+		configuration.LoadBalancingMethod = sl.String(l.Method)
+	}
+
+	if l.MaxConn != 0 {
+		configuration.MaxConn = sl.Int(l.MaxConn)
+	}
+
+	return configuration
+}
@@ -0,0 +1,263 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalancer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Member is a backend server of a load balancer, identified by its private IP
+// address
+type Member struct {
+	Address string
+	Weight  int
+}
+
+// Changes counts the changes made to reconcile a load balancer with its
+// desired state
+type Changes struct {
+	Added   int
+	Updated int
+	Removed int
+}
+
+// EnsureListeners makes the listeners of the load balancer with the provided
+// UUID match desired. Listeners are identified by their frontend protocol and
+// port: missing listeners are added, listeners whose backend differs from the
+// desired one are updated, and the other listeners are removed, along with
+// their pools.
+func EnsureListeners(sess *session.Session, uuid string, desired []Listener) (Changes, error) {
+	lb, err := services.GetNetworkLBaaSLoadBalancerService(sess).
+		Mask("id,uuid,listeners[uuid,protocol,protocolPort,connectionLimit," +
+			"defaultPool[protocol,protocolPort,loadBalancingAlgorithm]]").
+		GetLoadBalancer(sl.String(uuid))
+	if err != nil {
+		return Changes{}, err
+	}
+
+	current := map[string]datatypes.Network_LBaaS_Listener{}
+	for _, listener := range lb.Listeners {
+		key := listenerKey(sl.Get(listener.Protocol, "").(string), sl.Get(listener.ProtocolPort, 0).(int))
+		current[key] = listener
+	}
+
+	changes := Changes{}
+	configurations := []datatypes.Network_LBaaS_LoadBalancerProtocolConfiguration{}
+	wanted := map[string]bool{}
+
+	for _, listener := range desired {
+		key := listenerKey(listener.FrontendProtocol, listener.FrontendPort)
+		wanted[key] = true
+
+		existing, ok := current[key]
+		if !ok {
+			configurations = append(configurations, listener.protocolConfiguration())
+			changes.Added++
+			continue
+		}
+
+		// An empty method or connection limit keeps the current one
+		have, want := listenerOf(existing), listener.normalize()
+		if want.Method == "" {
+			want.Method = have.Method
+		}
+		if want.MaxConn == 0 {
+			want.MaxConn = have.MaxConn
+		}
+
+		if have == want {
+			continue
+		}
+
+		configuration := listener.protocolConfiguration()
+		configuration.ListenerUuid = existing.Uuid
+		configurations = append(configurations, configuration)
+		changes.Updated++
+	}
+
+	removed := []string{}
+	for key, listener := range current {
+		if !wanted[key] && listener.Uuid != nil {
+			removed = append(removed, *listener.Uuid)
+		}
+	}
+
+	service := services.GetNetworkLBaaSListenerService(sess)
+
+	if len(removed) > 0 {
+		if _, err := service.DeleteLoadBalancerProtocols(sl.String(uuid), removed); err != nil {
+			return changes, fmt.Errorf("Error removing listeners from load balancer %s: %s", uuid, err)
+		}
+		changes.Removed = len(removed)
+	}
+
+	if len(configurations) > 0 {
+		if _, err := service.UpdateLoadBalancerProtocols(sl.String(uuid), configurations); err != nil {
+			return changes, fmt.Errorf("Error updating listeners of load balancer %s: %s", uuid, err)
+		}
+	}
+
+	return changes, nil
+}
+
+// EnsureMembers makes the members of the load balancer with the provided UUID
+// match desired: missing members are added, members whose weight differs are
+// updated, and the other members are removed.
+func EnsureMembers(sess *session.Session, uuid string, desired []Member) (Changes, error) {
+	lb, err := services.GetNetworkLBaaSLoadBalancerService(sess).
+		Mask("id,uuid,members[uuid,address,weight]").
+		GetLoadBalancer(sl.String(uuid))
+	if err != nil {
+		return Changes{}, err
+	}
+
+	current := map[string]datatypes.Network_LBaaS_Member{}
+	for _, member := range lb.Members {
+		current[sl.Get(member.Address, "").(string)] = member
+	}
+
+	changes := Changes{}
+	added := []datatypes.Network_LBaaS_LoadBalancerServerInstanceInfo{}
+	updated := []datatypes.Network_LBaaS_Member{}
+	wanted := map[string]bool{}
+
+	for _, member := range desired {
+		wanted[member.Address] = true
+
+		existing, ok := current[member.Address]
+		if !ok {
+			added = append(added, datatypes.Network_LBaaS_LoadBalancerServerInstanceInfo{
+				PrivateIpAddress: sl.String(member.Address),
+				Weight:           sl.Int(member.Weight),
+			})
+			continue
+		}
+
+		if sl.Get(existing.Weight, 0).(int) != member.Weight {
+			updated = append(updated, datatypes.Network_LBaaS_Member{
+				Uuid:   existing.Uuid,
+				Weight: sl.Int(member.Weight),
+			})
+		}
+	}
+
+	removed := []string{}
+	for address, member := range current {
+		if !wanted[address] && member.Uuid != nil {
+			removed = append(removed, *member.Uuid)
+		}
+	}
+
+	service := services.GetNetworkLBaaSMemberService(sess)
+
+	if len(removed) > 0 {
+		if _, err := service.DeleteLoadBalancerMembers(sl.String(uuid), removed); err != nil {
+			return changes, fmt.Errorf("Error removing members from load balancer %s: %s", uuid, err)
+		}
+		changes.Removed = len(removed)
+	}
+
+	if len(updated) > 0 {
+		if _, err := service.UpdateLoadBalancerMembers(sl.String(uuid), updated); err != nil {
+			return changes, fmt.Errorf("Error updating members of load balancer %s: %s", uuid, err)
+		}
+		changes.Updated = len(updated)
+	}
+
+	if len(added) > 0 {
+		if _, err := service.AddLoadBalancerMembers(sl.String(uuid), added); err != nil {
+			return changes, fmt.Errorf("Error adding members to load balancer %s: %s", uuid, err)
+		}
+		changes.Added = len(added)
+	}
+
+	return changes, nil
+}
+
+// EnsureHealthMonitors makes the health monitors of the pools of the load
+// balancer with the provided UUID match desired, which holds the health
+// monitor of the pool forwarding the traffic to each backend port. Empty
+// fields of a desired monitor keep their current value. Health monitors come
+// and go with their pools, so they are only ever updated, and an error is
+// returned for a backend port without a pool.
+func EnsureHealthMonitors(sess *session.Session, uuid string, desired map[int]HealthMonitor) (Changes, error) {
+	current, err := healthMonitors(sess, uuid)
+	if err != nil {
+		return Changes{}, err
+	}
+
+	ports := make([]int, 0, len(desired))
+	for port := range desired {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+
+	updated := []lbaasHealthMonitorConfiguration{}
+	for _, port := range ports {
+		configuration, ok := current[port]
+		if !ok {
+			return Changes{}, fmt.Errorf("No pool with a health monitor found for port %d of load balancer %s", port, uuid)
+		}
+
+		if want := desired[port].apply(configuration); !reflect.DeepEqual(want, configuration) {
+			updated = append(updated, want)
+		}
+	}
+
+	if len(updated) == 0 {
+		return Changes{}, nil
+	}
+
+	if err := updateHealthMonitors(sess, uuid, updated); err != nil {
+		return Changes{}, err
+	}
+
+	return Changes{Updated: len(updated)}, nil
+}
+
+func listenerKey(protocol string, port int) string {
+	return fmt.Sprintf("%s:%d", strings.ToUpper(protocol), port)
+}
+
+// listenerOf returns the Listener matching an existing listener and its
+// default pool
+func listenerOf(listener datatypes.Network_LBaaS_Listener) Listener {
+	return Listener{
+		FrontendProtocol: strings.ToUpper(sl.Get(listener.Protocol, "").(string)),
+		FrontendPort:     sl.Get(listener.ProtocolPort, 0).(int),
+		BackendProtocol:  strings.ToUpper(sl.Grab(listener, "DefaultPool.Protocol", "").(string)),
+		BackendPort:      sl.Grab(listener, "DefaultPool.ProtocolPort", 0).(int),
+		Method:           strings.ToUpper(sl.Grab(listener, "DefaultPool.LoadBalancingAlgorithm", "").(string)),
+		MaxConn:          sl.Get(listener.ConnectionLimit, 0).(int),
+	}
+}
+
+// normalize returns the listener as listenerOf would report it once applied
+func (l Listener) normalize() Listener {
+	l.FrontendProtocol = strings.ToUpper(l.FrontendProtocol)
+	l.BackendProtocol = strings.ToUpper(l.BackendProtocol)
+	l.Method = strings.ToUpper(l.Method)
+	return l
+}
@@ -0,0 +1,220 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package location
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Key names of the packages the capabilities of the datacenters are read from
+const (
+	virtualServerPackage = "PUBLIC_CLOUD_SERVER"
+	storagePackage       = "STORAGE_AS_A_SERVICE_STAAS"
+)
+
+// Pod is a network pod of a datacenter, along with its capabilities
+type Pod struct {
+	Name               string
+	BackendRouterName  string
+	FrontendRouterName string
+	Capabilities       []string
+}
+
+// Datacenter is a datacenter annotated with its capabilities.
+//
+// VirtualServerFlavors lists the key names of the virtual server flavors
+// (presets) which can be ordered in the datacenter, and StorageTiers the key
+// names of the endurance storage tiers (e.g. "READHEAVY_TIER"). Both are
+// empty when the corresponding package is not sold in the datacenter.
+// PlacementGroups reports whether some routers of the datacenter accept
+// placement groups.
+type Datacenter struct {
+	Id                   int
+	Name                 string
+	LongName             string
+	Regions              []string
+	VirtualServerFlavors []string
+	StorageTiers         []string
+	PlacementGroups      bool
+	Pods                 []Pod
+}
+
+// ListDatacenters returns the datacenters, sorted by name, annotated with
+// their capabilities, as assembled from the regions of the virtual server and
+// storage packages, the routers accepting placement groups, and the network
+// pods
+func ListDatacenters(sess *session.Session) ([]Datacenter, error) {
+	locations, err := services.GetLocationService(sess).
+		Mask("id,name,longName,regions[keyname]").
+		GetDatacenters()
+	if err != nil {
+		return nil, err
+	}
+
+	guestId, guestDatacenters, err := packageDatacenters(sess, virtualServerPackage)
+	if err != nil {
+		return nil, err
+	}
+
+	presets, err := services.GetProductPackageService(sess).Id(guestId).Mask("keyName").GetActivePresets()
+	if err != nil {
+		return nil, err
+	}
+
+	storageId, storageDatacenters, err := packageDatacenters(sess, storagePackage)
+	if err != nil {
+		return nil, err
+	}
+
+	tiers, err := services.GetProductPackageService(sess).
+		Id(storageId).
+		Mask("keyName").
+		Filter(filter.Path("items.categories.categoryCode").Eq("storage_tier_level").Build()).
+		GetItems()
+	if err != nil {
+		return nil, err
+	}
+
+	placementDatacenters, err := placementGroupDatacenters(sess)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := services.GetNetworkPodService(sess).GetAllObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	podsByDatacenter := map[string][]Pod{}
+	for _, pod := range pods {
+		datacenter := sl.Get(pod.DatacenterName, "").(string)
+		podsByDatacenter[datacenter] = append(podsByDatacenter[datacenter], Pod{
+			Name:               sl.Get(pod.Name, "").(string),
+			BackendRouterName:  sl.Get(pod.BackendRouterName, "").(string),
+			FrontendRouterName: sl.Get(pod.FrontendRouterName, "").(string),
+			Capabilities:       pod.Capabilities,
+		})
+	}
+
+	datacenters := make([]Datacenter, 0, len(locations))
+	for _, location := range locations {
+		name := sl.Get(location.Name, "").(string)
+
+		datacenter := Datacenter{
+			Id:                   sl.Get(location.Id, 0).(int),
+			Name:                 name,
+			LongName:             sl.Get(location.LongName, "").(string),
+			Regions:              []string{},
+			VirtualServerFlavors: []string{},
+			StorageTiers:         []string{},
+			PlacementGroups:      placementDatacenters[name],
+			Pods:                 podsByDatacenter[name],
+		}
+
+		for _, region := range location.Regions {
+			datacenter.Regions = append(datacenter.Regions, sl.Get(region.Keyname, "").(string))
+		}
+
+		if guestDatacenters[name] {
+			for _, preset := range presets {
+				datacenter.VirtualServerFlavors = append(datacenter.VirtualServerFlavors, sl.Get(preset.KeyName, "").(string))
+			}
+		}
+
+		if storageDatacenters[name] {
+			for _, tier := range tiers {
+				datacenter.StorageTiers = append(datacenter.StorageTiers, sl.Get(tier.KeyName, "").(string))
+			}
+		}
+
+		datacenters = append(datacenters, datacenter)
+	}
+
+	sort.Slice(datacenters, func(i, j int) bool {
+		return datacenters[i].Name < datacenters[j].Name
+	})
+
+	return datacenters, nil
+}
+
+// packageDatacenters returns the id of the package with the provided key
+// name, along with the names of the datacenters it is sold in
+func packageDatacenters(sess *session.Session, keyName string) (int, map[string]bool, error) {
+	packages, err := services.GetProductPackageService(sess).
+		Mask("id").
+		Filter(filter.Path("keyName").Eq(keyName).Build()).
+		GetAllObjects()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if len(packages) == 0 {
+		return 0, nil, fmt.Errorf("No package found with key name of %s", keyName)
+	}
+
+	packageId := *packages[0].Id
+
+	regions, err := services.GetProductPackageService(sess).
+		Id(packageId).
+		Mask("keyname,location[location[name]]").
+		GetRegions()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	datacenters := map[string]bool{}
+	for _, region := range regions {
+		if name := sl.Grab(region, "Location.Location.Name", "").(string); name != "" {
+			datacenters[name] = true
+		}
+	}
+
+	return packageId, datacenters, nil
+}
+
+// placementGroupDatacenters returns the names of the datacenters some routers
+// of which accept placement groups
+func placementGroupDatacenters(sess *session.Session) (map[string]bool, error) {
+	// Virtual_PlacementGroup::getAvailableRouters() is called directly, as the
+	// service is missing from the generated services
+	var routers []datatypes.Hardware
+	err := sess.DoRequest(
+		"SoftLayer_Virtual_PlacementGroup",
+		"getAvailableRouters",
+		nil,
+		&sl.Options{Mask: "mask[id,datacenter[name]]"},
+		&routers)
+	if err != nil {
+		return nil, err
+	}
+
+	datacenters := map[string]bool{}
+	for _, router := range routers {
+		if name := sl.Grab(router, "Datacenter.Name", "").(string); name != "" {
+			datacenters[name] = true
+		}
+	}
+
+	return datacenters, nil
+}
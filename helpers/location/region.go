@@ -0,0 +1,149 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package location
+
+import (
+	"strings"
+)
+
+// Zone is an IBM Cloud availability zone (e.g. "us-south-1") within its
+// region (e.g. "us-south")
+type Zone struct {
+	Region string
+	Zone   string
+}
+
+// datacenterZones maps the SoftLayer datacenters of the IBM Cloud multizone
+// regions to their availability zone
+var datacenterZones = map[string]Zone{
+	"dal10": {"us-south", "us-south-1"},
+	"dal12": {"us-south", "us-south-2"},
+	"dal13": {"us-south", "us-south-3"},
+	"wdc04": {"us-east", "us-east-1"},
+	"wdc06": {"us-east", "us-east-2"},
+	"wdc07": {"us-east", "us-east-3"},
+	"tor01": {"ca-tor", "ca-tor-1"},
+	"tor04": {"ca-tor", "ca-tor-2"},
+	"tor05": {"ca-tor", "ca-tor-3"},
+	"sao01": {"br-sao", "br-sao-1"},
+	"sao04": {"br-sao", "br-sao-2"},
+	"sao05": {"br-sao", "br-sao-3"},
+	"lon04": {"eu-gb", "eu-gb-1"},
+	"lon05": {"eu-gb", "eu-gb-2"},
+	"lon06": {"eu-gb", "eu-gb-3"},
+	"fra02": {"eu-de", "eu-de-1"},
+	"fra04": {"eu-de", "eu-de-2"},
+	"fra05": {"eu-de", "eu-de-3"},
+	"mad02": {"eu-es", "eu-es-1"},
+	"mad04": {"eu-es", "eu-es-2"},
+	"mad05": {"eu-es", "eu-es-3"},
+	"tok02": {"jp-tok", "jp-tok-1"},
+	"tok04": {"jp-tok", "jp-tok-2"},
+	"tok05": {"jp-tok", "jp-tok-3"},
+	"osa21": {"jp-osa", "jp-osa-1"},
+	"osa22": {"jp-osa", "jp-osa-2"},
+	"osa23": {"jp-osa", "jp-osa-3"},
+	"syd01": {"au-syd", "au-syd-1"},
+	"syd04": {"au-syd", "au-syd-2"},
+	"syd05": {"au-syd", "au-syd-3"},
+}
+
+// DatacenterZone returns the IBM Cloud availability zone of the SoftLayer
+// datacenter with the provided short name (e.g. "dal10"), and false when the
+// datacenter is not part of a multizone region
+func DatacenterZone(datacenter string) (Zone, bool) {
+	zone, ok := datacenterZones[strings.ToLower(datacenter)]
+	return zone, ok
+}
+
+// ZoneDatacenter returns the short name of the SoftLayer datacenter of the
+// IBM Cloud availability zone with the provided name (e.g. "us-south-1"),
+// and false when no datacenter is known for the zone
+func ZoneDatacenter(zone string) (string, bool) {
+	for datacenter, z := range datacenterZones {
+		if z.Zone == strings.ToLower(zone) {
+			return datacenter, true
+		}
+	}
+
+	return "", false
+}
+
+// RegionDatacenters returns the short names of the SoftLayer datacenters of
+// the IBM Cloud region with the provided name (e.g. "us-south"), ordered by
+// zone
+func RegionDatacenters(region string) []string {
+	datacenters := []string{}
+	for _, zone := range []string{"-1", "-2", "-3"} {
+		if datacenter, ok := ZoneDatacenter(strings.ToLower(region) + zone); ok {
+			datacenters = append(datacenters, datacenter)
+		}
+	}
+
+	return datacenters
+}
+
+// ClosestDatacenter returns the candidate datacenter closest to the first
+// satisfiable entry of preference. The entries of preference, ordered from
+// the most to the least preferred, are datacenter short names, availability
+// zones or regions. A datacenter entry is satisfied by the same datacenter
+// first, then by a datacenter of the same metro (e.g. "dal12" for "dal10"),
+// then by a datacenter of the same region. False is returned when no
+// candidate satisfies any entry.
+func ClosestDatacenter(candidates []string, preference []string) (string, bool) {
+	for _, entry := range preference {
+		entry = strings.ToLower(entry)
+
+		if datacenter, ok := ZoneDatacenter(entry); ok {
+			entry = datacenter
+		}
+
+		matches := []func(candidate string) bool{
+			func(candidate string) bool {
+				return candidate == entry
+			},
+			func(candidate string) bool {
+				return metro(candidate) == metro(entry)
+			},
+			func(candidate string) bool {
+				region := entry
+				if zone, ok := DatacenterZone(entry); ok {
+					region = zone.Region
+				}
+
+				zone, ok := DatacenterZone(candidate)
+				return ok && zone.Region == region
+			},
+		}
+
+		for _, match := range matches {
+			for _, candidate := range candidates {
+				if match(strings.ToLower(candidate)) {
+					return candidate, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// metro returns the metro of the datacenter with the provided short name,
+// which is the name without its number (e.g. "dal" for "dal10")
+func metro(datacenter string) string {
+	return strings.TrimRight(datacenter, "0123456789")
+}
@@ -0,0 +1,46 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package location
+
+import "testing"
+
+func TestClosestDatacenter(t *testing.T) {
+	tests := []struct {
+		description string
+		candidates  []string
+		preference  []string
+		expected    string
+		found       bool
+	}{
+		{"same datacenter", []string{"dal12", "dal10"}, []string{"dal10"}, "dal10", true},
+		{"case insensitive", []string{"DAL10"}, []string{"Dal10"}, "DAL10", true},
+		{"same metro", []string{"wdc04", "dal12"}, []string{"dal10"}, "dal12", true},
+		{"same region", []string{"wdc04", "sao01"}, []string{"wdc07"}, "wdc04", true},
+		{"availability zone", []string{"dal10", "dal13"}, []string{"us-south-3"}, "dal13", true},
+		{"region", []string{"fra02", "lon04"}, []string{"eu-gb"}, "lon04", true},
+		{"first satisfiable entry", []string{"tok02", "syd01"}, []string{"mad02", "au-syd", "jp-tok"}, "syd01", true},
+		{"no satisfiable entry", []string{"tok02"}, []string{"dal10", "eu-de"}, "", false},
+		{"no candidates", []string{}, []string{"dal10"}, "", false},
+	}
+
+	for _, test := range tests {
+		datacenter, found := ClosestDatacenter(test.candidates, test.preference)
+		if datacenter != test.expected || found != test.found {
+			t.Errorf("%s: expected %q (%t), got %q (%t)", test.description, test.expected, test.found, datacenter, found)
+		}
+	}
+}
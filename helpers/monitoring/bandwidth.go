@@ -0,0 +1,244 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitoring
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Kinds of the sources of bandwidth usage
+const (
+	SourceVirtualGuest  = "virtual_guest"
+	SourceHardware      = "hardware"
+	SourceBandwidthPool = "bandwidth_pool"
+)
+
+// Periods of the samples accepted by the metric tracking objects
+const (
+	PeriodFiveMinutes = 5 * time.Minute
+	PeriodHour        = time.Hour
+	PeriodDay         = 24 * time.Hour
+)
+
+// Key names of the bandwidth metrics, in bytes
+const (
+	metricPublicIn   = "PUBLICIN_NET_OCTET"
+	metricPublicOut  = "PUBLICOUT_NET_OCTET"
+	metricPrivateIn  = "PRIVATEIN_NET_OCTET"
+	metricPrivateOut = "PRIVATEOUT_NET_OCTET"
+)
+
+// BandwidthSample is the bandwidth, in bytes, used in each direction during
+// the period starting at Time
+type BandwidthSample struct {
+	Time       time.Time
+	PublicIn   float64
+	PublicOut  float64
+	PrivateIn  float64
+	PrivateOut float64
+}
+
+// BandwidthUsage is the bandwidth used by a server or bandwidth pool over a
+// date range. The totals are in bytes, and Samples is the time series of the
+// usage, oldest first.
+type BandwidthUsage struct {
+	Kind             string
+	Id               int
+	Name             string
+	TrackingObjectId int
+	PublicIn         float64
+	PublicOut        float64
+	PrivateIn        float64
+	PrivateOut       float64
+	Samples          []BandwidthSample
+}
+
+// GetBandwidthData returns the bandwidth recorded by the metric tracking
+// object with the provided id between start and end, sampled every period.
+// The period must be one of PeriodFiveMinutes, PeriodHour and PeriodDay.
+func GetBandwidthData(
+	sess *session.Session,
+	trackingObjectId int,
+	start time.Time,
+	end time.Time,
+	period time.Duration,
+) (BandwidthUsage, error) {
+
+	if period != PeriodFiveMinutes && period != PeriodHour && period != PeriodDay {
+		return BandwidthUsage{}, fmt.Errorf("Unsupported sampling period %s", period)
+	}
+
+	validTypes := []datatypes.Container_Metric_Data_Type{}
+	for _, keyName := range []string{metricPublicIn, metricPublicOut, metricPrivateIn, metricPrivateOut} {
+		validTypes = append(validTypes, datatypes.Container_Metric_Data_Type{
+			KeyName:     sl.String(keyName),
+			SummaryType: sl.String("sum"),
+		})
+	}
+
+	data, err := services.GetMetricTrackingObjectService(sess).
+		Id(trackingObjectId).
+		GetSummaryData(
+			&datatypes.Time{Time: start},
+			&datatypes.Time{Time: end},
+			validTypes,
+			sl.Int(int(period.Seconds())))
+	if err != nil {
+		return BandwidthUsage{}, fmt.Errorf(
+			"Error getting the bandwidth data of tracking object %d: %s", trackingObjectId, err)
+	}
+
+	usage := BandwidthUsage{
+		TrackingObjectId: trackingObjectId,
+		Samples:          []BandwidthSample{},
+	}
+
+	samples := map[time.Time]*BandwidthSample{}
+	for _, point := range data {
+		if point.DateTime == nil {
+			continue
+		}
+
+		sample, ok := samples[point.DateTime.Time]
+		if !ok {
+			sample = &BandwidthSample{Time: point.DateTime.Time}
+			samples[point.DateTime.Time] = sample
+		}
+
+		counter := float64(sl.Get(point.Counter, datatypes.Float64(0)).(datatypes.Float64))
+
+		switch sl.Get(point.Type, "").(string) {
+		case metricPublicIn:
+			sample.PublicIn += counter
+			usage.PublicIn += counter
+		case metricPublicOut:
+			sample.PublicOut += counter
+			usage.PublicOut += counter
+		case metricPrivateIn:
+			sample.PrivateIn += counter
+			usage.PrivateIn += counter
+		case metricPrivateOut:
+			sample.PrivateOut += counter
+			usage.PrivateOut += counter
+		}
+	}
+
+	for _, sample := range samples {
+		usage.Samples = append(usage.Samples, *sample)
+	}
+
+	sort.Slice(usage.Samples, func(i, j int) bool {
+		return usage.Samples[i].Time.Before(usage.Samples[j].Time)
+	})
+
+	return usage, nil
+}
+
+// GetAccountBandwidth returns the bandwidth used between start and end by
+// each virtual guest, hardware server and bandwidth pool of the account,
+// sampled every period as GetBandwidthData does. The servers without a metric
+// tracking object, such as the ones still provisioning, are skipped.
+func GetAccountBandwidth(
+	sess *session.Session,
+	start time.Time,
+	end time.Time,
+	period time.Duration,
+) ([]BandwidthUsage, error) {
+
+	sources, err := bandwidthSources(sess)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]BandwidthUsage, 0, len(sources))
+	for _, source := range sources {
+		usage, err := GetBandwidthData(sess, source.TrackingObjectId, start, end, period)
+		if err != nil {
+			return nil, err
+		}
+
+		usage.Kind = source.Kind
+		usage.Id = source.Id
+		usage.Name = source.Name
+		usages = append(usages, usage)
+	}
+
+	return usages, nil
+}
+
+// bandwidthSources returns the servers and bandwidth pools of the account
+// which have a metric tracking object, without any usage
+func bandwidthSources(sess *session.Session) ([]BandwidthUsage, error) {
+	service := services.GetAccountService(sess)
+
+	guests, err := service.Mask("id,fullyQualifiedDomainName,metricTrackingObjectId").GetVirtualGuests()
+	if err != nil {
+		return nil, err
+	}
+
+	hardware, err := service.Mask("id,fullyQualifiedDomainName,metricTrackingObject[id]").GetHardware()
+	if err != nil {
+		return nil, err
+	}
+
+	pools, err := service.Mask("id,name,metricTrackingObjectId").GetBandwidthAllotments()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := []BandwidthUsage{}
+	add := func(kind string, id int, name string, trackingObjectId int) {
+		if trackingObjectId != 0 {
+			sources = append(sources, BandwidthUsage{
+				Kind:             kind,
+				Id:               id,
+				Name:             name,
+				TrackingObjectId: trackingObjectId,
+			})
+		}
+	}
+
+	for _, guest := range guests {
+		add(SourceVirtualGuest,
+			sl.Get(guest.Id, 0).(int),
+			sl.Get(guest.FullyQualifiedDomainName, "").(string),
+			sl.Get(guest.MetricTrackingObjectId, 0).(int))
+	}
+
+	for _, hw := range hardware {
+		add(SourceHardware,
+			sl.Get(hw.Id, 0).(int),
+			sl.Get(hw.FullyQualifiedDomainName, "").(string),
+			sl.Grab(hw, "MetricTrackingObject.Id", 0).(int))
+	}
+
+	for _, pool := range pools {
+		add(SourceBandwidthPool,
+			sl.Get(pool.Id, 0).(int),
+			sl.Get(pool.Name, "").(string),
+			sl.Get(pool.MetricTrackingObjectId, 0).(int))
+	}
+
+	return sources, nil
+}
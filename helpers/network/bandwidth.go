@@ -0,0 +1,110 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DefaultBandwidthPoolMask is the default object mask for bandwidth pools
+const DefaultBandwidthPoolMask = "id,name,createDate,locationGroup[name]," +
+	"bandwidthAllotmentType[keyName],hardwareCount,virtualGuestCount"
+
+// BandwidthUsage compares the public outbound bandwidth, in GB, allocated to a
+// bandwidth pool to the bandwidth it used in the current billing cycle, and to
+// the bandwidth it is projected to use by the end of the cycle
+type BandwidthUsage struct {
+	PoolId                  int
+	Name                    string
+	Allocated               uint
+	Used                    uint
+	Projected               float64
+	OverAllocation          bool
+	ProjectedOverAllocation bool
+}
+
+// ListBandwidthPools returns the bandwidth pools (allotments) of the account.
+// An object mask can be provided as an optional argument, and
+// DefaultBandwidthPoolMask is used otherwise.
+func ListBandwidthPools(sess *session.Session, mask ...string) ([]datatypes.Network_Bandwidth_Version1_Allotment, error) {
+	objectMask := DefaultBandwidthPoolMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	return services.GetAccountService(sess).Mask(objectMask).GetBandwidthAllotments()
+}
+
+// GetBandwidthUsage returns the current and projected usage of the bandwidth
+// pool with the provided id
+func GetBandwidthUsage(sess *session.Session, poolId int) (BandwidthUsage, error) {
+	pool, err := services.GetNetworkBandwidthVersion1AllotmentService(sess).
+		Id(poolId).
+		Mask("id,name,totalBandwidthAllocated,billingCyclePublicUsageTotal,projectedPublicBandwidthUsage," +
+			"overBandwidthAllocationFlag,projectedOverBandwidthAllocationFlag").
+		GetObject()
+	if err != nil {
+		return BandwidthUsage{}, err
+	}
+
+	return BandwidthUsage{
+		PoolId:                  poolId,
+		Name:                    sl.Get(pool.Name, "").(string),
+		Allocated:               sl.Get(pool.TotalBandwidthAllocated, uint(0)).(uint),
+		Used:                    sl.Get(pool.BillingCyclePublicUsageTotal, uint(0)).(uint),
+		Projected:               float64(sl.Get(pool.ProjectedPublicBandwidthUsage, datatypes.Float64(0)).(datatypes.Float64)),
+		OverAllocation:          sl.Get(pool.OverBandwidthAllocationFlag, 0).(int) == 1,
+		ProjectedOverAllocation: sl.Get(pool.ProjectedOverBandwidthAllocationFlag, 0).(int) == 1,
+	}, nil
+}
+
+// MoveToBandwidthPool moves the hardware and virtual guests with the provided
+// ids into the bandwidth pool with the provided id, removing them from their
+// current pool. The move is processed asynchronously by SoftLayer.
+func MoveToBandwidthPool(sess *session.Session, poolId int, hardwareIds []int, guestIds []int) error {
+	hardware := make([]datatypes.Hardware, 0, len(hardwareIds))
+	for _, id := range hardwareIds {
+		hardware = append(hardware, datatypes.Hardware{Id: sl.Int(id)})
+	}
+
+	guests := make([]datatypes.Virtual_Guest, 0, len(guestIds))
+	for _, id := range guestIds {
+		guests = append(guests, datatypes.Virtual_Guest{Id: sl.Int(id)})
+	}
+
+	_, err := services.GetNetworkBandwidthVersion1AllotmentService(sess).
+		Id(poolId).
+		RequestVdrContentUpdates(
+			hardware,
+			[]datatypes.Hardware{},
+			guests,
+			[]datatypes.Virtual_Guest{},
+			nil,
+			[]datatypes.Network_Application_Delivery_Controller{},
+			[]datatypes.Network_Application_Delivery_Controller{},
+		)
+	if err != nil {
+		return fmt.Errorf("Error moving servers to bandwidth pool %d: %s", poolId, err)
+	}
+
+	return nil
+}
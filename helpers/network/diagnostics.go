@@ -0,0 +1,98 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// PingResult is the result of a ping of a server, issued by SoftLayer from
+// the datacenter of the server. Output holds the raw ping output, which is
+// only available for hardware.
+type PingResult struct {
+	Hostname   string
+	IpAddress  string
+	Datacenter string
+	Pingable   bool
+	Output     string
+}
+
+// PingHardware pings the primary IP address of the hardware server with the
+// provided id
+func PingHardware(sess *session.Session, hardwareId int) (PingResult, error) {
+	service := services.GetHardwareServerService(sess).Id(hardwareId)
+
+	hardware, err := service.Mask("id,fullyQualifiedDomainName,primaryIpAddress,datacenter[name]").GetObject()
+	if err != nil {
+		return PingResult{}, err
+	}
+
+	result := PingResult{
+		Hostname:   sl.Get(hardware.FullyQualifiedDomainName, "").(string),
+		IpAddress:  sl.Get(hardware.PrimaryIpAddress, "").(string),
+		Datacenter: sl.Grab(hardware, "Datacenter.Name", "").(string),
+	}
+
+	if result.Pingable, err = service.IsPingable(); err != nil {
+		return result, fmt.Errorf("Error pinging hardware %d: %s", hardwareId, err)
+	}
+
+	if result.Output, err = service.Ping(); err != nil {
+		return result, fmt.Errorf("Error pinging hardware %d: %s", hardwareId, err)
+	}
+
+	return result, nil
+}
+
+// PingVirtualGuest pings the primary IP address of the virtual guest with the
+// provided id
+func PingVirtualGuest(sess *session.Session, guestId int) (PingResult, error) {
+	service := services.GetVirtualGuestService(sess).Id(guestId)
+
+	guest, err := service.Mask("id,fullyQualifiedDomainName,primaryIpAddress,datacenter[name]").GetObject()
+	if err != nil {
+		return PingResult{}, err
+	}
+
+	result := PingResult{
+		Hostname:   sl.Get(guest.FullyQualifiedDomainName, "").(string),
+		IpAddress:  sl.Get(guest.PrimaryIpAddress, "").(string),
+		Datacenter: sl.Grab(guest, "Datacenter.Name", "").(string),
+	}
+
+	if result.Pingable, err = service.IsPingable(); err != nil {
+		return result, fmt.Errorf("Error pinging virtual guest %d: %s", guestId, err)
+	}
+
+	return result, nil
+}
+
+// NsLookup resolves address, a hostname or an IP address, from the SoftLayer
+// network. recordType is the type of the records to look up (e.g. "a",
+// "ptr").
+func NsLookup(sess *session.Session, address string, recordType string) (string, error) {
+	return services.GetUtilityNetworkService(sess).NsLookup(sl.String(address), sl.String(recordType))
+}
+
+// Whois returns the whois information of the provided IP address or domain
+func Whois(sess *session.Session, address string) (string, error) {
+	return services.GetUtilityNetworkService(sess).Whois(sl.String(address))
+}
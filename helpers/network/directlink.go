@@ -0,0 +1,125 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// directLinkMask only holds the properties of Direct Link connections which
+// are plain strings, so that they can be decoded without generated datatypes
+const directLinkMask = "mask[id,name,createDate,datacenterName,portLabel,vendorName,zoneName]"
+
+// DirectLink is a Direct Link connection (network interconnect tenant).
+// PortLabel, Vendor and Zone describe the cross-connect of the link.
+type DirectLink struct {
+	Id         int
+	Name       string
+	CreateDate *datatypes.Time
+	Datacenter string
+	PortLabel  string
+	Vendor     string
+	Zone       string
+}
+
+// DirectLinkStatus holds the BGP information of a Direct Link connection, as
+// returned by the API, along with its cross-connect ports
+type DirectLinkStatus struct {
+	DirectLink
+	BgpInfo interface{}
+	Ports   interface{}
+}
+
+// The SoftLayer_Network_Interconnect_Tenant service and its datatype are not
+// part of the generated code, so they are called directly through the session.
+
+// interconnectTenant is the SoftLayer_Network_Interconnect_Tenant type
+type interconnectTenant struct {
+	Id             *int            `json:"id,omitempty" xmlrpc:"id,omitempty"`
+	Name           *string         `json:"name,omitempty" xmlrpc:"name,omitempty"`
+	CreateDate     *datatypes.Time `json:"createDate,omitempty" xmlrpc:"createDate,omitempty"`
+	DatacenterName *string         `json:"datacenterName,omitempty" xmlrpc:"datacenterName,omitempty"`
+	PortLabel      *string         `json:"portLabel,omitempty" xmlrpc:"portLabel,omitempty"`
+	VendorName     *string         `json:"vendorName,omitempty" xmlrpc:"vendorName,omitempty"`
+	ZoneName       *string         `json:"zoneName,omitempty" xmlrpc:"zoneName,omitempty"`
+}
+
+// ListDirectLinks returns the Direct Link connections of the account
+func ListDirectLinks(sess *session.Session) ([]DirectLink, error) {
+	var tenants []interconnectTenant
+	err := sess.DoRequest(
+		"SoftLayer_Network_Interconnect_Tenant",
+		"getAllObjects",
+		nil,
+		&sl.Options{Mask: directLinkMask},
+		&tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]DirectLink, 0, len(tenants))
+	for _, tenant := range tenants {
+		links = append(links, tenant.directLink())
+	}
+
+	return links, nil
+}
+
+// GetDirectLinkStatus returns the Direct Link connection with the provided id,
+// along with its BGP information and cross-connect ports
+func GetDirectLinkStatus(sess *session.Session, linkId int) (DirectLinkStatus, error) {
+	var tenant interconnectTenant
+	err := sess.DoRequest(
+		"SoftLayer_Network_Interconnect_Tenant",
+		"getObject",
+		nil,
+		&sl.Options{Id: &linkId, Mask: directLinkMask},
+		&tenant)
+	if err != nil {
+		return DirectLinkStatus{}, err
+	}
+
+	status := DirectLinkStatus{DirectLink: tenant.directLink()}
+
+	err = sess.DoRequest("SoftLayer_Network_Interconnect_Tenant", "getBgpIpsInfo", nil, &sl.Options{Id: &linkId}, &status.BgpInfo)
+	if err != nil {
+		return status, fmt.Errorf("Error getting BGP information of Direct Link %d: %s", linkId, err)
+	}
+
+	err = sess.DoRequest("SoftLayer_Network_Interconnect_Tenant", "getPorts", nil, &sl.Options{Id: &linkId}, &status.Ports)
+	if err != nil {
+		return status, fmt.Errorf("Error getting cross-connect ports of Direct Link %d: %s", linkId, err)
+	}
+
+	return status, nil
+}
+
+func (t interconnectTenant) directLink() DirectLink {
+	return DirectLink{
+		Id:         sl.Get(t.Id, 0).(int),
+		Name:       sl.Get(t.Name, "").(string),
+		CreateDate: t.CreateDate,
+		Datacenter: sl.Get(t.DatacenterName, "").(string),
+		PortLabel:  sl.Get(t.PortLabel, "").(string),
+		Vendor:     sl.Get(t.VendorName, "").(string),
+		Zone:       sl.Get(t.ZoneName, "").(string),
+	}
+}
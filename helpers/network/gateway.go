@@ -0,0 +1,210 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DefaultPollInterval is the time waited between two checks of a network
+// resource's state, when WaitForGateway or SetVlanSpanning is passed a zero
+// interval. Both give up waiting, and return the context's error, once their
+// context is canceled or past its deadline.
+const DefaultPollInterval = 30 * time.Second
+
+// DefaultGatewayMask is the default object mask for network gateways
+const DefaultGatewayMask = "id,name,networkSpace,status[keyName,name]," +
+	"publicIpAddress[ipAddress],privateIpAddress[ipAddress]," +
+	"members[id,priority,hardware[id,hostname,provisionDate]]," +
+	"insideVlans[id,bypassFlag,networkVlanId,networkVlan[vlanNumber]]"
+
+// GatewayRoutingStatus describes how a gateway routes its inside VLANs.
+// Routed and Bypassed hold the ids of the VLANs whose traffic goes through the
+// gateway, and of the ones whose traffic bypasses it.
+type GatewayRoutingStatus struct {
+	Status   string
+	Routed   []int
+	Bypassed []int
+}
+
+// ListGateways returns the network gateways (e.g. Vyatta, Juniper vSRX) of
+// the account. An object mask can be provided as an optional argument, and
+// DefaultGatewayMask is used otherwise.
+func ListGateways(sess *session.Session, mask ...string) ([]datatypes.Network_Gateway, error) {
+	objectMask := DefaultGatewayMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	return services.GetAccountService(sess).Mask(objectMask).GetNetworkGateways()
+}
+
+// AssociateVlans associates the VLANs with the provided ids to the gateway
+// with the provided id. Their traffic is routed through the gateway.
+func AssociateVlans(sess *session.Session, gatewayId int, vlanIds []int) error {
+	templates := make([]datatypes.Network_Gateway_Vlan, 0, len(vlanIds))
+	for _, vlanId := range vlanIds {
+		templates = append(templates, datatypes.Network_Gateway_Vlan{
+			NetworkGatewayId: sl.Int(gatewayId),
+			NetworkVlanId:    sl.Int(vlanId),
+			BypassFlag:       sl.Bool(false),
+		})
+	}
+
+	_, err := services.GetNetworkGatewayVlanService(sess).CreateObjects(templates)
+	return err
+}
+
+// DisassociateVlans removes the association of the VLANs with the provided
+// ids to the gateway with the provided id
+func DisassociateVlans(sess *session.Session, gatewayId int, vlanIds []int) error {
+	insideVlans, err := getInsideVlans(sess, gatewayId, vlanIds)
+	if err != nil {
+		return err
+	}
+
+	_, err = services.GetNetworkGatewayVlanService(sess).DeleteObjects(insideVlans)
+	return err
+}
+
+// BypassVlans makes the traffic of the VLANs with the provided ids, which must
+// be associated to the gateway with the provided id, bypass the gateway when
+// bypass is set, or go through it otherwise
+func BypassVlans(sess *session.Session, gatewayId int, vlanIds []int, bypass bool) error {
+	insideVlans, err := getInsideVlans(sess, gatewayId, vlanIds)
+	if err != nil {
+		return err
+	}
+
+	service := services.GetNetworkGatewayService(sess).Id(gatewayId)
+	if bypass {
+		return service.BypassVlans(insideVlans)
+	}
+
+	return service.UnbypassVlans(insideVlans)
+}
+
+// GetRoutingStatus returns the status of the gateway with the provided id,
+// along with how it routes each of its inside VLANs
+func GetRoutingStatus(sess *session.Session, gatewayId int) (GatewayRoutingStatus, error) {
+	gateway, err := services.GetNetworkGatewayService(sess).
+		Id(gatewayId).
+		Mask("id,status[keyName],insideVlans[id,bypassFlag,networkVlanId]").
+		GetObject()
+	if err != nil {
+		return GatewayRoutingStatus{}, err
+	}
+
+	status := GatewayRoutingStatus{
+		Status:   sl.Grab(gateway, "Status.KeyName", "").(string),
+		Routed:   []int{},
+		Bypassed: []int{},
+	}
+
+	for _, vlan := range gateway.InsideVlans {
+		vlanId := sl.Get(vlan.NetworkVlanId, 0).(int)
+		if sl.Get(vlan.BypassFlag, false).(bool) {
+			status.Bypassed = append(status.Bypassed, vlanId)
+		} else {
+			status.Routed = append(status.Routed, vlanId)
+		}
+	}
+
+	return status, nil
+}
+
+// WaitForGateway polls the gateway with the provided id until it is active
+// and all its member servers are provisioned, and returns it.
+func WaitForGateway(
+	ctx context.Context,
+	sess *session.Session,
+	gatewayId int,
+	interval time.Duration,
+) (datatypes.Network_Gateway, error) {
+
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	service := services.GetNetworkGatewayService(sess).Id(gatewayId).Mask(DefaultGatewayMask)
+
+	for {
+		gateway, err := service.GetObject()
+		if err != nil {
+			return datatypes.Network_Gateway{}, err
+		}
+
+		if isGatewayReady(gateway) {
+			return gateway, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return datatypes.Network_Gateway{}, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func isGatewayReady(gateway datatypes.Network_Gateway) bool {
+	if sl.Grab(gateway, "Status.KeyName", "").(string) != "ACTIVE" || len(gateway.Members) == 0 {
+		return false
+	}
+
+	for _, member := range gateway.Members {
+		if member.Hardware == nil || member.Hardware.ProvisionDate == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getInsideVlans returns the associations of the gateway with the provided id
+// to the VLANs with the provided ids
+func getInsideVlans(sess *session.Session, gatewayId int, vlanIds []int) ([]datatypes.Network_Gateway_Vlan, error) {
+	associated, err := services.GetNetworkGatewayService(sess).
+		Id(gatewayId).
+		Mask("id,bypassFlag,networkGatewayId,networkVlanId").
+		GetInsideVlans()
+	if err != nil {
+		return nil, err
+	}
+
+	byVlan := map[int]datatypes.Network_Gateway_Vlan{}
+	for _, vlan := range associated {
+		byVlan[sl.Get(vlan.NetworkVlanId, 0).(int)] = vlan
+	}
+
+	insideVlans := make([]datatypes.Network_Gateway_Vlan, 0, len(vlanIds))
+	for _, vlanId := range vlanIds {
+		vlan, ok := byVlan[vlanId]
+		if !ok {
+			return nil, fmt.Errorf("VLAN %d is not associated to gateway %d", vlanId, gatewayId)
+		}
+		insideVlans = append(insideVlans, vlan)
+	}
+
+	return insideVlans, nil
+}
@@ -0,0 +1,131 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Kinds of devices an IP address can be assigned to
+const (
+	AssignedToVirtualGuest = "virtual_guest"
+	AssignedToHardware     = "hardware"
+)
+
+const ipAddressMask = "id,ipAddress,note,isReserved,isGateway,isNetwork,isBroadcast," +
+	"subnet[id,networkIdentifier,cidr,subnetType,networkVlan[id,vlanNumber]]," +
+	"virtualGuest[id,hostname,domain],hardware[id,hostname,domain]"
+
+// IPAddressInfo describes an IP address of the account: the subnet it belongs
+// to, the device it is assigned to, if any, and its note. AssignedTo is one of
+// AssignedToVirtualGuest or AssignedToHardware, or empty when the address is
+// not assigned.
+type IPAddressInfo struct {
+	Id         int
+	IpAddress  string
+	Note       string
+	Reserved   bool
+	SubnetId   int
+	Subnet     string
+	SubnetType string
+	VlanNumber int
+	AssignedTo string
+	DeviceId   int
+	Hostname   string
+}
+
+// FindIPAddress returns who owns the provided IP address of the account
+func FindIPAddress(sess *session.Session, ipAddress string) (IPAddressInfo, error) {
+	ip, err := getIpAddress(sess, ipAddress, ipAddressMask)
+	if err != nil {
+		return IPAddressInfo{}, err
+	}
+
+	info := IPAddressInfo{
+		Id:         *ip.Id,
+		IpAddress:  sl.Get(ip.IpAddress, "").(string),
+		Note:       sl.Get(ip.Note, "").(string),
+		Reserved:   sl.Get(ip.IsReserved, false).(bool),
+		SubnetId:   sl.Grab(ip, "Subnet.Id", 0).(int),
+		SubnetType: sl.Grab(ip, "Subnet.SubnetType", "").(string),
+		VlanNumber: sl.Grab(ip, "Subnet.NetworkVlan.VlanNumber", 0).(int),
+	}
+
+	if ip.Subnet != nil && ip.Subnet.NetworkIdentifier != nil {
+		info.Subnet = fmt.Sprintf("%s/%d", *ip.Subnet.NetworkIdentifier, sl.Get(ip.Subnet.Cidr, 0))
+	}
+
+	switch {
+	case ip.VirtualGuest != nil && ip.VirtualGuest.Id != nil:
+		info.AssignedTo = AssignedToVirtualGuest
+		info.DeviceId = *ip.VirtualGuest.Id
+		info.Hostname = fqdn(sl.Get(ip.VirtualGuest.Hostname, "").(string), sl.Get(ip.VirtualGuest.Domain, "").(string))
+	case ip.Hardware != nil && ip.Hardware.Id != nil:
+		info.AssignedTo = AssignedToHardware
+		info.DeviceId = *ip.Hardware.Id
+		info.Hostname = fqdn(sl.Get(ip.Hardware.Hostname, "").(string), sl.Get(ip.Hardware.Domain, "").(string))
+	}
+
+	return info, nil
+}
+
+// SetIPNote sets the note of the provided IP address of the account. An empty
+// note clears it.
+func SetIPNote(sess *session.Session, ipAddress string, note string) error {
+	ip, err := getIpAddress(sess, ipAddress, "id,ipAddress")
+	if err != nil {
+		return err
+	}
+
+	_, err = services.GetNetworkSubnetIpAddressService(sess).
+		Id(*ip.Id).
+		EditObject(&datatypes.Network_Subnet_IpAddress{Note: sl.String(note)})
+	if err != nil {
+		return fmt.Errorf("Error setting the note of IP address %s: %s", ipAddress, err)
+	}
+
+	return nil
+}
+
+func getIpAddress(sess *session.Session, ipAddress string, mask string) (datatypes.Network_Subnet_IpAddress, error) {
+	ip, err := services.GetNetworkSubnetIpAddressService(sess).
+		Mask(mask).
+		GetByIpAddress(sl.String(ipAddress))
+	if err != nil {
+		return datatypes.Network_Subnet_IpAddress{}, err
+	}
+
+	// getByIpAddress() returns an empty object for unknown addresses
+	if ip.Id == nil {
+		return datatypes.Network_Subnet_IpAddress{}, fmt.Errorf("IP address %s was not found on the account", ipAddress)
+	}
+
+	return ip, nil
+}
+
+func fqdn(hostname string, domain string) string {
+	if domain == "" {
+		return hostname
+	}
+
+	return hostname + "." + domain
+}
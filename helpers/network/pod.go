@@ -0,0 +1,154 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// RouterPlacement locates a resource in the network topology: the router it
+// is behind, and the pod and datacenter that router belongs to
+type RouterPlacement struct {
+	RouterId       int
+	RouterHostname string
+	Pod            string
+	Datacenter     string
+}
+
+// ListPods returns the network pods of the provided datacenter (e.g.
+// "dal10"), or of all datacenters when datacenter is empty
+func ListPods(sess *session.Session, datacenter string) ([]datatypes.Network_Pod, error) {
+	pods, err := services.GetNetworkPodService(sess).GetAllObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	if datacenter == "" {
+		return pods, nil
+	}
+
+	// Pods are not persistent objects, and cannot be filtered by the API
+	result := []datatypes.Network_Pod{}
+	for _, pod := range pods {
+		if sl.Get(pod.DatacenterName, "").(string) == datacenter {
+			result = append(result, pod)
+		}
+	}
+
+	return result, nil
+}
+
+// GetPodsByDatacenter returns the network pods of all datacenters, keyed by
+// datacenter name
+func GetPodsByDatacenter(sess *session.Session) (map[string][]datatypes.Network_Pod, error) {
+	pods, err := ListPods(sess, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string][]datatypes.Network_Pod{}
+	for _, pod := range pods {
+		datacenter := sl.Get(pod.DatacenterName, "").(string)
+		result[datacenter] = append(result[datacenter], pod)
+	}
+
+	return result, nil
+}
+
+// GetVlanPlacement returns the router the VLAN with the provided id is
+// trunked to, along with its pod
+func GetVlanPlacement(sess *session.Session, vlanId int) (RouterPlacement, error) {
+	vlan, err := services.GetNetworkVlanService(sess).
+		Id(vlanId).
+		Mask("id,primaryRouter[id,hostname,datacenter[name]]").
+		GetObject()
+	if err != nil {
+		return RouterPlacement{}, err
+	}
+
+	if vlan.PrimaryRouter == nil || vlan.PrimaryRouter.Id == nil {
+		return RouterPlacement{}, fmt.Errorf("VLAN %d has no router", vlanId)
+	}
+
+	return routerPlacement(
+		sess,
+		*vlan.PrimaryRouter.Id,
+		sl.Get(vlan.PrimaryRouter.Hostname, "").(string),
+		sl.Grab(vlan, "PrimaryRouter.Datacenter.Name", "").(string))
+}
+
+// placementGroup is the SoftLayer_Virtual_PlacementGroup type, which is not
+// part of the generated datatypes
+type placementGroup struct {
+	Id            *int                `json:"id,omitempty" xmlrpc:"id,omitempty"`
+	BackendRouter *datatypes.Hardware `json:"backendRouter,omitempty" xmlrpc:"backendRouter,omitempty"`
+}
+
+// GetPlacementGroupPlacement returns the backend router the guests of the
+// placement group with the provided id are placed behind, along with its pod
+func GetPlacementGroupPlacement(sess *session.Session, placementGroupId int) (RouterPlacement, error) {
+	// Virtual_PlacementGroup::getObject() is called directly, as the service
+	// is missing from the generated services
+	var group placementGroup
+	err := sess.DoRequest(
+		"SoftLayer_Virtual_PlacementGroup",
+		"getObject",
+		nil,
+		&sl.Options{Id: &placementGroupId, Mask: "mask[id,backendRouter[id,hostname,datacenter[name]]]"},
+		&group)
+	if err != nil {
+		return RouterPlacement{}, err
+	}
+
+	if group.BackendRouter == nil || group.BackendRouter.Id == nil {
+		return RouterPlacement{}, fmt.Errorf("Placement group %d has no backend router", placementGroupId)
+	}
+
+	return routerPlacement(
+		sess,
+		*group.BackendRouter.Id,
+		sl.Get(group.BackendRouter.Hostname, "").(string),
+		sl.Grab(group, "BackendRouter.Datacenter.Name", "").(string))
+}
+
+// routerPlacement finds the pod of the router with the provided id
+func routerPlacement(sess *session.Session, routerId int, hostname string, datacenter string) (RouterPlacement, error) {
+	placement := RouterPlacement{
+		RouterId:       routerId,
+		RouterHostname: hostname,
+		Datacenter:     datacenter,
+	}
+
+	pods, err := ListPods(sess, datacenter)
+	if err != nil {
+		return placement, err
+	}
+
+	for _, pod := range pods {
+		if sl.Get(pod.BackendRouterId, 0).(int) == routerId || sl.Get(pod.FrontendRouterId, 0).(int) == routerId {
+			placement.Pod = sl.Get(pod.Name, "").(string)
+			return placement, nil
+		}
+	}
+
+	return placement, fmt.Errorf("No pod found for router %s", hostname)
+}
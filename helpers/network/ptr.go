@@ -0,0 +1,147 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/helpers/dns"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// GetPTR returns the hostname the reverse DNS (PTR) record of the provided IP
+// address points to, or an empty string when the address has no PTR record
+func GetPTR(sess *session.Session, ipAddress string) (string, error) {
+	_, _, record, err := findPTR(sess, ipAddress)
+	if err != nil || record == nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(sl.Get(record.Data, "").(string), "."), nil
+}
+
+// SetPTR points the reverse DNS (PTR) record of the provided IP address to
+// hostname, creating the record in the reverse zone of the address's subnet
+// if needed, and returns the record. A ttl of 0 keeps the TTL of an existing
+// record, or uses dns.DefaultTTL for a new one.
+func SetPTR(sess *session.Session, ipAddress string, hostname string, ttl int) (datatypes.Dns_Domain_ResourceRecord, error) {
+	zone, host, record, err := findPTR(sess, ipAddress)
+	if err != nil {
+		return datatypes.Dns_Domain_ResourceRecord{}, err
+	}
+
+	service := services.GetDnsDomainResourceRecordService(sess)
+
+	if record == nil {
+		if ttl == 0 {
+			ttl = dns.DefaultTTL
+		}
+
+		return service.CreateObject(&datatypes.Dns_Domain_ResourceRecord{
+			DomainId: zone.Id,
+			Host:     sl.String(host),
+			Type:     sl.String("ptr"),
+			Data:     sl.String(hostname),
+			Ttl:      sl.Int(ttl),
+		})
+	}
+
+	template := datatypes.Dns_Domain_ResourceRecord{
+		Id:       record.Id,
+		DomainId: zone.Id,
+		Host:     record.Host,
+		Type:     record.Type,
+		Data:     sl.String(hostname),
+		Ttl:      record.Ttl,
+	}
+	if ttl != 0 {
+		template.Ttl = sl.Int(ttl)
+	}
+
+	if _, err := service.Id(*record.Id).EditObject(&template); err != nil {
+		return datatypes.Dns_Domain_ResourceRecord{}, fmt.Errorf("Error updating PTR record of %s: %s", ipAddress, err)
+	}
+
+	return template, nil
+}
+
+// findPTR returns the reverse zone holding the PTR record of an IP address,
+// the host of the record within that zone, and the record itself, or nil when
+// it does not exist
+func findPTR(sess *session.Session, ipAddress string) (datatypes.Dns_Domain, string, *datatypes.Dns_Domain_ResourceRecord, error) {
+	name, err := reverseName(ipAddress)
+	if err != nil {
+		return datatypes.Dns_Domain{}, "", nil, err
+	}
+
+	ip, err := getIpAddress(sess, ipAddress, "id,ipAddress,subnetId")
+	if err != nil {
+		return datatypes.Dns_Domain{}, "", nil, err
+	}
+
+	zones, err := services.GetNetworkSubnetService(sess).
+		Id(sl.Get(ip.SubnetId, 0).(int)).
+		Mask("id,name,resourceRecords[id,host,type,data,ttl]").
+		GetReverseDomainRecords()
+	if err != nil {
+		return datatypes.Dns_Domain{}, "", nil, err
+	}
+
+	for _, zone := range zones {
+		zoneName := strings.ToLower(sl.Get(zone.Name, "").(string))
+		if zoneName == "" || !strings.HasSuffix(name, "."+zoneName) {
+			continue
+		}
+
+		host := strings.TrimSuffix(name, "."+zoneName)
+		for i, record := range zone.ResourceRecords {
+			if strings.EqualFold(sl.Get(record.Type, "").(string), "ptr") &&
+				sl.Get(record.Host, "").(string) == host {
+				return zone, host, &zone.ResourceRecords[i], nil
+			}
+		}
+
+		return zone, host, nil, nil
+	}
+
+	return datatypes.Dns_Domain{}, "", nil, fmt.Errorf("No reverse zone found for IP address %s", ipAddress)
+}
+
+// reverseName returns the name of the PTR record of an IP address, e.g.
+// 10.2.0.192.in-addr.arpa for 192.0.2.10
+func reverseName(ipAddress string) (string, error) {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return "", fmt.Errorf("Invalid IP address %s", ipAddress)
+	}
+
+	if v4 := ip.To4(); v4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa", v4[3], v4[2], v4[1], v4[0]), nil
+	}
+
+	nibbles := make([]string, 0, 2*net.IPv6len)
+	for i := net.IPv6len - 1; i >= 0; i-- {
+		nibbles = append(nibbles, fmt.Sprintf("%x", ip[i]&0xf), fmt.Sprintf("%x", ip[i]>>4))
+	}
+
+	return strings.Join(nibbles, ".") + ".ip6.arpa", nil
+}
@@ -0,0 +1,41 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import "testing"
+
+func TestReverseName(t *testing.T) {
+	tests := []struct {
+		ipAddress string
+		expected  string
+		valid     bool
+	}{
+		{"192.0.2.10", "10.2.0.192.in-addr.arpa", true},
+		{"10.0.0.1", "1.0.0.10.in-addr.arpa", true},
+		{"::ffff:192.0.2.10", "10.2.0.192.in-addr.arpa", true},
+		{"2001:db8::567:89ab", "b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa", true},
+		{"192.0.2", "", false},
+		{"not an address", "", false},
+	}
+
+	for _, test := range tests {
+		name, err := reverseName(test.ipAddress)
+		if (err == nil) != test.valid || name != test.expected {
+			t.Errorf("Expected %q (valid: %t) for %s, got %q (error: %v)", test.expected, test.valid, test.ipAddress, name, err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Types of targets a subnet can be routed to
+const (
+	RouteToIpAddress = "SoftLayer_Network_Subnet_IpAddress"
+	RouteToVlan      = "SoftLayer_Network_Vlan"
+)
+
+// SubnetRoute describes where the traffic of a secondary subnet is routed.
+// Type is the routing type of the subnet (e.g. "STATIC", "PORTABLE"), and
+// EndpointIpAddress is set for subnets routed to an IP address, while VlanId
+// is the VLAN whose interface the subnet is bound to otherwise.
+type SubnetRoute struct {
+	Type              string
+	EndpointIpAddress string
+	VlanId            int
+	VlanNumber        int
+}
+
+// RouteSubnetToIpAddress routes the secondary subnet with the provided id to
+// the provided endpoint IP address
+func RouteSubnetToIpAddress(sess *session.Session, subnetId int, ipAddress string) error {
+	return routeSubnet(sess, subnetId, RouteToIpAddress, ipAddress)
+}
+
+// RouteSubnetToVlan routes the secondary subnet with the provided id to the
+// interface of the VLAN with the provided id
+func RouteSubnetToVlan(sess *session.Session, subnetId int, vlanId int) error {
+	return routeSubnet(sess, subnetId, RouteToVlan, strconv.Itoa(vlanId))
+}
+
+// UnrouteSubnet removes the route of the secondary subnet with the provided
+// id, which is then no longer reachable
+func UnrouteSubnet(sess *session.Session, subnetId int) error {
+	// Network_Subnet::clearRoute() is called directly, as it is missing from
+	// the generated services
+	var result bool
+	err := sess.DoRequest("SoftLayer_Network_Subnet", "clearRoute", nil, &sl.Options{Id: &subnetId}, &result)
+	if err != nil {
+		return fmt.Errorf("Error removing the route of subnet %d: %s", subnetId, err)
+	}
+
+	return nil
+}
+
+// GetSubnetRoute returns the current routing target of the subnet with the
+// provided id
+func GetSubnetRoute(sess *session.Session, subnetId int) (SubnetRoute, error) {
+	subnet, err := services.GetNetworkSubnetService(sess).
+		Id(subnetId).
+		Mask("id,routingTypeKeyName,endPointIpAddress[ipAddress],networkVlan[id,vlanNumber]").
+		GetObject()
+	if err != nil {
+		return SubnetRoute{}, err
+	}
+
+	route := SubnetRoute{
+		Type:              sl.Get(subnet.RoutingTypeKeyName, "").(string),
+		EndpointIpAddress: sl.Grab(subnet, "EndPointIpAddress.IpAddress", "").(string),
+	}
+
+	if route.EndpointIpAddress == "" {
+		route.VlanId = sl.Grab(subnet, "NetworkVlan.Id", 0).(int)
+		route.VlanNumber = sl.Grab(subnet, "NetworkVlan.VlanNumber", 0).(int)
+	}
+
+	return route, nil
+}
+
+func routeSubnet(sess *session.Session, subnetId int, targetType string, identifier string) error {
+	// Network_Subnet::route() is called directly, as it is missing from the
+	// generated services
+	var result bool
+	err := sess.DoRequest(
+		"SoftLayer_Network_Subnet",
+		"route",
+		[]interface{}{targetType, identifier},
+		&sl.Options{Id: &subnetId},
+		&result)
+	if err != nil {
+		return fmt.Errorf("Error routing subnet %d to %s: %s", subnetId, identifier, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// VlanSpanningState describes the VLAN spanning setting of the account, which
+// lets the private VLANs of the account reach each other. Applied reports
+// whether the last change of the setting reached the network.
+type VlanSpanningState struct {
+	Enabled         bool
+	Applied         bool
+	ModifyDate      *datatypes.Time
+	LastAppliedDate *datatypes.Time
+}
+
+// GetVlanSpanningState returns the VLAN spanning setting of the account
+func GetVlanSpanningState(sess *session.Session) (VlanSpanningState, error) {
+	span, err := services.GetAccountService(sess).
+		Mask("id,enabledFlag,modifyDate,lastAppliedDate").
+		GetNetworkVlanSpan()
+	if err != nil {
+		return VlanSpanningState{}, err
+	}
+
+	state := VlanSpanningState{
+		Enabled:         sl.Get(span.EnabledFlag, false).(bool),
+		ModifyDate:      span.ModifyDate,
+		LastAppliedDate: span.LastAppliedDate,
+	}
+
+	state.Applied = span.ModifyDate == nil ||
+		(span.LastAppliedDate != nil && !span.LastAppliedDate.Before(span.ModifyDate.Time))
+
+	return state, nil
+}
+
+// SetVlanSpanning enables or disables VLAN spanning on the account, and waits
+// until the change reaches the network. Nothing is changed when the setting
+// already has the requested value.
+func SetVlanSpanning(ctx context.Context, sess *session.Session, enabled bool, interval time.Duration) error {
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	state, err := GetVlanSpanningState(sess)
+	if err != nil {
+		return err
+	}
+
+	if state.Enabled != enabled {
+		if _, err := services.GetAccountService(sess).SetVlanSpan(sl.Bool(enabled)); err != nil {
+			return fmt.Errorf("Error setting VLAN spanning: %s", err)
+		}
+	}
+
+	for {
+		state, err := GetVlanSpanningState(sess)
+		if err != nil {
+			return err
+		}
+
+		if state.Enabled == enabled && state.Applied {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,194 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/helpers/product"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// SubnetPackageKeyName is the key name of the package subnets are ordered from
+const SubnetPackageKeyName = "ADDITIONAL_SERVICES"
+
+// Kinds of subnets. Portable subnets are attached to a VLAN, and their
+// addresses can be moved between the servers of the VLAN. Static subnets are
+// routed to an endpoint IP address.
+const (
+	SubnetPortable = "PORTABLE"
+	SubnetStatic   = "STATIC"
+)
+
+// SubnetOrder describes a subnet to order.
+//
+// Portable subnets are attached to the VLAN with id VlanId, and are public or
+// private depending on the VLAN. Static subnets are routed to the IP address
+// with id EndpointIpAddressId. Size is the number of addresses of the subnet,
+// and is ignored for IPv6 subnets, which always hold a /64.
+type SubnetOrder struct {
+	Kind                string
+	IPv6                bool
+	Size                int
+	VlanId              int
+	EndpointIpAddressId int
+}
+
+// subnetCategories maps the kind, IP version and network space of a subnet to
+// the category code of its items
+var subnetCategories = map[string]string{
+	SubnetPortable + "|4|" + VlanTypePublic:  "sov_sec_ip_addresses_pub",
+	SubnetPortable + "|4|" + VlanTypePrivate: "sov_sec_ip_addresses_priv",
+	SubnetPortable + "|6|" + VlanTypePublic:  "sov_ipv6_addresses",
+	SubnetStatic + "|4|" + VlanTypePublic:    "static_sec_ip_addresses",
+	SubnetStatic + "|6|" + VlanTypePublic:    "static_ipv6_addresses",
+}
+
+// OrderSubnet orders a subnet, and returns the order receipt. The price is
+// selected for the datacenter of the VLAN or endpoint IP address.
+func OrderSubnet(sess *session.Session, config SubnetOrder) (datatypes.Container_Product_Order_Receipt, error) {
+	order := datatypes.Container_Product_Order_Network_Subnet{}
+
+	var vlan datatypes.Network_Vlan
+	switch config.Kind {
+	case SubnetPortable:
+		if config.VlanId == 0 {
+			return datatypes.Container_Product_Order_Receipt{}, fmt.Errorf("A VLAN is required to order a portable subnet")
+		}
+
+		var err error
+		vlan, err = services.GetNetworkVlanService(sess).
+			Id(config.VlanId).
+			Mask("id,networkSpace,primaryRouter[datacenter[name]]").
+			GetObject()
+		if err != nil {
+			return datatypes.Container_Product_Order_Receipt{}, err
+		}
+
+		order.EndPointVlanId = sl.Int(config.VlanId)
+	case SubnetStatic:
+		if config.EndpointIpAddressId == 0 {
+			return datatypes.Container_Product_Order_Receipt{}, fmt.Errorf("An endpoint IP address is required to order a static subnet")
+		}
+
+		ip, err := services.GetNetworkSubnetIpAddressService(sess).
+			Id(config.EndpointIpAddressId).
+			Mask("id,subnet[networkVlan[id,networkSpace,primaryRouter[datacenter[name]]]]").
+			GetObject()
+		if err != nil {
+			return datatypes.Container_Product_Order_Receipt{}, err
+		}
+
+		vlan = sl.Grab(ip, "Subnet.NetworkVlan", datatypes.Network_Vlan{}).(datatypes.Network_Vlan)
+		order.EndPointIpAddressId = sl.Int(config.EndpointIpAddressId)
+	default:
+		return datatypes.Container_Product_Order_Receipt{}, fmt.Errorf("Unknown subnet kind %s", config.Kind)
+	}
+
+	version, size := 4, config.Size
+	if config.IPv6 {
+		version, size = 6, 64
+	}
+
+	category, ok := subnetCategories[fmt.Sprintf("%s|%d|%s", config.Kind, version, sl.Get(vlan.NetworkSpace, "").(string))]
+	if !ok {
+		return datatypes.Container_Product_Order_Receipt{},
+			fmt.Errorf("%s IPv%d subnets cannot be ordered on a %s VLAN", config.Kind, version, sl.Get(vlan.NetworkSpace, ""))
+	}
+
+	pkg, err := product.GetPackageByKeyName(sess, SubnetPackageKeyName, "id")
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	keyName, err := findSubnetItem(sess, *pkg.Id, category, size)
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	datacenter := sl.Grab(vlan, "PrimaryRouter.Datacenter.Name", "").(string)
+	price, err := product.GetPriceByKeyName(sess, *pkg.Id, keyName, product.PriceOptions{
+		Datacenter: datacenter,
+		Category:   category,
+	})
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	order.PackageId = pkg.Id
+	order.Quantity = sl.Int(1)
+	order.Prices = []datatypes.Product_Item_Price{{Id: price.Id}}
+
+	return services.GetProductOrderService(sess).PlaceOrder(&order, sl.Bool(false))
+}
+
+// CancelSubnet cancels the subnet with the provided id. Subnets with no
+// billing item, such as the primary subnets of VLANs, and subnets still in use
+// by servers or virtual guests cannot be canceled, and an error is returned
+// for them.
+func CancelSubnet(sess *session.Session, id int) error {
+	subnet, err := services.GetNetworkSubnetService(sess).
+		Id(id).
+		Mask("id,networkIdentifier,cidr,billingItem[id,pendingCancellationFlag],hardwareCount,virtualGuestCount").
+		GetObject()
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s/%d", sl.Get(subnet.NetworkIdentifier, ""), sl.Get(subnet.Cidr, 0))
+
+	if subnet.BillingItem == nil {
+		return fmt.Errorf("Subnet %s has no billing item and cannot be canceled", name)
+	}
+
+	if sl.Get(subnet.BillingItem.PendingCancellationFlag, false).(bool) {
+		return fmt.Errorf("Subnet %s is already pending cancellation", name)
+	}
+
+	hardware := sl.Get(subnet.HardwareCount, uint(0)).(uint)
+	guests := sl.Get(subnet.VirtualGuestCount, uint(0)).(uint)
+	if hardware+guests > 0 {
+		return fmt.Errorf("Subnet %s is in use by %d servers and %d virtual guests", name, hardware, guests)
+	}
+
+	_, err = services.GetBillingItemService(sess).Id(*subnet.BillingItem.Id).CancelService()
+	return err
+}
+
+// findSubnetItem returns the key name of the item of the provided category
+// holding the provided number of addresses
+func findSubnetItem(sess *session.Session, packageId int, category string, size int) (string, error) {
+	items, err := product.GetCachedPackageItems(sess, packageId)
+	if err != nil {
+		return "", err
+	}
+
+	for _, item := range items {
+		if sl.Grab(item, "ItemCategory.CategoryCode", "").(string) != category {
+			continue
+		}
+
+		if item.Capacity != nil && int(*item.Capacity) == size && item.KeyName != nil {
+			return *item.KeyName, nil
+		}
+	}
+
+	return "", fmt.Errorf("No %s item found for a subnet of %d addresses", category, size)
+}
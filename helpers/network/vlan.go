@@ -0,0 +1,203 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/helpers/location"
+	"github.com/softlayer/softlayer-go/helpers/product"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// VlanPackageKeyName is the key name of the package VLANs are ordered from
+const VlanPackageKeyName = "ADDITIONAL_SERVICES_NETWORK_VLAN"
+
+// Network spaces of a VLAN
+const (
+	VlanTypePublic  = "PUBLIC"
+	VlanTypePrivate = "PRIVATE"
+)
+
+// DefaultVlanMask is the default object mask for VLANs
+const DefaultVlanMask = "id,vlanNumber,name,networkSpace,note,fullyQualifiedName," +
+	"primaryRouter[id,hostname,datacenter[name]],billingItem[id]," +
+	"hardwareCount,virtualGuestCount,subnetCount"
+
+// VlanQuery holds the criteria used to search the VLANs of the account. Type
+// is one of VlanTypePublic or VlanTypePrivate. Zero values are ignored.
+type VlanQuery struct {
+	Datacenter string
+	Number     int
+	Name       string
+	Type       string
+}
+
+// VlanOrder describes a VLAN to order. RouterHostname is optional, and pins
+// the VLAN to one of the routers of the datacenter (e.g. "fcr01a.dal13").
+type VlanOrder struct {
+	Datacenter     string
+	RouterHostname string
+	Public         bool
+	Name           string
+}
+
+// FindVlans returns the VLANs of the account matching query. An object mask
+// can be provided as an optional argument, and DefaultVlanMask is used
+// otherwise.
+func FindVlans(sess *session.Session, query VlanQuery, mask ...string) ([]datatypes.Network_Vlan, error) {
+	objectMask := DefaultVlanMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	filters := filter.New()
+
+	if query.Datacenter != "" {
+		filters = append(filters, filter.Path("networkVlans.primaryRouter.datacenter.name").Eq(query.Datacenter))
+	}
+
+	if query.Number != 0 {
+		filters = append(filters, filter.Path("networkVlans.vlanNumber").Eq(query.Number))
+	}
+
+	if query.Name != "" {
+		filters = append(filters, filter.Path("networkVlans.name").Eq(query.Name))
+	}
+
+	if query.Type != "" {
+		filters = append(filters, filter.Path("networkVlans.networkSpace").Eq(query.Type))
+	}
+
+	return services.GetAccountService(sess).
+		Mask(objectMask).
+		Filter(filters.Build()).
+		GetNetworkVlans()
+}
+
+// IsAutomaticVlan reports whether the VLAN was assigned automatically to the
+// account, as opposed to ordered. Automatic VLANs have no billing item and
+// cannot be canceled: they are reclaimed once nothing uses them anymore. The
+// VLAN must have been retrieved with its billing item.
+func IsAutomaticVlan(vlan datatypes.Network_Vlan) bool {
+	return vlan.BillingItem == nil
+}
+
+// OrderVlan orders a VLAN, and returns the order receipt
+func OrderVlan(sess *session.Session, config VlanOrder) (datatypes.Container_Product_Order_Receipt, error) {
+	pkg, err := product.GetPackageByKeyName(sess, VlanPackageKeyName, "id")
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	itemKeyName := "PRIVATE_NETWORK_VLAN"
+	if config.Public {
+		itemKeyName = "PUBLIC_NETWORK_VLAN"
+	}
+
+	priceId, err := product.GetPriceIDByKeyName(sess, *pkg.Id, itemKeyName, product.PriceOptions{Datacenter: config.Datacenter})
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	order := datatypes.Container_Product_Order_Network_Vlan{
+		Container_Product_Order: datatypes.Container_Product_Order{
+			PackageId: pkg.Id,
+			Location:  sl.String(config.Datacenter),
+			Quantity:  sl.Int(1),
+			Prices: []datatypes.Product_Item_Price{
+				{Id: sl.Int(priceId)},
+			},
+		},
+	}
+
+	if config.Name != "" {
+		order.Name = sl.String(config.Name)
+	}
+
+	if config.RouterHostname != "" {
+		routerId, err := getRouterId(sess, config.Datacenter, config.RouterHostname)
+		if err != nil {
+			return datatypes.Container_Product_Order_Receipt{}, err
+		}
+		order.RouterId = sl.Int(routerId)
+	}
+
+	return services.GetProductOrderService(sess).PlaceOrder(&order, sl.Bool(false))
+}
+
+// CancelVlan cancels the VLAN with the provided id. VLANs which were assigned
+// automatically, or which still hold servers, virtual guests or subnets, cannot
+// be canceled and an error is returned for them.
+func CancelVlan(sess *session.Session, id int) error {
+	vlan, err := services.GetNetworkVlanService(sess).
+		Id(id).
+		Mask("id,vlanNumber,billingItem[id,pendingCancellationFlag],hardwareCount,virtualGuestCount,subnetCount").
+		GetObject()
+	if err != nil {
+		return err
+	}
+
+	number := sl.Get(vlan.VlanNumber, 0).(int)
+
+	if IsAutomaticVlan(vlan) {
+		return fmt.Errorf("VLAN %d was assigned automatically and cannot be canceled", number)
+	}
+
+	if sl.Get(vlan.BillingItem.PendingCancellationFlag, false).(bool) {
+		return fmt.Errorf("VLAN %d is already pending cancellation", number)
+	}
+
+	hardware := sl.Get(vlan.HardwareCount, uint(0)).(uint)
+	guests := sl.Get(vlan.VirtualGuestCount, uint(0)).(uint)
+	subnets := sl.Get(vlan.SubnetCount, uint(0)).(uint)
+	if hardware+guests+subnets > 0 {
+		return fmt.Errorf("VLAN %d is in use by %d servers, %d virtual guests and %d subnets",
+			number, hardware, guests, subnets)
+	}
+
+	_, err = services.GetBillingItemService(sess).Id(*vlan.BillingItem.Id).CancelService()
+	return err
+}
+
+// getRouterId returns the id of the router with the provided hostname in the
+// datacenter with the provided name
+func getRouterId(sess *session.Session, datacenter string, hostname string) (int, error) {
+	dc, err := location.GetLocationByName(sess, datacenter, "id")
+	if err != nil {
+		return 0, err
+	}
+
+	routers, err := services.GetLocationDatacenterService(sess).
+		Id(*dc.Id).
+		Mask("id,hostname").
+		Filter(filter.Build(filter.Path("hardwareRouters.hostname").Eq(hostname))).
+		GetHardwareRouters()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(routers) == 0 {
+		return 0, fmt.Errorf("No router found with hostname of %s in %s", hostname, datacenter)
+	}
+
+	return *routers[0].Id, nil
+}
@@ -0,0 +1,146 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Types of events
+const (
+	EventPlanned      = "PLANNED"
+	EventUnplanned    = "UNPLANNED_INCIDENT"
+	EventAnnouncement = "ANNOUNCEMENT"
+)
+
+// DefaultEventMask is the default object mask for events
+const DefaultEventMask = "id,subject,summary,startDate,endDate,modifyDate,recoveryTime,systemTicketId," +
+	"acknowledgedFlag,statusCode[keyName,name],notificationOccurrenceEventType[keyName]," +
+	"impactedResources[resourceTableId,resourceName,filterLabel]"
+
+// Query holds the criteria used to search the events affecting the account.
+// Empty fields are ignored. Type is the type of the events (e.g.
+// EventPlanned), ResourceTableId and ResourceName identify a resource the
+// events impact, e.g. the id and hostname of a server, and Unacknowledged
+// restricts the search to the events which were not acknowledged.
+type Query struct {
+	Type            string
+	ResourceTableId int
+	ResourceName    string
+	Unacknowledged  bool
+}
+
+// ListEvents returns the active and upcoming events affecting the account
+// which match query, earliest start first. An event is active or
+// upcoming when it has not ended. An object mask can be provided as an
+// optional argument, and DefaultEventMask is used otherwise.
+func ListEvents(sess *session.Session, query Query, mask ...string) ([]datatypes.Notification_Occurrence_Event, error) {
+	objectMask := DefaultEventMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	filters := filter.New()
+
+	if query.Type != "" {
+		filters = append(filters, filter.Path("notificationOccurrenceEventType.keyName").Eq(query.Type))
+	}
+
+	if query.ResourceTableId != 0 {
+		filters = append(filters, filter.Path("impactedResources.resourceTableId").Eq(query.ResourceTableId))
+	}
+
+	if query.ResourceName != "" {
+		filters = append(filters, filter.Path("impactedResources.resourceName").Eq(query.ResourceName))
+	}
+
+	if query.Unacknowledged {
+		filters = append(filters, filter.Path("acknowledgedFlag").Eq(0))
+	}
+
+	events, err := services.GetNotificationOccurrenceEventService(sess).
+		Mask(objectMask).
+		Filter(filters.Build()).
+		GetAllObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	active := []datatypes.Notification_Occurrence_Event{}
+	for _, event := range events {
+		if event.EndDate == nil || event.EndDate.After(now) {
+			active = append(active, event)
+		}
+	}
+
+	sort.SliceStable(active, func(i, j int) bool {
+		if active[i].StartDate == nil || active[j].StartDate == nil {
+			return active[i].StartDate != nil
+		}
+
+		return active[i].StartDate.Before(active[j].StartDate.Time)
+	})
+
+	return active, nil
+}
+
+// GetEventUpdates returns the updates of the event with the provided id,
+// describing its progress
+func GetEventUpdates(sess *session.Session, eventId int) ([]datatypes.Notification_Occurrence_Update, error) {
+	return services.GetNotificationOccurrenceEventService(sess).
+		Id(eventId).
+		Mask("createDate,startDate,endDate,contents").
+		GetUpdates()
+}
+
+// AcknowledgeEvents acknowledges the events with the provided ids on behalf
+// of the user the session is authenticated as
+func AcknowledgeEvents(sess *session.Session, eventIds ...int) error {
+	for _, id := range eventIds {
+		ok, err := services.GetNotificationOccurrenceEventService(sess).Id(id).AcknowledgeNotification()
+		if err != nil {
+			return fmt.Errorf("Error acknowledging event %d: %s", id, err)
+		}
+
+		if !ok {
+			return fmt.Errorf("Acknowledgement of event %d was not accepted", id)
+		}
+	}
+
+	return nil
+}
+
+// ImpactedResourceNames returns the names of the resources of the account
+// impacted by event
+func ImpactedResourceNames(event datatypes.Notification_Occurrence_Event) []string {
+	names := make([]string, 0, len(event.ImpactedResources))
+	for _, resource := range event.ImpactedResources {
+		names = append(names, sl.Get(resource.ResourceName, sl.Get(resource.FilterLabel, "")).(string))
+	}
+
+	return names
+}
@@ -0,0 +1,229 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Types of object storage endpoints
+const (
+	EndpointPublic  = "public"
+	EndpointPrivate = "private"
+)
+
+// DefaultAccountMask is the default object mask for object storage accounts
+const DefaultAccountMask = "id,username,uuid,notes,credentialCount"
+
+// Credential is an S3 (HMAC) credential of an object storage account
+type Credential struct {
+	Id        int
+	AccessKey string
+	SecretKey string
+	Type      string
+}
+
+// Endpoint is the URL object storage is reached at, in a region (e.g.
+// "us-geo") and location (e.g. "dal09"), from the public network or from the
+// private one. Type is one of EndpointPublic or EndpointPrivate.
+type Endpoint struct {
+	Region   string
+	Location string
+	Type     string
+	Url      string
+}
+
+// Bucket is the usage of a bucket
+type Bucket struct {
+	Name            string
+	StorageLocation string
+	BytesUsed       int
+	ObjectCount     int
+}
+
+// Usage is the usage of an object storage account
+type Usage struct {
+	BytesUsed int
+	Buckets   []Bucket
+}
+
+// ListAccounts returns the object storage accounts of the account. An object
+// mask can be provided as an optional argument, and DefaultAccountMask is used
+// otherwise.
+func ListAccounts(sess *session.Session, mask ...string) ([]datatypes.Network_Storage_Hub_Cleversafe_Account, error) {
+	objectMask := DefaultAccountMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	return services.GetNetworkStorageHubCleversafeAccountService(sess).Mask(objectMask).GetAllObjects()
+}
+
+// ListCredentials returns the S3 credentials of the object storage account
+// with the provided id
+func ListCredentials(sess *session.Session, accountId int) ([]Credential, error) {
+	credentials, err := services.GetNetworkStorageHubCleversafeAccountService(sess).
+		Id(accountId).
+		Mask("id,username,password,type[keyName]").
+		GetCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	return toCredentials(credentials), nil
+}
+
+// CreateCredential creates an S3 credential on the object storage account with
+// the provided id, and returns it. An error is returned when the account
+// already holds as many credentials as it is allowed to.
+func CreateCredential(sess *session.Session, accountId int) (Credential, error) {
+	service := services.GetNetworkStorageHubCleversafeAccountService(sess).Id(accountId)
+
+	current, err := ListCredentials(sess, accountId)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	limit, err := service.GetCredentialLimit()
+	if err != nil {
+		return Credential{}, err
+	}
+
+	if len(current) >= limit {
+		return Credential{}, fmt.Errorf("Object storage account %d already has %d credentials, the maximum", accountId, limit)
+	}
+
+	// The created credential is returned along with the existing ones
+	credentials, err := service.CredentialCreate()
+	if err != nil {
+		return Credential{}, fmt.Errorf("Error creating a credential on object storage account %d: %s", accountId, err)
+	}
+
+	existing := map[int]bool{}
+	for _, credential := range current {
+		existing[credential.Id] = true
+	}
+
+	for _, credential := range toCredentials(credentials) {
+		if !existing[credential.Id] {
+			return credential, nil
+		}
+	}
+
+	return Credential{}, fmt.Errorf("Could not find the credential created on object storage account %d", accountId)
+}
+
+// DeleteCredential deletes the S3 credential with the provided id from the
+// object storage account with the provided id
+func DeleteCredential(sess *session.Session, accountId int, credentialId int) error {
+	_, err := services.GetNetworkStorageHubCleversafeAccountService(sess).
+		Id(accountId).
+		CredentialDelete(&datatypes.Network_Storage_Credential{Id: sl.Int(credentialId)})
+	if err != nil {
+		return fmt.Errorf("Error deleting credential %d of object storage account %d: %s", credentialId, accountId, err)
+	}
+
+	return nil
+}
+
+// ListEndpoints returns the endpoints of the object storage account with the
+// provided id
+func ListEndpoints(sess *session.Session, accountId int) ([]Endpoint, error) {
+	endpoints, err := services.GetNetworkStorageHubCleversafeAccountService(sess).Id(accountId).GetEndpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Endpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		result = append(result, Endpoint{
+			Region:   sl.Get(endpoint.Region, "").(string),
+			Location: sl.Get(endpoint.Location, "").(string),
+			Type:     sl.Get(endpoint.Type, "").(string),
+			Url:      sl.Get(endpoint.Url, "").(string),
+		})
+	}
+
+	return result, nil
+}
+
+// GetEndpoint returns the URL of the endpoint of the provided type
+// (EndpointPublic or EndpointPrivate) of the object storage account with the
+// provided id, in the provided region or location
+func GetEndpoint(sess *session.Session, accountId int, region string, endpointType string) (string, error) {
+	endpoints, err := ListEndpoints(sess, accountId)
+	if err != nil {
+		return "", err
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.Type == endpointType && (endpoint.Region == region || endpoint.Location == region) {
+			return endpoint.Url, nil
+		}
+	}
+
+	return "", fmt.Errorf("No %s endpoint found in %s for object storage account %d", endpointType, region, accountId)
+}
+
+// GetUsage returns the capacity used by the object storage account with the
+// provided id, in total and per bucket
+func GetUsage(sess *session.Session, accountId int) (Usage, error) {
+	service := services.GetNetworkStorageHubCleversafeAccountService(sess).Id(accountId)
+
+	bytesUsed, err := service.GetCapacityUsage()
+	if err != nil {
+		return Usage{}, err
+	}
+
+	buckets, err := service.GetBuckets()
+	if err != nil {
+		return Usage{}, err
+	}
+
+	usage := Usage{BytesUsed: bytesUsed, Buckets: make([]Bucket, 0, len(buckets))}
+	for _, bucket := range buckets {
+		usage.Buckets = append(usage.Buckets, Bucket{
+			Name:            sl.Get(bucket.Name, "").(string),
+			StorageLocation: sl.Get(bucket.StorageLocation, "").(string),
+			BytesUsed:       sl.Get(bucket.BytesUsed, 0).(int),
+			ObjectCount:     sl.Get(bucket.ObjectCount, 0).(int),
+		})
+	}
+
+	return usage, nil
+}
+
+// toCredentials converts credentials, whose username and password are the
+// access and secret keys
+func toCredentials(credentials []datatypes.Network_Storage_Credential) []Credential {
+	result := make([]Credential, 0, len(credentials))
+	for _, credential := range credentials {
+		result = append(result, Credential{
+			Id:        sl.Get(credential.Id, 0).(int),
+			AccessKey: sl.Get(credential.Username, "").(string),
+			SecretKey: sl.Get(credential.Password, "").(string),
+			Type:      sl.Grab(credential, "Type.KeyName", "").(string),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,297 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package order
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/helpers/product"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// containerTypes maps the key name of a package type to a function wrapping a
+// generic order container in the container subtype expected for that type.
+// Packages of any other type are ordered with a plain Container_Product_Order.
+var containerTypes = map[string]func(datatypes.Container_Product_Order) interface{}{
+	"BARE_METAL_CPU":                hardwareServerContainer,
+	"BARE_METAL_CORE":               hardwareServerContainer,
+	"BARE_METAL_CPU_FAST_PROVISION": hardwareServerContainer,
+	"VIRTUAL_SERVER_INSTANCE":       virtualGuestContainer,
+	"SUSPEND_CLOUD_SERVER":          virtualGuestContainer,
+	"STORAGE_AS_A_SERVICE": func(o datatypes.Container_Product_Order) interface{} {
+		return &datatypes.Container_Product_Order_Network_Storage_AsAService{Container_Product_Order: o}
+	},
+	"ADDITIONAL_SERVICES_NETWORK_VLAN": func(o datatypes.Container_Product_Order) interface{} {
+		return &datatypes.Container_Product_Order_Network_Vlan{Container_Product_Order: o}
+	},
+	"ADDITIONAL_SERVICES_PORTABLE_IP_ADDRESS": subnetContainer,
+	"ADDITIONAL_SERVICES_STATIC_IP_ADDRESSES": subnetContainer,
+	"ADDITIONAL_SERVICES_GLOBAL_IP_ADDRESSES": subnetContainer,
+	"ADDITIONAL_SERVICES_SSL_CERTIFICATE": func(o datatypes.Container_Product_Order) interface{} {
+		return &datatypes.Container_Product_Order_Security_Certificate{Container_Product_Order: o}
+	},
+}
+
+// Builder assembles a product order from key names, resolving the package,
+// preset, location and price ids along the way. Create one with NewBuilder and
+// chain its setters, e.g.
+//
+//	receipt, err := order.NewBuilder(sess).
+//		Package("PUBLIC_CLOUD_SERVER").
+//		Preset("B1_2X8X100").
+//		Location("dal13").
+//		Item("OS_UBUNTU_16_04_LTS_XENIAL_XERUS_64_BIT", "BANDWIDTH_0_GB_2").
+//		Host("web01", "example.com").
+//		Place()
+//
+// Errors are reported by Build, Verify and Place.
+type Builder struct {
+	sess *session.Session
+
+	packageKeyName string
+	presetKeyName  string
+	location       string
+	itemKeyNames   []string
+	quantity       int
+	hourly         bool
+	term           int
+	promotionCode  string
+	hosts          []datatypes.Hardware
+
+	modifiers []func(*datatypes.Container_Product_Order)
+}
+
+// NewBuilder returns an empty order builder for the provided session
+func NewBuilder(sess *session.Session) *Builder {
+	return &Builder{sess: sess}
+}
+
+// Package sets the key name of the package to order from (e.g.
+// "PUBLIC_CLOUD_SERVER" or "BARE_METAL_SERVER")
+func (b *Builder) Package(keyName string) *Builder {
+	b.packageKeyName = keyName
+	return b
+}
+
+// Preset sets the key name of the package preset to order
+func (b *Builder) Preset(keyName string) *Builder {
+	b.presetKeyName = keyName
+	return b
+}
+
+// Location sets the short name of the datacenter to order in (e.g. "dal13").
+// Location specific prices are selected for the items of the order.
+func (b *Builder) Location(datacenter string) *Builder {
+	b.location = datacenter
+	return b
+}
+
+// Item adds the items with the provided key names to the order
+func (b *Builder) Item(keyNames ...string) *Builder {
+	b.itemKeyNames = append(b.itemKeyNames, keyNames...)
+	return b
+}
+
+// Quantity sets the number of copies of the configuration to order. It
+// defaults to the number of hosts, or to 1 when no host was added.
+func (b *Builder) Quantity(quantity int) *Builder {
+	b.quantity = quantity
+	return b
+}
+
+// Hourly selects hourly billing instead of monthly billing
+func (b *Builder) Hourly(hourly bool) *Builder {
+	b.hourly = hourly
+	return b
+}
+
+// Term selects the prices committing to a term of the provided number of
+// months (see product.OneYear and product.ThreeYears) for the items of the
+// order. An error is reported when an item has no price for the term, or when
+// a price added by a modifier does not commit to the term.
+func (b *Builder) Term(months int) *Builder {
+	b.term = months
+	return b
+}
+
+// PromotionCode sets the promotion code applied to the order
+func (b *Builder) PromotionCode(code string) *Builder {
+	b.promotionCode = code
+	return b
+}
+
+// Host adds a host to the order, for packages of servers. Each host accounts
+// for one copy of the configuration.
+func (b *Builder) Host(hostname string, domain string) *Builder {
+	b.hosts = append(b.hosts, datatypes.Hardware{
+		Hostname: sl.String(hostname),
+		Domain:   sl.String(domain),
+	})
+	return b
+}
+
+// Modify registers a function called on the order container once it is
+// built, to set the properties the builder does not cover.
+func (b *Builder) Modify(modifier func(*datatypes.Container_Product_Order)) *Builder {
+	b.modifiers = append(b.modifiers, modifier)
+	return b
+}
+
+// Build resolves the key names of the order, and returns a pointer to the
+// Container_Product_Order subtype matching the type of the package, ready to
+// be passed to verifyOrder or placeOrder.
+func (b *Builder) Build() (interface{}, error) {
+	if b.packageKeyName == "" {
+		return nil, fmt.Errorf("No package set for the order")
+	}
+
+	pkg, err := product.GetPackageByKeyName(b.sess, b.packageKeyName, "id,keyName,type[keyName]")
+	if err != nil {
+		return nil, err
+	}
+
+	container := datatypes.Container_Product_Order{
+		PackageId:        pkg.Id,
+		UseHourlyPricing: sl.Bool(b.hourly),
+		Prices:           []datatypes.Product_Item_Price{},
+	}
+
+	if b.presetKeyName != "" {
+		preset, err := product.GetPresetByKeyName(b.sess, b.packageKeyName, b.presetKeyName, "id,keyName")
+		if err != nil {
+			return nil, err
+		}
+		container.PresetId = preset.Id
+	}
+
+	if b.location != "" {
+		container.Location = sl.String(b.location)
+	}
+
+	if b.promotionCode != "" {
+		container.PromotionCode = sl.String(b.promotionCode)
+	}
+
+	options := product.PriceOptions{Datacenter: b.location, Hourly: b.hourly}
+	termPriceIds := map[int]bool{}
+	for _, keyName := range b.itemKeyNames {
+		var price datatypes.Product_Item_Price
+		if b.term != product.NoTerm {
+			price, err = product.GetTermPriceByKeyName(b.sess, *pkg.Id, keyName, b.term, options)
+		} else {
+			price, err = product.GetPriceByKeyName(b.sess, *pkg.Id, keyName, options)
+		}
+		if err != nil {
+			return nil, err
+		}
+		container.Prices = append(container.Prices, datatypes.Product_Item_Price{Id: price.Id})
+		termPriceIds[*price.Id] = true
+	}
+
+	quantity := b.quantity
+	if quantity == 0 {
+		quantity = len(b.hosts)
+	}
+	if quantity == 0 {
+		quantity = 1
+	}
+	container.Quantity = sl.Int(quantity)
+
+	if len(b.hosts) > 0 {
+		container.Hardware = b.hosts
+		container.VirtualGuests = make([]datatypes.Virtual_Guest, 0, len(b.hosts))
+		for _, host := range b.hosts {
+			container.VirtualGuests = append(container.VirtualGuests, datatypes.Virtual_Guest{
+				Hostname: host.Hostname,
+				Domain:   host.Domain,
+			})
+		}
+	}
+
+	for _, modifier := range b.modifiers {
+		modifier(&container)
+	}
+
+	// Prices added by the modifiers must commit to the term as well
+	if b.term != product.NoTerm {
+		var priceIds []int
+		for _, price := range container.Prices {
+			if price.Id != nil && !termPriceIds[*price.Id] {
+				priceIds = append(priceIds, *price.Id)
+			}
+		}
+		if err := product.ValidateTermPrices(b.sess, priceIds, b.term); err != nil {
+			return nil, err
+		}
+	}
+
+	wrap, ok := containerTypes[sl.Grab(pkg, "Type.KeyName", "").(string)]
+	if !ok {
+		return &container, nil
+	}
+
+	return wrap(container), nil
+}
+
+// Verify builds the order and verifies it without placing it, returning the
+// cost breakdown of the order
+func (b *Builder) Verify() (product.CostBreakdown, error) {
+	orderData, err := b.Build()
+	if err != nil {
+		return product.CostBreakdown{}, err
+	}
+
+	return product.VerifyOrder(b.sess, orderData)
+}
+
+// Place builds the order, verifies it and places it
+func (b *Builder) Place() (datatypes.Container_Product_Order_Receipt, error) {
+	orderData, err := b.Build()
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	service := services.GetProductOrderService(b.sess)
+
+	if _, err := service.VerifyOrder(orderData); err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, fmt.Errorf("Error verifying order: %s", err)
+	}
+
+	return service.PlaceOrder(orderData, sl.Bool(false))
+}
+
+func hardwareServerContainer(o datatypes.Container_Product_Order) interface{} {
+	// Server packages are ordered by hardware, not virtual guests
+	o.VirtualGuests = nil
+	return &datatypes.Container_Product_Order_Hardware_Server{Container_Product_Order: o}
+}
+
+func virtualGuestContainer(o datatypes.Container_Product_Order) interface{} {
+	o.Hardware = nil
+	return &datatypes.Container_Product_Order_Virtual_Guest{
+		Container_Product_Order_Hardware_Server: datatypes.Container_Product_Order_Hardware_Server{
+			Container_Product_Order: o,
+		},
+	}
+}
+
+func subnetContainer(o datatypes.Container_Product_Order) interface{} {
+	return &datatypes.Container_Product_Order_Network_Subnet{Container_Product_Order: o}
+}
@@ -0,0 +1,88 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package order
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/helpers/product"
+)
+
+// HoursPerMonth is the number of hours in an average month, used to project
+// hourly costs over a month
+const HoursPerMonth = 730
+
+// PricingComparison holds the verified costs of the same configuration billed
+// hourly and monthly. Rates are recurring fees before tax.
+type PricingComparison struct {
+	Hourly  product.CostBreakdown
+	Monthly product.CostBreakdown
+
+	HourlyRate  float64
+	MonthlyRate float64
+
+	// BreakEvenHours is the number of hours of use per month above which
+	// monthly billing is cheaper than hourly billing. It is zero when the
+	// configuration carries no hourly fee.
+	BreakEvenHours float64
+}
+
+// HourlyCost returns the projected cost of running the configuration for the
+// provided number of hours in a month with hourly billing
+func (c PricingComparison) HourlyCost(hours float64) float64 {
+	return c.HourlyRate * hours
+}
+
+// CheaperHourly returns true if hourly billing costs less than monthly billing
+// when the configuration runs for the provided number of hours in a month
+func (c PricingComparison) CheaperHourly(hours float64) bool {
+	return c.HourlyCost(hours) < c.MonthlyRate
+}
+
+// ComparePricing verifies the order of the builder twice, once with hourly
+// billing and once with monthly billing, and returns both costs side by side.
+// The builder itself is left unchanged.
+func (b *Builder) ComparePricing() (PricingComparison, error) {
+	hourly := *b
+	hourly.hourly = true
+
+	hourlyCosts, err := hourly.Verify()
+	if err != nil {
+		return PricingComparison{}, fmt.Errorf("Error verifying hourly order: %s", err)
+	}
+
+	monthly := *b
+	monthly.hourly = false
+
+	monthlyCosts, err := monthly.Verify()
+	if err != nil {
+		return PricingComparison{}, fmt.Errorf("Error verifying monthly order: %s", err)
+	}
+
+	comparison := PricingComparison{
+		Hourly:      hourlyCosts,
+		Monthly:     monthlyCosts,
+		HourlyRate:  hourlyCosts.RecurringBeforeTax,
+		MonthlyRate: monthlyCosts.RecurringBeforeTax,
+	}
+
+	if comparison.HourlyRate > 0 {
+		comparison.BreakEvenHours = comparison.MonthlyRate / comparison.HourlyRate
+	}
+
+	return comparison, nil
+}
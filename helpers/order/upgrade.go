@@ -0,0 +1,271 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package order
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/helpers/product"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+const (
+	upgradeBillingItemMask = "id,categoryCode,package[id],item[keyName],activeChildren[categoryCode,item[keyName]]"
+	upgradePriceMask       = "id,locationGroupId,hourlyRecurringFee,recurringFee,categories[categoryCode],item[keyName]"
+)
+
+// UpgradeBuilder assembles an upgrade order for an existing virtual guest or
+// bare metal server. It is given the key names of the items the resource
+// should have, and only orders the ones it does not already have, e.g.
+//
+//	receipt, err := order.NewUpgradeBuilder(sess).
+//		VirtualGuest(guestId).
+//		Item("GUEST_CORE_4", "RAM_8_GB").
+//		Place()
+//
+// Errors are reported by Build, Verify and Place.
+type UpgradeBuilder struct {
+	sess *session.Session
+
+	guestId      int
+	hardwareId   int
+	itemKeyNames []string
+	when         time.Time
+}
+
+// NewUpgradeBuilder returns an empty upgrade order builder for the provided
+// session
+func NewUpgradeBuilder(sess *session.Session) *UpgradeBuilder {
+	return &UpgradeBuilder{sess: sess}
+}
+
+// VirtualGuest sets the id of the virtual guest to upgrade
+func (b *UpgradeBuilder) VirtualGuest(id int) *UpgradeBuilder {
+	b.guestId = id
+	b.hardwareId = 0
+	return b
+}
+
+// Hardware sets the id of the bare metal server to upgrade
+func (b *UpgradeBuilder) Hardware(id int) *UpgradeBuilder {
+	b.hardwareId = id
+	b.guestId = 0
+	return b
+}
+
+// Item adds the key names of items the resource should have after the upgrade
+func (b *UpgradeBuilder) Item(keyNames ...string) *UpgradeBuilder {
+	b.itemKeyNames = append(b.itemKeyNames, keyNames...)
+	return b
+}
+
+// MaintenanceWindow sets when the upgrade takes place. The upgrade takes place
+// immediately by default.
+func (b *UpgradeBuilder) MaintenanceWindow(when time.Time) *UpgradeBuilder {
+	b.when = when
+	return b
+}
+
+// Delta returns the key names of the items requested which the resource does
+// not have yet, which are the only items included in the upgrade order
+func (b *UpgradeBuilder) Delta() ([]string, error) {
+	billingItem, err := b.getBillingItem()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.delta(billingItem), nil
+}
+
+// Build resolves the current configuration of the resource and the upgrade
+// prices of the missing items, and returns a pointer to the upgrade order
+// container, ready to be passed to verifyOrder or placeOrder. An error is
+// returned when the resource already has all the requested items.
+func (b *UpgradeBuilder) Build() (interface{}, error) {
+	billingItem, err := b.getBillingItem()
+	if err != nil {
+		return nil, err
+	}
+
+	delta := b.delta(billingItem)
+	if len(delta) == 0 {
+		return nil, fmt.Errorf("Nothing to upgrade: all the requested items are already in place")
+	}
+
+	upgradePrices, err := b.getUpgradeItemPrices()
+	if err != nil {
+		return nil, err
+	}
+
+	prices := []datatypes.Product_Item_Price{}
+	for _, keyName := range delta {
+		price, ok := selectUpgradePrice(upgradePrices, keyName)
+		if !ok {
+			return nil, fmt.Errorf("Item %s is not an available upgrade", keyName)
+		}
+		prices = append(prices, datatypes.Product_Item_Price{Id: price.Id})
+	}
+
+	upgradeTime := time.Now().UTC().Format(time.RFC3339)
+	if !b.when.IsZero() {
+		upgradeTime = b.when.UTC().Format(time.RFC3339)
+	}
+
+	container := datatypes.Container_Product_Order{
+		PackageId: sl.Int(sl.Grab(billingItem, "Package.Id", 0).(int)),
+		Prices:    prices,
+		Properties: []datatypes.Container_Product_Order_Property{
+			{
+				Name:  sl.String("MAINTENANCE_WINDOW"),
+				Value: sl.String(upgradeTime),
+			},
+		},
+	}
+
+	if b.guestId != 0 {
+		container.VirtualGuests = []datatypes.Virtual_Guest{{Id: sl.Int(b.guestId)}}
+		return &datatypes.Container_Product_Order_Virtual_Guest_Upgrade{
+			Container_Product_Order_Virtual_Guest: datatypes.Container_Product_Order_Virtual_Guest{
+				Container_Product_Order_Hardware_Server: datatypes.Container_Product_Order_Hardware_Server{
+					Container_Product_Order: container,
+				},
+			},
+		}, nil
+	}
+
+	container.Hardware = []datatypes.Hardware{{Id: sl.Int(b.hardwareId)}}
+	return &datatypes.Container_Product_Order_Hardware_Server_Upgrade{
+		Container_Product_Order_Hardware_Server: datatypes.Container_Product_Order_Hardware_Server{
+			Container_Product_Order: container,
+		},
+	}, nil
+}
+
+// Verify builds the upgrade order and verifies it without placing it,
+// returning the cost breakdown of the order
+func (b *UpgradeBuilder) Verify() (product.CostBreakdown, error) {
+	orderData, err := b.Build()
+	if err != nil {
+		return product.CostBreakdown{}, err
+	}
+
+	return product.VerifyOrder(b.sess, orderData)
+}
+
+// Place builds the upgrade order, verifies it and places it
+func (b *UpgradeBuilder) Place() (datatypes.Container_Product_Order_Receipt, error) {
+	orderData, err := b.Build()
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	service := services.GetProductOrderService(b.sess)
+
+	if _, err := service.VerifyOrder(orderData); err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, fmt.Errorf("Error verifying upgrade order: %s", err)
+	}
+
+	return service.PlaceOrder(orderData, sl.Bool(false))
+}
+
+// getBillingItem returns the billing item of the resource, along with its
+// active children, which together describe its current configuration
+func (b *UpgradeBuilder) getBillingItem() (datatypes.Billing_Item, error) {
+	switch {
+	case b.guestId != 0:
+		billingItem, err := services.GetVirtualGuestService(b.sess).
+			Id(b.guestId).
+			Mask(upgradeBillingItemMask).
+			GetBillingItem()
+		return billingItem.Billing_Item, err
+	case b.hardwareId != 0:
+		billingItem, err := services.GetHardwareServerService(b.sess).
+			Id(b.hardwareId).
+			Mask(upgradeBillingItemMask).
+			GetBillingItem()
+		return billingItem.Billing_Item, err
+	}
+
+	return datatypes.Billing_Item{}, fmt.Errorf("No virtual guest or hardware set for the upgrade order")
+}
+
+func (b *UpgradeBuilder) getUpgradeItemPrices() ([]datatypes.Product_Item_Price, error) {
+	if b.guestId != 0 {
+		return services.GetVirtualGuestService(b.sess).
+			Id(b.guestId).
+			Mask(upgradePriceMask).
+			GetUpgradeItemPrices(sl.Bool(true))
+	}
+
+	return services.GetHardwareServerService(b.sess).
+		Id(b.hardwareId).
+		Mask(upgradePriceMask).
+		GetUpgradeItemPrices()
+}
+
+func (b *UpgradeBuilder) delta(billingItem datatypes.Billing_Item) []string {
+	current := map[string]bool{}
+	if billingItem.Item != nil && billingItem.Item.KeyName != nil {
+		current[*billingItem.Item.KeyName] = true
+	}
+
+	for _, child := range billingItem.ActiveChildren {
+		if child.Item != nil && child.Item.KeyName != nil {
+			current[*child.Item.KeyName] = true
+		}
+	}
+
+	delta := []string{}
+	for _, keyName := range b.itemKeyNames {
+		if !current[keyName] {
+			delta = append(delta, keyName)
+		}
+	}
+
+	return delta
+}
+
+// selectUpgradePrice returns the upgrade price of the item with the provided
+// key name, preferring the standard price over location specific ones, as
+// upgrade prices are already narrowed down to the location of the resource
+func selectUpgradePrice(prices []datatypes.Product_Item_Price, keyName string) (datatypes.Product_Item_Price, bool) {
+	var match *datatypes.Product_Item_Price
+
+	for i, price := range prices {
+		if sl.Grab(price, "Item.KeyName", "").(string) != keyName {
+			continue
+		}
+
+		if price.LocationGroupId == nil {
+			return price, true
+		}
+
+		if match == nil {
+			match = &prices[i]
+		}
+	}
+
+	if match != nil {
+		return *match, true
+	}
+
+	return datatypes.Product_Item_Price{}, false
+}
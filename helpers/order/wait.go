@@ -0,0 +1,189 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DefaultPollInterval is the time waited between two checks of an order by
+// WaitForOrder, when it is passed a zero interval. The wait is abandoned, and
+// the context's error returned, once the context is done.
+const DefaultPollInterval = 30 * time.Second
+
+// ResourceType identifies the kind of resource provisioned by an order
+type ResourceType string
+
+const (
+	ResourceVirtualGuest   ResourceType = "SoftLayer_Virtual_Guest"
+	ResourceHardware       ResourceType = "SoftLayer_Hardware"
+	ResourceNetworkStorage ResourceType = "SoftLayer_Network_Storage"
+)
+
+// resourceCategories maps the category code of the top level item of an
+// order to the type of resource the item provisions
+var resourceCategories = map[string]ResourceType{
+	"guest_core":                   ResourceVirtualGuest,
+	"server":                       ResourceHardware,
+	"server_core":                  ResourceHardware,
+	"storage_as_a_service":         ResourceNetworkStorage,
+	"storage_service_enterprise":   ResourceNetworkStorage,
+	"performance_storage_iscsi":    ResourceNetworkStorage,
+	"performance_storage_nfs":      ResourceNetworkStorage,
+	"storage_file":                 ResourceNetworkStorage,
+	"storage_block":                ResourceNetworkStorage,
+	"enterprise_storage_iscsi":     ResourceNetworkStorage,
+	"enterprise_storage_nfs":       ResourceNetworkStorage,
+	"storage_snapshot_space":       ResourceNetworkStorage,
+	"storage_replicant_nfs":        ResourceNetworkStorage,
+	"storage_replicant_iscsi":      ResourceNetworkStorage,
+	"performance_storage_replicas": ResourceNetworkStorage,
+}
+
+// OrderResource identifies a resource provisioned by an order
+type OrderResource struct {
+	Type ResourceType
+	Id   int
+}
+
+// WaitForOrder polls the Billing_Order with the provided id until the virtual
+// guests, servers and storage volumes it provisions exist, and returns them.
+// An error is returned as soon as the order is cancelled or rejected, and for
+// orders provisioning none of these resources.
+func WaitForOrder(
+	ctx context.Context,
+	sess *session.Session,
+	orderId int,
+	interval time.Duration,
+) ([]OrderResource, error) {
+
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	service := services.GetBillingOrderService(sess)
+
+	for {
+		order, err := service.Id(orderId).Mask("id,status,orderTopLevelItems[id,categoryCode]").GetObject()
+		if err != nil {
+			return nil, err
+		}
+
+		status := sl.Get(order.Status, "").(string)
+		if status == "CANCELLED" || status == "REJECTED" {
+			return nil, fmt.Errorf("Order %d was not approved: %s", orderId, status)
+		}
+
+		expected := map[ResourceType]int{}
+		for _, item := range order.OrderTopLevelItems {
+			if resourceType, ok := resourceCategories[sl.Get(item.CategoryCode, "").(string)]; ok {
+				expected[resourceType]++
+			}
+		}
+
+		if len(expected) == 0 {
+			return nil, fmt.Errorf("Order %d does not provision any virtual guest, server or storage volume", orderId)
+		}
+
+		if status == "APPROVED" || status == "COMPLETE" {
+			resources, err := findOrderResources(sess, orderId, expected)
+			if err != nil {
+				return nil, err
+			}
+
+			if resources != nil {
+				return resources, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// findOrderResources returns the resources of the account whose billing item
+// was ordered with the provided order, or nil if fewer resources of a type
+// than expected exist yet
+func findOrderResources(sess *session.Session, orderId int, expected map[ResourceType]int) ([]OrderResource, error) {
+	service := services.GetAccountService(sess).Mask("id")
+
+	resources := []OrderResource{}
+	ids := []int{}
+
+	for resourceType, count := range expected {
+		ids = ids[:0]
+
+		switch resourceType {
+		case ResourceVirtualGuest:
+			guests, err := service.
+				Filter(orderFilter("virtualGuests", orderId)).
+				GetVirtualGuests()
+			if err != nil {
+				return nil, err
+			}
+			for _, guest := range guests {
+				ids = append(ids, *guest.Id)
+			}
+		case ResourceHardware:
+			hardware, err := service.
+				Filter(orderFilter("hardware", orderId)).
+				GetHardware()
+			if err != nil {
+				return nil, err
+			}
+			for _, h := range hardware {
+				ids = append(ids, *h.Id)
+			}
+		case ResourceNetworkStorage:
+			volumes, err := service.
+				Filter(orderFilter("networkStorage", orderId)).
+				GetNetworkStorage()
+			if err != nil {
+				return nil, err
+			}
+			for _, volume := range volumes {
+				ids = append(ids, *volume.Id)
+			}
+		}
+
+		if len(ids) < count {
+			return nil, nil
+		}
+
+		for _, id := range ids {
+			resources = append(resources, OrderResource{Type: resourceType, Id: id})
+		}
+	}
+
+	return resources, nil
+}
+
+// orderFilter returns the object filter selecting the items of a relational
+// property of the account whose billing item was ordered with the provided order
+func orderFilter(property string, orderId int) string {
+	return filter.Build(filter.Path(property + ".billingItem.orderItem.order.id").Eq(orderId))
+}
@@ -0,0 +1,61 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package product
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/session"
+)
+
+// cache holds the packages by key name, the product items of each package,
+// and the price group ids of each datacenter, keyed by session, along with the
+// optional persistent store backing them (see SetCatalogStore)
+var cache = struct {
+	sync.Mutex
+	packages    map[string]datatypes.Product_Package
+	items       map[string][]datatypes.Product_Item
+	priceGroups map[string][]int
+	store       CatalogStore
+}{
+	packages:    map[string]datatypes.Product_Package{},
+	items:       map[string][]datatypes.Product_Item{},
+	priceGroups: map[string][]int{},
+}
+
+// ClearCache empties the in-memory cache of packages, package items and
+// datacenter price groups. The catalog store, if any, is left untouched.
+func ClearCache() {
+	cache.Lock()
+	defer cache.Unlock()
+
+	cache.packages = map[string]datatypes.Product_Package{}
+	cache.items = map[string][]datatypes.Product_Item{}
+	cache.priceGroups = map[string][]int{}
+}
+
+// cacheKey returns the key under which a value is cached for the session
+func cacheKey(sess *session.Session, parts ...interface{}) string {
+	key := sess.Endpoint + "|" + sess.UserName
+	for _, part := range parts {
+		key = fmt.Sprintf("%s|%v", key, part)
+	}
+
+	return key
+}
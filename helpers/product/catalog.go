@@ -0,0 +1,192 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package product
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/session"
+)
+
+// CatalogStore persists the catalog data cached by this package (packages,
+// package items and prices, datacenter price groups) across processes.
+//
+// Load decodes the value stored under key into v, and returns false when
+// nothing usable is stored under key. Save stores v under key. Clear removes
+// every stored value.
+type CatalogStore interface {
+	Load(key string, v interface{}) (bool, error)
+	Save(key string, v interface{}) error
+	Clear() error
+}
+
+// SetCatalogStore makes the catalog lookups of this package read from and
+// write to store, in addition to the in-memory cache. Pass nil to go back to
+// in-memory caching only. Store errors are not fatal: a failed load is treated
+// as a miss, and a failed save is ignored.
+func SetCatalogStore(store CatalogStore) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	cache.store = store
+}
+
+// RefreshCatalog downloads the items of the package with the provided id
+// again, replacing the ones held in memory and in the catalog store.
+func RefreshCatalog(sess *session.Session, packageId int) ([]datatypes.Product_Item, error) {
+	items, err := GetPackageProducts(sess, packageId, PriceItemMask)
+	if err != nil {
+		return nil, err
+	}
+
+	key := cacheKey(sess, packageId)
+
+	cache.Lock()
+	cache.items[key] = items
+	cache.Unlock()
+
+	saveStored("items", key, items)
+
+	return items, nil
+}
+
+// FileStore is a CatalogStore keeping each value as a JSON file in a
+// directory. Values older than MaxAge are ignored, unless MaxAge is zero.
+type FileStore struct {
+	Dir    string
+	MaxAge time.Duration
+}
+
+// NewFileStore returns a FileStore writing to dir, which is created if needed
+func NewFileStore(dir string, maxAge time.Duration) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &FileStore{Dir: dir, MaxAge: maxAge}, nil
+}
+
+// Load implements CatalogStore
+func (s *FileStore) Load(key string, v interface{}) (bool, error) {
+	path := s.path(key)
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if s.MaxAge > 0 && time.Since(info.ModTime()) > s.MaxAge {
+		return false, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Save implements CatalogStore. Values are written to a temporary file first,
+// so that concurrent readers never see a partial file.
+func (s *FileStore) Save(key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(s.Dir, ".catalog")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// Clear implements CatalogStore
+func (s *FileStore) Clear() error {
+	files, err := filepath.Glob(filepath.Join(s.Dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// path returns the file holding the value stored under key. Keys are hashed,
+// as they hold characters which are not valid in file names.
+func (s *FileStore) path(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(s.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// loadStored decodes the value stored under key for the kind of data into v,
+// if a catalog store is set
+func loadStored(kind string, key string, v interface{}) bool {
+	cache.Lock()
+	store := cache.store
+	cache.Unlock()
+
+	if store == nil {
+		return false
+	}
+
+	ok, err := store.Load(kind+"|"+key, v)
+	return ok && err == nil
+}
+
+// saveStored stores v under key for the kind of data, if a catalog store is
+// set
+func saveStored(kind string, key string, v interface{}) {
+	cache.Lock()
+	store := cache.store
+	cache.Unlock()
+
+	if store != nil {
+		store.Save(kind+"|"+key, v)
+	}
+}
@@ -0,0 +1,173 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package product
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DefaultCurrency is the currency catalog prices are expressed in
+const DefaultCurrency = "USD"
+
+// GetAccountCurrency returns the key name of the currency the account is
+// billed in (e.g. "EUR"). DefaultCurrency is returned when the billing
+// information of the account names no currency.
+func GetAccountCurrency(sess *session.Session) (string, error) {
+	info, err := services.GetAccountService(sess).Mask("id,currency[keyName]").GetBillingInfo()
+	if err != nil {
+		return "", err
+	}
+
+	return sl.Grab(info, "Currency.KeyName", DefaultCurrency).(string), nil
+}
+
+// GetExchangeRate returns the current rate by which an amount in the from
+// currency is multiplied to express it in the to currency
+func GetExchangeRate(sess *session.Session, from string, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	rate, err := services.GetBillingCurrencyExchangeRateService(sess).
+		Mask("id,rate").
+		GetExchangeRate(sl.String(to), sl.String(from), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if rate.Rate == nil {
+		return 0, fmt.Errorf("No exchange rate found from %s to %s", from, to)
+	}
+
+	return float64(*rate.Rate), nil
+}
+
+// Convert returns a copy of the cost breakdown with every amount multiplied
+// by rate, expressed in currency. The original currency and the rate are
+// recorded in the OriginalCurrency and ExchangeRate fields of the copy.
+func (b CostBreakdown) Convert(currency string, rate float64) CostBreakdown {
+	converted := b
+	converted.Currency = currency
+	converted.OriginalCurrency = b.Currency
+	converted.ExchangeRate = rate
+
+	converted.Items = make([]ItemCost, len(b.Items))
+	for i, item := range b.Items {
+		item.RecurringFee *= rate
+		item.HourlyRecurringFee *= rate
+		item.SetupFee *= rate
+		item.OneTimeFee *= rate
+		item.LaborFee *= rate
+		item.ProratedRecurringFee *= rate
+		converted.Items[i] = item
+	}
+
+	converted.RecurringBeforeTax *= rate
+	converted.RecurringTax *= rate
+	converted.RecurringAfterTax *= rate
+	converted.SetupBeforeTax *= rate
+	converted.SetupTax *= rate
+	converted.SetupAfterTax *= rate
+	converted.ProratedInitialCharge *= rate
+	converted.ProratedOrderTotal *= rate
+
+	return converted
+}
+
+// VerifyOrderInAccountCurrency is like VerifyOrder, but expresses the cost
+// breakdown in the billing currency of the account. The amounts are converted
+// at the current exchange rate when verifyOrder returns them in another
+// currency.
+func VerifyOrderInAccountCurrency(sess *session.Session, orderData interface{}) (CostBreakdown, error) {
+	breakdown, err := VerifyOrder(sess, orderData)
+	if err != nil {
+		return CostBreakdown{}, err
+	}
+
+	currency, err := GetAccountCurrency(sess)
+	if err != nil {
+		return CostBreakdown{}, err
+	}
+
+	from := breakdown.Currency
+	if from == "" {
+		from = DefaultCurrency
+	}
+
+	if from == currency {
+		breakdown.Currency = currency
+		return breakdown, nil
+	}
+
+	rate, err := GetExchangeRate(sess, from, currency)
+	if err != nil {
+		return CostBreakdown{}, err
+	}
+
+	converted := breakdown.Convert(currency, rate)
+	converted.OriginalCurrency = from
+
+	return converted, nil
+}
+
+// GetPriceInAccountCurrency is like GetPriceByKeyName, but returns the fees of
+// the price in the billing currency of the account, along with the key name
+// of that currency
+func GetPriceInAccountCurrency(
+	sess *session.Session,
+	packageId int,
+	keyName string,
+	opts ...PriceOptions,
+) (datatypes.Product_Item_Price, string, error) {
+
+	price, err := GetPriceByKeyName(sess, packageId, keyName, opts...)
+	if err != nil {
+		return datatypes.Product_Item_Price{}, "", err
+	}
+
+	currency, err := GetAccountCurrency(sess)
+	if err != nil {
+		return datatypes.Product_Item_Price{}, "", err
+	}
+
+	rate, err := GetExchangeRate(sess, DefaultCurrency, currency)
+	if err != nil {
+		return datatypes.Product_Item_Price{}, "", err
+	}
+
+	price.HourlyRecurringFee = convertFee(price.HourlyRecurringFee, rate)
+	price.RecurringFee = convertFee(price.RecurringFee, rate)
+	price.SetupFee = convertFee(price.SetupFee, rate)
+	price.OneTimeFee = convertFee(price.OneTimeFee, rate)
+	price.LaborFee = convertFee(price.LaborFee, rate)
+
+	return price, currency, nil
+}
+
+func convertFee(fee *datatypes.Float64, rate float64) *datatypes.Float64 {
+	if fee == nil {
+		return nil
+	}
+
+	converted := datatypes.Float64(float64(*fee) * rate)
+	return &converted
+}
@@ -0,0 +1,186 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package product
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// PackageMask is the default object mask for product packages
+const PackageMask = "id,keyName,name,description,isActive,type[keyName]"
+
+// PackageQuery holds the criteria used to search product packages. Keyword
+// matches any part of the package name, and Type the key name of the package
+// type (e.g. "BARE_METAL_CPU"). Empty fields are ignored. Packages whose name
+// or description mark them as outlet packages are never returned.
+type PackageQuery struct {
+	Keyword    string
+	Type       string
+	ActiveOnly bool
+}
+
+// CategoryRequirement describes an item category of a package, and whether
+// an order for the package must include an item of that category
+type CategoryRequirement struct {
+	CategoryCode string
+	Name         string
+	Required     bool
+	Sort         int
+}
+
+// FindPackages returns the product packages matching query. An object mask
+// can be provided as an optional argument, and PackageMask is used otherwise.
+func FindPackages(sess *session.Session, query PackageQuery, mask ...string) ([]datatypes.Product_Package, error) {
+	objectMask := PackageMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	filters := filter.New()
+
+	if query.Keyword != "" {
+		filters = append(filters, filter.Path("name").Contains(query.Keyword))
+	}
+
+	if query.Type != "" {
+		filters = append(filters, filter.Path("type.keyName").Eq(query.Type))
+	}
+
+	if query.ActiveOnly {
+		filters = append(filters, filter.Path("isActive").Eq(1))
+	}
+
+	packages, err := services.GetProductPackageService(sess).
+		Mask(objectMask).
+		Filter(filters.Build()).
+		GetAllObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	return rejectOutletPackages(packages), nil
+}
+
+// GetPackageByKeyName returns the Product_Package with the provided key name
+// (e.g. "BARE_METAL_SERVER" or "PUBLIC_CLOUD_SERVER"). Packages are cached in
+// memory per session and object mask, see ClearCache, and in the catalog store
+// if one is set.
+func GetPackageByKeyName(sess *session.Session, keyName string, mask ...string) (datatypes.Product_Package, error) {
+	objectMask := PackageMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	key := cacheKey(sess, keyName, objectMask)
+
+	cache.Lock()
+	pkg, ok := cache.packages[key]
+	cache.Unlock()
+
+	if ok {
+		return pkg, nil
+	}
+
+	if loadStored("packages", key, &pkg) {
+		cache.Lock()
+		cache.packages[key] = pkg
+		cache.Unlock()
+
+		return pkg, nil
+	}
+
+	packages, err := services.GetProductPackageService(sess).
+		Mask(objectMask).
+		Filter(filter.Build(filter.Path("keyName").Eq(keyName))).
+		GetAllObjects()
+	if err != nil {
+		return datatypes.Product_Package{}, err
+	}
+
+	if len(packages) == 0 {
+		return datatypes.Product_Package{}, fmt.Errorf("No product package found with key name of %s", keyName)
+	}
+
+	cache.Lock()
+	cache.packages[key] = packages[0]
+	cache.Unlock()
+
+	saveStored("packages", key, packages[0])
+
+	return packages[0], nil
+}
+
+// GetPackageCategories returns the item categories of the package with the
+// provided id, in the order in which they appear in the order form, along with
+// whether each of them is required.
+func GetPackageCategories(sess *session.Session, packageId int) ([]CategoryRequirement, error) {
+	configuration, err := services.GetProductPackageService(sess).
+		Id(packageId).
+		Mask("isRequired,sort,itemCategory[categoryCode,name]").
+		GetConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make([]CategoryRequirement, 0, len(configuration))
+	for _, c := range configuration {
+		categories = append(categories, CategoryRequirement{
+			CategoryCode: sl.Grab(c, "ItemCategory.CategoryCode", "").(string),
+			Name:         sl.Grab(c, "ItemCategory.Name", "").(string),
+			Required:     sl.Get(c.IsRequired, 0).(int) == 1,
+			Sort:         sl.Get(c.Sort, 0).(int),
+		})
+	}
+
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].Sort < categories[j].Sort
+	})
+
+	return categories, nil
+}
+
+// MissingRequiredCategories returns the code of each required category of the
+// package with the provided id for which categoryCodes holds no entry. An
+// empty result means an order holding items of the provided categories is
+// complete.
+func MissingRequiredCategories(sess *session.Session, packageId int, categoryCodes []string) ([]string, error) {
+	categories, err := GetPackageCategories(sess, packageId)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := map[string]bool{}
+	for _, code := range categoryCodes {
+		selected[code] = true
+	}
+
+	missing := []string{}
+	for _, category := range categories {
+		if category.Required && !selected[category.CategoryCode] {
+			missing = append(missing, category.CategoryCode)
+		}
+	}
+
+	return missing, nil
+}
@@ -0,0 +1,68 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package product
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+)
+
+// PresetMask is the default object mask for presets, which includes the
+// prices implied by each preset
+const PresetMask = "id,keyName,name,description,isActive,totalMinimumHourlyFee,totalMinimumRecurringFee," +
+	"prices[id,hourlyRecurringFee,recurringFee,item[keyName,description,capacity,units],categories[categoryCode]]"
+
+// ListActivePresets returns the active presets of the package with the
+// provided key name, along with the prices each preset implies. An object mask
+// can be provided as an optional argument, and PresetMask is used otherwise.
+func ListActivePresets(sess *session.Session, packageKeyName string, mask ...string) ([]datatypes.Product_Package_Preset, error) {
+	objectMask := PresetMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	pkg, err := GetPackageByKeyName(sess, packageKeyName, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	return services.GetProductPackageService(sess).
+		Id(*pkg.Id).
+		Mask(objectMask).
+		GetActivePresets()
+}
+
+// GetPresetByKeyName returns the active preset with the provided key name in
+// the package with the provided key name.
+func GetPresetByKeyName(sess *session.Session, packageKeyName string, presetKeyName string, mask ...string) (datatypes.Product_Package_Preset, error) {
+	presets, err := ListActivePresets(sess, packageKeyName, mask...)
+	if err != nil {
+		return datatypes.Product_Package_Preset{}, err
+	}
+
+	for _, preset := range presets {
+		if preset.KeyName != nil && *preset.KeyName == presetKeyName {
+			return preset, nil
+		}
+	}
+
+	return datatypes.Product_Package_Preset{},
+		fmt.Errorf("No active preset found with key name of %s in package %s", presetKeyName, packageKeyName)
+}
@@ -0,0 +1,201 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package product
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/helpers/location"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// PriceItemMask is the object mask used to retrieve the product items cached
+// for price lookups
+const PriceItemMask = "id,keyName,capacity,description,units,itemCategory[categoryCode]," +
+	"prices[id,locationGroupId,hourlyRecurringFee,recurringFee,setupFee,oneTimeFee,laborFee,categories[categoryCode]]"
+
+// PriceOptions narrows down the price selected for an item.
+//
+// When Datacenter is set, a price specific to one of the datacenter's price
+// groups is preferred over the standard price. Category restricts the prices
+// to the ones in the given category code, and Hourly to the ones carrying an
+// hourly fee.
+type PriceOptions struct {
+	Datacenter string
+	Category   string
+	Hourly     bool
+}
+
+// GetPriceIDByKeyName returns the id of the price of the item with the provided
+// key name in the package with the provided id. The items of a package are
+// downloaded once per session, and kept in memory for subsequent lookups.
+func GetPriceIDByKeyName(sess *session.Session, packageId int, keyName string, opts ...PriceOptions) (int, error) {
+	price, err := GetPriceByKeyName(sess, packageId, keyName, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	return *price.Id, nil
+}
+
+// GetPriceByKeyName is like GetPriceIDByKeyName, but returns the full price,
+// including its fees.
+func GetPriceByKeyName(sess *session.Session, packageId int, keyName string, opts ...PriceOptions) (datatypes.Product_Item_Price, error) {
+	var options PriceOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	items, err := GetCachedPackageItems(sess, packageId)
+	if err != nil {
+		return datatypes.Product_Item_Price{}, err
+	}
+
+	var groupIds []int
+	if options.Datacenter != "" {
+		groupIds, err = getPriceGroupIds(sess, options.Datacenter)
+		if err != nil {
+			return datatypes.Product_Item_Price{}, err
+		}
+	}
+
+	for _, item := range items {
+		if item.KeyName == nil || *item.KeyName != keyName {
+			continue
+		}
+
+		if price, ok := SelectItemPrice(item, groupIds, options.Category, options.Hourly); ok {
+			return price, nil
+		}
+	}
+
+	return datatypes.Product_Item_Price{}, fmt.Errorf("No price found for item %s in package %d", keyName, packageId)
+}
+
+// SelectItemPrice returns the price of item matching category and hourly (see
+// PriceOptions). A price tied to one of the location groups in groupIds takes
+// precedence over the standard price, which is tied to no location group.
+func SelectItemPrice(item datatypes.Product_Item, groupIds []int, category string, hourly bool) (datatypes.Product_Item_Price, bool) {
+	var standard *datatypes.Product_Item_Price
+
+	for i, price := range item.Prices {
+		if hourly && price.HourlyRecurringFee == nil {
+			continue
+		}
+
+		if category != "" && !priceHasCategory(price, category) {
+			continue
+		}
+
+		if price.LocationGroupId == nil {
+			if standard == nil {
+				standard = &item.Prices[i]
+			}
+			continue
+		}
+
+		for _, groupId := range groupIds {
+			if *price.LocationGroupId == groupId {
+				return price, true
+			}
+		}
+	}
+
+	if standard != nil {
+		return *standard, true
+	}
+
+	return datatypes.Product_Item_Price{}, false
+}
+
+// GetCachedPackageItems returns the product items of the package with the
+// provided id, retrieved with PriceItemMask. Items are only downloaded the
+// first time a package is requested for a given session, unless the catalog
+// store holds them already.
+func GetCachedPackageItems(sess *session.Session, packageId int) ([]datatypes.Product_Item, error) {
+	key := cacheKey(sess, packageId)
+
+	cache.Lock()
+	items, ok := cache.items[key]
+	cache.Unlock()
+
+	if ok {
+		return items, nil
+	}
+
+	if loadStored("items", key, &items) {
+		cache.Lock()
+		cache.items[key] = items
+		cache.Unlock()
+
+		return items, nil
+	}
+
+	return RefreshCatalog(sess, packageId)
+}
+
+// getPriceGroupIds returns the ids of the price groups of the datacenter with
+// the provided name
+func getPriceGroupIds(sess *session.Session, datacenter string) ([]int, error) {
+	key := cacheKey(sess, datacenter)
+
+	cache.Lock()
+	groupIds, ok := cache.priceGroups[key]
+	cache.Unlock()
+
+	if ok {
+		return groupIds, nil
+	}
+
+	if loadStored("priceGroups", key, &groupIds) {
+		cache.Lock()
+		cache.priceGroups[key] = groupIds
+		cache.Unlock()
+
+		return groupIds, nil
+	}
+
+	dc, err := location.GetLocationByName(sess, datacenter, "id,priceGroups[id]")
+	if err != nil {
+		return nil, err
+	}
+
+	groupIds = []int{}
+	for _, group := range dc.PriceGroups {
+		groupIds = append(groupIds, sl.Get(group.Id, 0).(int))
+	}
+
+	cache.Lock()
+	cache.priceGroups[key] = groupIds
+	cache.Unlock()
+
+	saveStored("priceGroups", key, groupIds)
+
+	return groupIds, nil
+}
+
+func priceHasCategory(price datatypes.Product_Item_Price, category string) bool {
+	for _, c := range price.Categories {
+		if c.CategoryCode != nil && *c.CategoryCode == category {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,138 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package product
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// QuoteMask is the default object mask for quotes
+const QuoteMask = "id,name,quoteKey,status,createDate,modifyDate,expirationDate,publicNote"
+
+// SaveAsQuote saves orderData (a pointer to any kind of product order
+// container) as a quote instead of placing it. Set the QuoteName property of
+// the order to name the quote. The saved quote is returned.
+func SaveAsQuote(sess *session.Session, orderData interface{}) (datatypes.Billing_Order_Quote, error) {
+	receipt, err := services.GetProductOrderService(sess).PlaceOrder(orderData, sl.Bool(true))
+	if err != nil {
+		return datatypes.Billing_Order_Quote{}, err
+	}
+
+	if receipt.Quote == nil {
+		return datatypes.Billing_Order_Quote{}, fmt.Errorf("No quote returned in the order receipt")
+	}
+
+	return *receipt.Quote, nil
+}
+
+// ListQuotes returns the quotes of the account. When activeOnly is set, only
+// the quotes which have not expired, nor been ordered, are returned. An object
+// mask can be provided as an optional argument, and QuoteMask is used
+// otherwise.
+func ListQuotes(sess *session.Session, activeOnly bool, mask ...string) ([]datatypes.Billing_Order_Quote, error) {
+	objectMask := QuoteMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	service := services.GetAccountService(sess).Mask(objectMask)
+	if activeOnly {
+		return service.GetActiveQuotes()
+	}
+
+	return service.GetQuotes()
+}
+
+// GetQuote returns the quote with the provided id. An object mask can be
+// provided as an optional argument, and QuoteMask is used otherwise.
+func GetQuote(sess *session.Session, quoteId int, mask ...string) (datatypes.Billing_Order_Quote, error) {
+	objectMask := QuoteMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	return services.GetBillingOrderQuoteService(sess).
+		Id(quoteId).
+		Mask(objectMask).
+		GetObject()
+}
+
+// VerifyQuote recalculates the order container of the quote with the provided
+// id against current prices and verifies it. prepare is optional, and called
+// on the recalculated container before verification to fill in the values a
+// quote does not hold, such as hostnames and domains.
+func VerifyQuote(
+	sess *session.Session,
+	quoteId int,
+	prepare func(*datatypes.Container_Product_Order),
+) (datatypes.Container_Product_Order, error) {
+
+	container, err := recalculateQuote(sess, quoteId, prepare)
+	if err != nil {
+		return datatypes.Container_Product_Order{}, err
+	}
+
+	return services.GetBillingOrderQuoteService(sess).Id(quoteId).VerifyOrder(&container)
+}
+
+// PlaceOrderFromQuote recalculates and verifies the order container of the
+// quote with the provided id, as VerifyQuote does, then places the order.
+func PlaceOrderFromQuote(
+	sess *session.Session,
+	quoteId int,
+	prepare func(*datatypes.Container_Product_Order),
+) (datatypes.Container_Product_Order_Receipt, error) {
+
+	container, err := recalculateQuote(sess, quoteId, prepare)
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	service := services.GetBillingOrderQuoteService(sess).Id(quoteId)
+
+	verified, err := service.VerifyOrder(&container)
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, fmt.Errorf("Error verifying quote %d: %s", quoteId, err)
+	}
+
+	return service.PlaceOrder(&verified)
+}
+
+func recalculateQuote(
+	sess *session.Session,
+	quoteId int,
+	prepare func(*datatypes.Container_Product_Order),
+) (datatypes.Container_Product_Order, error) {
+
+	container, err := services.GetBillingOrderQuoteService(sess).
+		Id(quoteId).
+		GetRecalculatedOrderContainer(nil, sl.Bool(true))
+	if err != nil {
+		return datatypes.Container_Product_Order{}, err
+	}
+
+	if prepare != nil {
+		prepare(&container)
+	}
+
+	return container, nil
+}
@@ -0,0 +1,144 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package product
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Term lengths, in months, of the prices committing to a term. Prices with no
+// term commitment have a term length of NoTerm.
+const (
+	NoTerm     = 0
+	OneYear    = 12
+	ThreeYears = 36
+)
+
+const termPriceMask = "mask[id,keyName,prices[id,termLength,locationGroupId,hourlyRecurringFee,recurringFee,categories[categoryCode]]]"
+
+// termPrice is a Product_Item_Price carrying its term length, which the
+// generated datatype lacks
+type termPrice struct {
+	datatypes.Product_Item_Price
+
+	TermLength *int `json:"termLength,omitempty" xmlrpc:"termLength,omitempty"`
+}
+
+// termItem is a Product_Item whose prices carry their term length
+type termItem struct {
+	datatypes.Product_Item
+
+	Prices []termPrice `json:"prices,omitempty" xmlrpc:"prices,omitempty"`
+}
+
+// GetTermPriceByKeyName returns the price of the item with the provided key
+// name in the package with the provided id, committing to a term of the
+// provided number of months (e.g. OneYear or ThreeYears). Pass NoTerm to
+// select a price without term commitment. opts narrows down the price further,
+// as for GetPriceByKeyName.
+func GetTermPriceByKeyName(
+	sess *session.Session,
+	packageId int,
+	keyName string,
+	term int,
+	opts ...PriceOptions,
+) (datatypes.Product_Item_Price, error) {
+
+	var options PriceOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	var groupIds []int
+	if options.Datacenter != "" {
+		var err error
+		groupIds, err = getPriceGroupIds(sess, options.Datacenter)
+		if err != nil {
+			return datatypes.Product_Item_Price{}, err
+		}
+	}
+
+	// Product_Package::getItems() is called directly, as the generated
+	// datatypes cannot decode the term length of prices
+	var items []termItem
+	err := sess.DoRequest(
+		"SoftLayer_Product_Package",
+		"getItems",
+		nil,
+		&sl.Options{
+			Id:     &packageId,
+			Mask:   termPriceMask,
+			Filter: filter.Build(filter.Path("items.keyName").Eq(keyName)),
+		},
+		&items)
+	if err != nil {
+		return datatypes.Product_Item_Price{}, err
+	}
+
+	for _, item := range items {
+		if item.KeyName == nil || *item.KeyName != keyName {
+			continue
+		}
+
+		candidate := item.Product_Item
+		candidate.Prices = []datatypes.Product_Item_Price{}
+		for _, price := range item.Prices {
+			if sl.Get(price.TermLength, NoTerm).(int) == term {
+				candidate.Prices = append(candidate.Prices, price.Product_Item_Price)
+			}
+		}
+
+		if price, ok := SelectItemPrice(candidate, groupIds, options.Category, options.Hourly); ok {
+			return price, nil
+		}
+	}
+
+	return datatypes.Product_Item_Price{},
+		fmt.Errorf("No price found for item %s in package %d with a term of %d months", keyName, packageId, term)
+}
+
+// ValidateTermPrices checks that each of the prices with the provided ids
+// commits to a term of the provided number of months, and returns an error
+// naming the first price which does not.
+func ValidateTermPrices(sess *session.Session, priceIds []int, term int) error {
+	for _, priceId := range priceIds {
+		id := priceId
+
+		var price termPrice
+		err := sess.DoRequest(
+			"SoftLayer_Product_Item_Price",
+			"getObject",
+			nil,
+			&sl.Options{Id: &id, Mask: "mask[id,termLength,item[keyName]]"},
+			&price)
+		if err != nil {
+			return err
+		}
+
+		if length := sl.Get(price.TermLength, NoTerm).(int); length != term {
+			return fmt.Errorf("Price %d of item %s has a term of %d months, not %d",
+				priceId, sl.Grab(price.Product_Item_Price, "Item.KeyName", "").(string), length, term)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package product
+
+import (
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// CostBreakdown is a normalized view of the costs of a verified order.
+//
+// Recurring fees are monthly, unless Hourly is set, in which case they are
+// hourly. Setup fees are charged once.
+type CostBreakdown struct {
+	Hourly   bool
+	Currency string
+	Items    []ItemCost
+
+	RecurringBeforeTax float64
+	RecurringTax       float64
+	RecurringAfterTax  float64
+
+	SetupBeforeTax float64
+	SetupTax       float64
+	SetupAfterTax  float64
+
+	// ProratedInitialCharge is charged when the order is placed, for the
+	// remainder of the current billing cycle
+	ProratedInitialCharge float64
+	ProratedOrderTotal    float64
+
+	// OriginalCurrency and ExchangeRate are set when the amounts were
+	// converted from another currency, see Convert
+	OriginalCurrency string
+	ExchangeRate     float64
+}
+
+// ItemCost holds the fees of a single price of an order
+type ItemCost struct {
+	PriceId              int
+	KeyName              string
+	Description          string
+	Category             string
+	RecurringFee         float64
+	HourlyRecurringFee   float64
+	SetupFee             float64
+	OneTimeFee           float64
+	LaborFee             float64
+	ProratedRecurringFee float64
+}
+
+// VerifyOrder verifies orderData (a pointer to any kind of product order
+// container) without placing it, and returns the cost breakdown of the order.
+func VerifyOrder(sess *session.Session, orderData interface{}) (CostBreakdown, error) {
+	verified, err := services.GetProductOrderService(sess).VerifyOrder(orderData)
+	if err != nil {
+		return CostBreakdown{}, err
+	}
+
+	return NewCostBreakdown(verified), nil
+}
+
+// NewCostBreakdown builds the cost breakdown of an order container returned
+// by verifyOrder. The items and totals of nested order containers are included.
+func NewCostBreakdown(order datatypes.Container_Product_Order) CostBreakdown {
+	breakdown := CostBreakdown{
+		Hourly:   sl.Get(order.UseHourlyPricing, false).(bool),
+		Currency: sl.Get(order.CurrencyShortName, "").(string),
+		Items:    []ItemCost{},
+	}
+
+	addOrderCosts(&breakdown, order)
+
+	for _, container := range order.OrderContainers {
+		addOrderCosts(&breakdown, container)
+	}
+
+	return breakdown
+}
+
+func addOrderCosts(breakdown *CostBreakdown, order datatypes.Container_Product_Order) {
+	for _, price := range order.Prices {
+		breakdown.Items = append(breakdown.Items, ItemCost{
+			PriceId:              sl.Get(price.Id, 0).(int),
+			KeyName:              sl.Grab(price, "Item.KeyName", "").(string),
+			Description:          sl.Grab(price, "Item.Description", "").(string),
+			Category:             priceCategory(price),
+			RecurringFee:         floatValue(price.RecurringFee),
+			HourlyRecurringFee:   floatValue(price.HourlyRecurringFee),
+			SetupFee:             floatValue(price.SetupFee),
+			OneTimeFee:           floatValue(price.OneTimeFee),
+			LaborFee:             floatValue(price.LaborFee),
+			ProratedRecurringFee: floatValue(price.ProratedRecurringFee),
+		})
+	}
+
+	if breakdown.Hourly {
+		breakdown.RecurringBeforeTax += floatValue(order.PreTaxRecurringHourly)
+	} else {
+		breakdown.RecurringBeforeTax += floatValue(order.PreTaxRecurringMonthly)
+	}
+
+	breakdown.RecurringTax += floatValue(order.TotalRecurringTax)
+	breakdown.RecurringAfterTax += floatValue(order.PostTaxRecurring)
+	breakdown.SetupBeforeTax += floatValue(order.PreTaxSetup)
+	breakdown.SetupTax += floatValue(order.TotalSetupTax)
+	breakdown.SetupAfterTax += floatValue(order.PostTaxSetup)
+	breakdown.ProratedInitialCharge += floatValue(order.ProratedInitialCharge)
+	breakdown.ProratedOrderTotal += floatValue(order.ProratedOrderTotal)
+}
+
+// priceCategory returns the code of the first category of a price, falling
+// back to the category of its item
+func priceCategory(price datatypes.Product_Item_Price) string {
+	for _, category := range price.Categories {
+		if category.CategoryCode != nil {
+			return *category.CategoryCode
+		}
+	}
+
+	return sl.Grab(price, "Item.ItemCategory.CategoryCode", "").(string)
+}
+
+func floatValue(f *datatypes.Float64) float64 {
+	if f == nil {
+		return 0
+	}
+
+	return float64(*f)
+}
@@ -0,0 +1,167 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package security
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DefaultCertificateMask is the default object mask for SSL certificates
+const DefaultCertificateMask = "id,commonName,organizationName,keySize,notes," +
+	"validityBegin,validityEnd,validityDays,associatedServiceCount"
+
+// Certificate holds the PEM encoded content of an SSL certificate to upload.
+// IntermediateCertificate, CertificateSigningRequest and Notes are optional.
+type Certificate struct {
+	Certificate               string
+	PrivateKey                string
+	IntermediateCertificate   string
+	CertificateSigningRequest string
+	Notes                     string
+}
+
+// ExpiringCertificate is a certificate expiring soon, or expired. Remaining is
+// the time left until it expires, and is negative when it has expired. InUse
+// reports whether services, such as load balancers, use the certificate.
+type ExpiringCertificate struct {
+	Id          int
+	CommonName  string
+	ValidityEnd time.Time
+	Remaining   time.Duration
+	InUse       bool
+}
+
+// UploadCertificate uploads an SSL certificate to the account, and returns it
+func UploadCertificate(sess *session.Session, certificate Certificate) (datatypes.Security_Certificate, error) {
+	if certificate.Certificate == "" || certificate.PrivateKey == "" {
+		return datatypes.Security_Certificate{}, fmt.Errorf("A certificate and private key are required")
+	}
+
+	created, err := services.GetSecurityCertificateService(sess).CreateObject(certificateTemplate(certificate))
+	if err != nil {
+		return datatypes.Security_Certificate{}, fmt.Errorf("Error uploading certificate: %s", err)
+	}
+
+	return created, nil
+}
+
+// ListCertificates returns the SSL certificates of the account. An object mask
+// can be provided as an optional argument, and DefaultCertificateMask is used
+// otherwise.
+func ListCertificates(sess *session.Session, mask ...string) ([]datatypes.Security_Certificate, error) {
+	objectMask := DefaultCertificateMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	return services.GetAccountService(sess).Mask(objectMask).GetSecurityCertificates()
+}
+
+// UpdateCertificate replaces the content of the SSL certificate with the
+// provided id, e.g. with a renewed certificate. Empty fields of certificate
+// are left unchanged.
+func UpdateCertificate(sess *session.Session, certificateId int, certificate Certificate) error {
+	_, err := services.GetSecurityCertificateService(sess).Id(certificateId).EditObject(certificateTemplate(certificate))
+	if err != nil {
+		return fmt.Errorf("Error updating certificate %d: %s", certificateId, err)
+	}
+
+	return nil
+}
+
+// DeleteCertificate deletes the SSL certificate with the provided id. An
+// error is returned, without deleting it, when services use the certificate.
+func DeleteCertificate(sess *session.Session, certificateId int) error {
+	service := services.GetSecurityCertificateService(sess).Id(certificateId)
+
+	count, err := service.GetAssociatedServiceCount()
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return fmt.Errorf("Certificate %d is used by %d services", certificateId, count)
+	}
+
+	_, err = service.DeleteObject()
+	return err
+}
+
+// FindExpiring returns the SSL certificates of the account expiring within
+// the provided duration, the expired ones included, soonest first
+func FindExpiring(sess *session.Session, within time.Duration) ([]ExpiringCertificate, error) {
+	certificates, err := ListCertificates(sess, "id,commonName,validityEnd,associatedServiceCount")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	expiring := []ExpiringCertificate{}
+	for _, certificate := range certificates {
+		if certificate.ValidityEnd == nil || certificate.ValidityEnd.After(now.Add(within)) {
+			continue
+		}
+
+		expiring = append(expiring, ExpiringCertificate{
+			Id:          sl.Get(certificate.Id, 0).(int),
+			CommonName:  sl.Get(certificate.CommonName, "").(string),
+			ValidityEnd: certificate.ValidityEnd.Time,
+			Remaining:   certificate.ValidityEnd.Sub(now),
+			InUse:       sl.Get(certificate.AssociatedServiceCount, 0).(int) > 0,
+		})
+	}
+
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].ValidityEnd.Before(expiring[j].ValidityEnd)
+	})
+
+	return expiring, nil
+}
+
+func certificateTemplate(certificate Certificate) *datatypes.Security_Certificate {
+	template := &datatypes.Security_Certificate{}
+
+	if certificate.Certificate != "" {
+		template.Certificate = sl.String(certificate.Certificate)
+	}
+
+	if certificate.PrivateKey != "" {
+		template.PrivateKey = sl.String(certificate.PrivateKey)
+	}
+
+	if certificate.IntermediateCertificate != "" {
+		template.IntermediateCertificate = sl.String(certificate.IntermediateCertificate)
+	}
+
+	if certificate.CertificateSigningRequest != "" {
+		template.CertificateSigningRequest = sl.String(certificate.CertificateSigningRequest)
+	}
+
+	if certificate.Notes != "" {
+		template.Notes = sl.String(certificate.Notes)
+	}
+
+	return template
+}
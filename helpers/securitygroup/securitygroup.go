@@ -0,0 +1,249 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package securitygroup
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Rule directions
+const (
+	Ingress = "ingress"
+	Egress  = "egress"
+)
+
+// Rule describes a security group rule. Build one with AllowIngress or
+// AllowEgress, and narrow it down with its methods, e.g.
+//
+//	securitygroup.AllowIngress("tcp", 22, 22).FromIP("10.0.0.0/8")
+//
+// An empty Protocol matches all protocols, and zero ports match all ports.
+type Rule struct {
+	Direction     string
+	Ethertype     string
+	Protocol      string
+	PortMin       int
+	PortMax       int
+	RemoteIp      string
+	RemoteGroupId int
+}
+
+// AllowIngress returns an IPv4 rule allowing inbound traffic of the provided
+// protocol on the provided range of ports, from anywhere
+func AllowIngress(protocol string, portMin int, portMax int) Rule {
+	return Rule{Direction: Ingress, Ethertype: "IPv4", Protocol: protocol, PortMin: portMin, PortMax: portMax}
+}
+
+// AllowEgress returns an IPv4 rule allowing outbound traffic of the provided
+// protocol on the provided range of ports, to anywhere
+func AllowEgress(protocol string, portMin int, portMax int) Rule {
+	return Rule{Direction: Egress, Ethertype: "IPv4", Protocol: protocol, PortMin: portMin, PortMax: portMax}
+}
+
+// FromIP restricts the rule to the remote address or CIDR block provided
+func (r Rule) FromIP(remoteIp string) Rule {
+	r.RemoteIp = remoteIp
+	r.RemoteGroupId = 0
+	return r
+}
+
+// FromGroup restricts the rule to the members of the security group with the
+// provided id
+func (r Rule) FromGroup(remoteGroupId int) Rule {
+	r.RemoteGroupId = remoteGroupId
+	r.RemoteIp = ""
+	return r
+}
+
+// IPv6 makes the rule apply to IPv6 traffic instead of IPv4 traffic
+func (r Rule) IPv6() Rule {
+	r.Ethertype = "IPv6"
+	return r
+}
+
+// template returns the rule as a Network_SecurityGroup_Rule template
+func (r Rule) template() datatypes.Network_SecurityGroup_Rule {
+	rule := datatypes.Network_SecurityGroup_Rule{
+		Direction: sl.String(r.Direction),
+		Ethertype: sl.String(r.Ethertype),
+	}
+
+	if r.Protocol != "" {
+		rule.Protocol = sl.String(r.Protocol)
+	}
+
+	if r.PortMin != 0 || r.PortMax != 0 {
+		rule.PortRangeMin = sl.Int(r.PortMin)
+		rule.PortRangeMax = sl.Int(r.PortMax)
+	}
+
+	if r.RemoteIp != "" {
+		rule.RemoteIp = sl.String(r.RemoteIp)
+	}
+
+	if r.RemoteGroupId != 0 {
+		rule.RemoteGroupId = sl.Int(r.RemoteGroupId)
+	}
+
+	return rule
+}
+
+// ruleOf returns the Rule matching an existing security group rule
+func ruleOf(rule datatypes.Network_SecurityGroup_Rule) Rule {
+	return Rule{
+		Direction:     sl.Get(rule.Direction, "").(string),
+		Ethertype:     sl.Get(rule.Ethertype, "IPv4").(string),
+		Protocol:      sl.Get(rule.Protocol, "").(string),
+		PortMin:       sl.Get(rule.PortRangeMin, 0).(int),
+		PortMax:       sl.Get(rule.PortRangeMax, 0).(int),
+		RemoteIp:      sl.Get(rule.RemoteIp, "").(string),
+		RemoteGroupId: sl.Get(rule.RemoteGroupId, 0).(int),
+	}
+}
+
+// CreateGroup creates a security group with the provided name and description,
+// and returns it
+func CreateGroup(sess *session.Session, name string, description string) (datatypes.Network_SecurityGroup, error) {
+	groups, err := services.GetNetworkSecurityGroupService(sess).CreateObjects([]datatypes.Network_SecurityGroup{
+		{
+			Name:        sl.String(name),
+			Description: sl.String(description),
+		},
+	})
+	if err != nil {
+		return datatypes.Network_SecurityGroup{}, err
+	}
+
+	if len(groups) == 0 {
+		return datatypes.Network_SecurityGroup{}, fmt.Errorf("No security group returned when creating %s", name)
+	}
+
+	return groups[0], nil
+}
+
+// EnsureRules makes the rules of the security group with the provided id
+// match desired: the missing rules are added, and the rules which are not
+// desired are removed. Nothing is changed when the rules already match. The
+// number of rules added and removed is returned.
+func EnsureRules(sess *session.Session, groupId int, desired []Rule) (added int, removed int, err error) {
+	service := services.GetNetworkSecurityGroupService(sess).Id(groupId)
+
+	current, err := service.
+		Mask("id,direction,ethertype,protocol,portRangeMin,portRangeMax,remoteIp,remoteGroupId").
+		GetRules()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	wanted := map[Rule]bool{}
+	for _, rule := range desired {
+		wanted[rule] = true
+	}
+
+	existing := map[Rule]bool{}
+	removeIds := []int{}
+	for _, rule := range current {
+		r := ruleOf(rule)
+		if wanted[r] && !existing[r] {
+			existing[r] = true
+			continue
+		}
+
+		// Duplicates of a desired rule are removed as well
+		removeIds = append(removeIds, *rule.Id)
+	}
+
+	templates := []datatypes.Network_SecurityGroup_Rule{}
+	for _, rule := range desired {
+		if !existing[rule] {
+			existing[rule] = true
+			templates = append(templates, rule.template())
+		}
+	}
+
+	// The missing rules are added first, so that a failure leaves the group
+	// with its current rules rather than stripped of them
+	if len(templates) > 0 {
+		if _, err := service.AddRules(templates); err != nil {
+			return 0, 0, fmt.Errorf("Error adding rules to security group %d: %s", groupId, err)
+		}
+	}
+
+	if len(removeIds) > 0 {
+		if _, err := service.RemoveRules(removeIds); err != nil {
+			return len(templates), 0, fmt.Errorf("Error removing rules from security group %d: %s", groupId, err)
+		}
+	}
+
+	return len(templates), len(removeIds), nil
+}
+
+// AttachToGuest attaches the security group with the provided id to the
+// public or private primary network component of the virtual guest with the
+// provided id
+func AttachToGuest(sess *session.Session, groupId int, guestId int, public bool) error {
+	componentId, err := getGuestNetworkComponentId(sess, guestId, public)
+	if err != nil {
+		return err
+	}
+
+	_, err = services.GetNetworkSecurityGroupService(sess).
+		Id(groupId).
+		AttachNetworkComponents([]int{componentId})
+	return err
+}
+
+// DetachFromGuest detaches the security group with the provided id from the
+// public or private primary network component of the virtual guest with the
+// provided id
+func DetachFromGuest(sess *session.Session, groupId int, guestId int, public bool) error {
+	componentId, err := getGuestNetworkComponentId(sess, guestId, public)
+	if err != nil {
+		return err
+	}
+
+	_, err = services.GetNetworkSecurityGroupService(sess).
+		Id(groupId).
+		DetachNetworkComponents([]int{componentId})
+	return err
+}
+
+func getGuestNetworkComponentId(sess *session.Session, guestId int, public bool) (int, error) {
+	service := services.GetVirtualGuestService(sess).Id(guestId).Mask("id")
+
+	var component datatypes.Virtual_Guest_Network_Component
+	var err error
+	if public {
+		component, err = service.GetPrimaryNetworkComponent()
+	} else {
+		component, err = service.GetPrimaryBackendNetworkComponent()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if component.Id == nil {
+		return 0, fmt.Errorf("No primary network component found for virtual guest %d", guestId)
+	}
+
+	return *component.Id, nil
+}
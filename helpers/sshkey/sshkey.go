@@ -0,0 +1,118 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sshkey
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Fingerprint returns the MD5 fingerprint of an OpenSSH public key (e.g.
+// "ssh-rsa AAAAB3Nza... user@host"), in the colon separated hexadecimal form
+// SoftLayer reports the fingerprint of SSH keys in
+func Fingerprint(publicKey string) (string, error) {
+	fields := strings.Fields(publicKey)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("Invalid public key: expected a key type and a base64 encoded key")
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return "", fmt.Errorf("Invalid public key: %s", err)
+	}
+
+	sum := md5.Sum(blob)
+
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02x", b)
+	}
+
+	return strings.Join(hex, ":"), nil
+}
+
+// FindSSHKey returns the SSH key of the account with the fingerprint of the
+// provided public key, or an error when the account holds no such key
+func FindSSHKey(sess *session.Session, publicKey string) (datatypes.Security_Ssh_Key, error) {
+	fingerprint, err := Fingerprint(publicKey)
+	if err != nil {
+		return datatypes.Security_Ssh_Key{}, err
+	}
+
+	key, found, err := findByFingerprint(sess, fingerprint)
+	if err != nil {
+		return datatypes.Security_Ssh_Key{}, err
+	}
+
+	if !found {
+		return datatypes.Security_Ssh_Key{}, fmt.Errorf("No SSH key found with fingerprint %s", fingerprint)
+	}
+
+	return key, nil
+}
+
+// EnsureSSHKey returns the id of the SSH key of the account matching the
+// provided public key, creating the key with the provided label when the
+// account holds none. Keys are matched by fingerprint, so an existing key is
+// reused whatever its label.
+func EnsureSSHKey(sess *session.Session, label string, publicKey string) (int, error) {
+	fingerprint, err := Fingerprint(publicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	existing, found, err := findByFingerprint(sess, fingerprint)
+	if err != nil {
+		return 0, err
+	}
+
+	if found {
+		return *existing.Id, nil
+	}
+
+	key, err := services.GetSecuritySshKeyService(sess).CreateObject(&datatypes.Security_Ssh_Key{
+		Label: sl.String(label),
+		Key:   sl.String(strings.TrimSpace(publicKey)),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Error creating SSH key %s: %s", label, err)
+	}
+
+	return *key.Id, nil
+}
+
+func findByFingerprint(sess *session.Session, fingerprint string) (datatypes.Security_Ssh_Key, bool, error) {
+	keys, err := services.GetAccountService(sess).Mask("id,label,fingerprint,notes,createDate").GetSshKeys()
+	if err != nil {
+		return datatypes.Security_Ssh_Key{}, false, err
+	}
+
+	for _, key := range keys {
+		if sl.Get(key.Fingerprint, "").(string) == fingerprint {
+			return key, true, nil
+		}
+	}
+
+	return datatypes.Security_Ssh_Key{}, false, nil
+}
@@ -0,0 +1,44 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sshkey
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	const key = "AAAAC3NzaC1lZDI1NTE5AAAAIP7awtVTGvcDkOGMcEmfLQ/bJiFFPK+0IhLkKa1cdn8O"
+	const fingerprint = "17:c5:2b:72:14:88:97:e6:d4:99:d6:98:6b:64:22:5a"
+
+	tests := []struct {
+		publicKey string
+		expected  string
+		valid     bool
+	}{
+		{"ssh-ed25519 " + key + " user@host", fingerprint, true},
+		{"ssh-ed25519 " + key, fingerprint, true},
+		{"  ssh-ed25519\t" + key + "  user@host\n", fingerprint, true},
+		{key, "", false},
+		{"ssh-ed25519 not-base64!", "", false},
+		{"", "", false},
+	}
+
+	for _, test := range tests {
+		fp, err := Fingerprint(test.publicKey)
+		if (err == nil) != test.valid || fp != test.expected {
+			t.Errorf("Expected %q (valid: %t) for %q, got %q (error: %v)", test.expected, test.valid, test.publicKey, fp, err)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Kinds of hosts a volume can be authorized for
+const (
+	HostVirtualGuest = "virtual_guest"
+	HostHardware     = "hardware"
+	HostIpAddress    = "ip_address"
+)
+
+const allowedHostMask = "allowedHost[name,credential[username,password]]"
+
+// Hosts identifies hosts by kind: virtual guests and hardware by id, and IP
+// addresses of the account by address
+type Hosts struct {
+	VirtualGuestIds []int
+	HardwareIds     []int
+	IpAddresses     []string
+}
+
+// HostCredential is the authorization of a host on a volume. Name is the
+// iSCSI qualified name (IQN) of the host, and Username and Password are the
+// CHAP credentials it logs in to the volume with. IpAddress is the private
+// address of the host.
+type HostCredential struct {
+	Type      string
+	HostId    int
+	IpAddress string
+	Name      string
+	Username  string
+	Password  string
+}
+
+// AuthorizedSubnet is a subnet authorized on a volume, whose hosts can all
+// reach the volume
+type AuthorizedSubnet struct {
+	Id                int
+	NetworkIdentifier string
+	Cidr              int
+	Type              string
+}
+
+// AccessReport lists who can reach a volume, for audits. TargetAddress is the
+// address hosts connect to the volume at, and MountPath the NFS mount path of
+// file volumes. HostLimit is the number of hosts which can be authorized on the
+// volume.
+type AccessReport struct {
+	VolumeId      int
+	Username      string
+	Datacenter    string
+	TargetAddress string
+	MountPath     string
+	Hosts         []HostCredential
+	Subnets       []AuthorizedSubnet
+	HostLimit     int
+}
+
+// ListAuthorizedHosts returns the hosts authorized on the volume with the
+// provided id, along with their credentials
+func ListAuthorizedHosts(sess *session.Session, volumeId int) ([]HostCredential, error) {
+	service := services.GetNetworkStorageService(sess).Id(volumeId)
+
+	guests, err := service.Mask("id,primaryBackendIpAddress," + allowedHostMask).GetAllowedVirtualGuests()
+	if err != nil {
+		return nil, err
+	}
+
+	hardware, err := service.Mask("id,primaryBackendIpAddress," + allowedHostMask).GetAllowedHardware()
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := service.Mask("id,ipAddress," + allowedHostMask).GetAllowedIpAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := []HostCredential{}
+	for _, guest := range guests {
+		credentials = append(credentials, hostCredential(
+			HostVirtualGuest, *guest.Id, sl.Get(guest.PrimaryBackendIpAddress, "").(string), guest.AllowedHost))
+	}
+	for _, h := range hardware {
+		credentials = append(credentials, hostCredential(
+			HostHardware, *h.Id, sl.Get(h.PrimaryBackendIpAddress, "").(string), h.AllowedHost))
+	}
+	for _, ip := range ips {
+		credentials = append(credentials, hostCredential(
+			HostIpAddress, *ip.Id, sl.Get(ip.IpAddress, "").(string), ip.AllowedHost))
+	}
+
+	return credentials, nil
+}
+
+// GetAccessReport returns the access report of the volume with the provided
+// id: the hosts authorized on it, along with their addresses, IQNs and CHAP
+// credentials, and the subnets authorized on it
+func GetAccessReport(sess *session.Session, volumeId int) (AccessReport, error) {
+	service := services.GetNetworkStorageService(sess).Id(volumeId)
+
+	volume, err := service.Mask(VolumeMask).GetObject()
+	if err != nil {
+		return AccessReport{}, err
+	}
+
+	hosts, err := ListAuthorizedHosts(sess, volumeId)
+	if err != nil {
+		return AccessReport{}, err
+	}
+
+	subnets, err := service.Mask("id,networkIdentifier,cidr,subnetType").GetAllowedSubnets()
+	if err != nil {
+		return AccessReport{}, err
+	}
+
+	limit, err := service.GetAllowedHostsLimit()
+	if err != nil {
+		return AccessReport{}, err
+	}
+
+	report := AccessReport{
+		VolumeId:      volumeId,
+		Username:      sl.Get(volume.Username, "").(string),
+		Datacenter:    sl.Grab(volume, "ServiceResource.Datacenter.Name", "").(string),
+		TargetAddress: sl.Get(volume.ServiceResourceBackendIpAddress, "").(string),
+		MountPath:     MountPath(volume),
+		Hosts:         hosts,
+		Subnets:       make([]AuthorizedSubnet, 0, len(subnets)),
+		HostLimit:     limit,
+	}
+
+	for _, subnet := range subnets {
+		report.Subnets = append(report.Subnets, AuthorizedSubnet{
+			Id:                sl.Get(subnet.Id, 0).(int),
+			NetworkIdentifier: sl.Get(subnet.NetworkIdentifier, "").(string),
+			Cidr:              sl.Get(subnet.Cidr, 0).(int),
+			Type:              sl.Get(subnet.SubnetType, "").(string),
+		})
+	}
+
+	return report, nil
+}
+
+// AuthorizeHosts authorizes the provided hosts on the volume with the provided
+// id, skipping the hosts already authorized, and returns the credentials of
+// all the hosts authorized on the volume
+func AuthorizeHosts(sess *session.Session, volumeId int, hosts Hosts) ([]HostCredential, error) {
+	missing, err := filterHosts(sess, volumeId, hosts, false)
+	if err != nil {
+		return nil, err
+	}
+
+	service := services.GetNetworkStorageService(sess).Id(volumeId)
+
+	if len(missing.VirtualGuestIds) > 0 {
+		if _, err := service.AllowAccessFromVirtualGuestList(guestTemplates(missing.VirtualGuestIds)); err != nil {
+			return nil, fmt.Errorf("Error authorizing virtual guests on volume %d: %s", volumeId, err)
+		}
+	}
+
+	if len(missing.HardwareIds) > 0 {
+		if _, err := service.AllowAccessFromHardwareList(hardwareTemplates(missing.HardwareIds)); err != nil {
+			return nil, fmt.Errorf("Error authorizing hardware on volume %d: %s", volumeId, err)
+		}
+	}
+
+	if len(missing.IpAddresses) > 0 {
+		ips, err := ipAddressTemplates(sess, missing.IpAddresses)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := service.AllowAccessFromIpAddressList(ips); err != nil {
+			return nil, fmt.Errorf("Error authorizing IP addresses on volume %d: %s", volumeId, err)
+		}
+	}
+
+	return ListAuthorizedHosts(sess, volumeId)
+}
+
+// DeauthorizeHosts removes the authorization of the provided hosts on the
+// volume with the provided id, skipping the hosts which are not authorized
+func DeauthorizeHosts(sess *session.Session, volumeId int, hosts Hosts) error {
+	present, err := filterHosts(sess, volumeId, hosts, true)
+	if err != nil {
+		return err
+	}
+
+	service := services.GetNetworkStorageService(sess).Id(volumeId)
+
+	if len(present.VirtualGuestIds) > 0 {
+		if _, err := service.RemoveAccessFromVirtualGuestList(guestTemplates(present.VirtualGuestIds)); err != nil {
+			return fmt.Errorf("Error deauthorizing virtual guests on volume %d: %s", volumeId, err)
+		}
+	}
+
+	if len(present.HardwareIds) > 0 {
+		if _, err := service.RemoveAccessFromHardwareList(hardwareTemplates(present.HardwareIds)); err != nil {
+			return fmt.Errorf("Error deauthorizing hardware on volume %d: %s", volumeId, err)
+		}
+	}
+
+	if len(present.IpAddresses) > 0 {
+		ips, err := ipAddressTemplates(sess, present.IpAddresses)
+		if err != nil {
+			return err
+		}
+
+		if _, err := service.RemoveAccessFromIpAddressList(ips); err != nil {
+			return fmt.Errorf("Error deauthorizing IP addresses on volume %d: %s", volumeId, err)
+		}
+	}
+
+	return nil
+}
+
+// filterHosts returns the hosts which are authorized on the volume, when
+// authorized is set, or the ones which are not otherwise
+func filterHosts(sess *session.Session, volumeId int, hosts Hosts, authorized bool) (Hosts, error) {
+	current, err := ListAuthorizedHosts(sess, volumeId)
+	if err != nil {
+		return Hosts{}, err
+	}
+
+	ids := map[string]map[int]bool{HostVirtualGuest: {}, HostHardware: {}}
+	addresses := map[string]bool{}
+	for _, host := range current {
+		if host.Type == HostIpAddress {
+			addresses[host.IpAddress] = true
+		} else {
+			ids[host.Type][host.HostId] = true
+		}
+	}
+
+	result := Hosts{VirtualGuestIds: []int{}, HardwareIds: []int{}, IpAddresses: []string{}}
+	for _, id := range hosts.VirtualGuestIds {
+		if ids[HostVirtualGuest][id] == authorized {
+			result.VirtualGuestIds = append(result.VirtualGuestIds, id)
+		}
+	}
+	for _, id := range hosts.HardwareIds {
+		if ids[HostHardware][id] == authorized {
+			result.HardwareIds = append(result.HardwareIds, id)
+		}
+	}
+	for _, address := range hosts.IpAddresses {
+		if addresses[address] == authorized {
+			result.IpAddresses = append(result.IpAddresses, address)
+		}
+	}
+
+	return result, nil
+}
+
+func hostCredential(hostType string, id int, ipAddress string, host *datatypes.Network_Storage_Allowed_Host) HostCredential {
+	credential := HostCredential{Type: hostType, HostId: id, IpAddress: ipAddress}
+	if host != nil {
+		credential.Name = sl.Get(host.Name, "").(string)
+		credential.Username = sl.Grab(*host, "Credential.Username", "").(string)
+		credential.Password = sl.Grab(*host, "Credential.Password", "").(string)
+	}
+
+	return credential
+}
+
+func guestTemplates(ids []int) []datatypes.Virtual_Guest {
+	templates := make([]datatypes.Virtual_Guest, 0, len(ids))
+	for _, id := range ids {
+		templates = append(templates, datatypes.Virtual_Guest{Id: sl.Int(id)})
+	}
+
+	return templates
+}
+
+func hardwareTemplates(ids []int) []datatypes.Hardware {
+	templates := make([]datatypes.Hardware, 0, len(ids))
+	for _, id := range ids {
+		templates = append(templates, datatypes.Hardware{Id: sl.Int(id)})
+	}
+
+	return templates
+}
+
+// ipAddressTemplates resolves the ids of IP addresses of the account
+func ipAddressTemplates(sess *session.Session, addresses []string) ([]datatypes.Network_Subnet_IpAddress, error) {
+	service := services.GetNetworkSubnetIpAddressService(sess).Mask("id")
+
+	templates := make([]datatypes.Network_Subnet_IpAddress, 0, len(addresses))
+	for _, address := range addresses {
+		ip, err := service.GetByIpAddress(sl.String(address))
+		if err != nil {
+			return nil, err
+		}
+
+		if ip.Id == nil {
+			return nil, fmt.Errorf("IP address %s was not found on the account", address)
+		}
+
+		templates = append(templates, datatypes.Network_Subnet_IpAddress{Id: ip.Id})
+	}
+
+	return templates, nil
+}
@@ -0,0 +1,255 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// CancellationReport is the result of the pre-flight checks run before
+// canceling a volume.
+//
+// Replicas, Duplicates and ActiveTransactions prevent the cancellation.
+// AuthorizedHosts and AuthorizedSubnets prevent it too, unless the access to
+// the volume is cleared first.
+type CancellationReport struct {
+	VolumeId           int
+	BillingItemId      int
+	PendingCancel      bool
+	Replicas           []string
+	Duplicates         []string
+	ActiveTransactions []string
+	AuthorizedHosts    []string
+	AuthorizedSubnets  []string
+}
+
+// Blocked reports whether any of the pre-flight checks, apart from the
+// authorizations on the volume, prevents the cancellation of the volume
+func (r CancellationReport) Blocked() bool {
+	return r.BillingItemId == 0 || r.PendingCancel ||
+		len(r.Replicas) > 0 || len(r.Duplicates) > 0 || len(r.ActiveTransactions) > 0
+}
+
+// Authorized reports whether hosts or subnets are authorized on the volume
+func (r CancellationReport) Authorized() bool {
+	return len(r.AuthorizedHosts) > 0 || len(r.AuthorizedSubnets) > 0
+}
+
+// Reasons returns a description of each check preventing the cancellation,
+// the authorizations on the volume included
+func (r CancellationReport) Reasons() []string {
+	reasons := []string{}
+
+	if r.BillingItemId == 0 {
+		reasons = append(reasons, "no billing item found")
+	}
+
+	if r.PendingCancel {
+		reasons = append(reasons, "cancellation already pending")
+	}
+
+	if len(r.Replicas) > 0 {
+		reasons = append(reasons, fmt.Sprintf("replicated to %s", strings.Join(r.Replicas, ", ")))
+	}
+
+	if len(r.Duplicates) > 0 {
+		reasons = append(reasons, fmt.Sprintf("dependent duplicates %s", strings.Join(r.Duplicates, ", ")))
+	}
+
+	if len(r.ActiveTransactions) > 0 {
+		reasons = append(reasons, fmt.Sprintf("active transactions %s", strings.Join(r.ActiveTransactions, ", ")))
+	}
+
+	if len(r.AuthorizedHosts) > 0 {
+		reasons = append(reasons, fmt.Sprintf("authorized hosts %s", strings.Join(r.AuthorizedHosts, ", ")))
+	}
+
+	if len(r.AuthorizedSubnets) > 0 {
+		reasons = append(reasons, fmt.Sprintf("authorized subnets %s", strings.Join(r.AuthorizedSubnets, ", ")))
+	}
+
+	return reasons
+}
+
+// dependentVolume is a Network_Storage carrying the dependent duplicates of
+// the volume, and whether it is itself a dependent duplicate, which the
+// generated datatype lacks
+type dependentVolume struct {
+	DependentDuplicate  *string                     `json:"dependentDuplicate,omitempty" xmlrpc:"dependentDuplicate,omitempty"`
+	DependentDuplicates []datatypes.Network_Storage `json:"dependentDuplicates,omitempty" xmlrpc:"dependentDuplicates,omitempty"`
+}
+
+// CheckVolumeCancellation runs the pre-flight checks for the cancellation of
+// the volume with the provided id, without canceling it
+func CheckVolumeCancellation(sess *session.Session, volumeId int) (CancellationReport, error) {
+	service := services.GetNetworkStorageService(sess).Id(volumeId)
+
+	volume, err := service.
+		Mask("id,replicationPartners[id,username],activeTransactions[id,transactionStatus[name]]," +
+			"billingItem[id,pendingCancellationFlag]").
+		GetObject()
+	if err != nil {
+		return CancellationReport{}, err
+	}
+
+	// The dependent duplicates are retrieved through a direct call, as they
+	// are missing from the generated datatypes
+	var dependent dependentVolume
+	err = sess.DoRequest("SoftLayer_Network_Storage", "getObject", nil,
+		&sl.Options{Id: &volumeId, Mask: "mask[dependentDuplicates[id,username]]"}, &dependent)
+	if err != nil {
+		return CancellationReport{}, err
+	}
+
+	hosts, err := ListAuthorizedHosts(sess, volumeId)
+	if err != nil {
+		return CancellationReport{}, err
+	}
+
+	subnets, err := service.Mask("id,networkIdentifier,cidr").GetAllowedSubnets()
+	if err != nil {
+		return CancellationReport{}, err
+	}
+
+	report := CancellationReport{
+		VolumeId:           volumeId,
+		BillingItemId:      sl.Grab(volume, "BillingItem.Id", 0).(int),
+		PendingCancel:      sl.Grab(volume, "BillingItem.PendingCancellationFlag", false).(bool),
+		Replicas:           []string{},
+		Duplicates:         []string{},
+		ActiveTransactions: []string{},
+		AuthorizedHosts:    []string{},
+		AuthorizedSubnets:  []string{},
+	}
+
+	for _, replica := range volume.ReplicationPartners {
+		report.Replicas = append(report.Replicas, sl.Get(replica.Username, "").(string))
+	}
+
+	for _, duplicate := range dependent.DependentDuplicates {
+		report.Duplicates = append(report.Duplicates, sl.Get(duplicate.Username, "").(string))
+	}
+
+	for _, transaction := range volume.ActiveTransactions {
+		report.ActiveTransactions = append(
+			report.ActiveTransactions,
+			sl.Grab(transaction, "TransactionStatus.Name", "unknown").(string))
+	}
+
+	for _, host := range hosts {
+		report.AuthorizedHosts = append(report.AuthorizedHosts, fmt.Sprintf("%s %d (%s)", host.Type, host.HostId, host.IpAddress))
+	}
+
+	for _, subnet := range subnets {
+		report.AuthorizedSubnets = append(report.AuthorizedSubnets, fmt.Sprintf(
+			"%s/%d", sl.Get(subnet.NetworkIdentifier, ""), sl.Get(subnet.Cidr, 0)))
+	}
+
+	return report, nil
+}
+
+// CancelVolume cancels the volume with the provided id, right away when
+// immediate is set, or at the end of the billing cycle otherwise. The hosts
+// and subnets authorized on the volume are deauthorized first when
+// clearAccess is set, and prevent the cancellation otherwise.
+//
+// The cancellation is only issued when none of the pre-flight checks run by
+// CheckVolumeCancellation, which can be used for a dry run, prevents it. The
+// report is returned in all cases.
+func CancelVolume(
+	sess *session.Session,
+	volumeId int,
+	immediate bool,
+	clearAccess bool,
+	reason string,
+) (CancellationReport, error) {
+
+	report, err := CheckVolumeCancellation(sess, volumeId)
+	if err != nil {
+		return report, err
+	}
+
+	if report.Blocked() || (report.Authorized() && !clearAccess) {
+		return report, fmt.Errorf(
+			"Cannot cancel volume %d: %s", volumeId, strings.Join(report.Reasons(), "; "))
+	}
+
+	if report.Authorized() {
+		if err := clearVolumeAccess(sess, volumeId); err != nil {
+			return report, err
+		}
+	}
+
+	ok, err := services.GetBillingItemService(sess).
+		Id(report.BillingItemId).
+		CancelItem(sl.Bool(immediate), sl.Bool(true), sl.String(reason), sl.String(reason))
+	if err != nil {
+		return report, err
+	}
+
+	if !ok {
+		return report, fmt.Errorf("Cancellation of volume %d was not accepted", volumeId)
+	}
+
+	return report, nil
+}
+
+// clearVolumeAccess deauthorizes all the hosts and subnets authorized on the
+// volume with the provided id
+func clearVolumeAccess(sess *session.Session, volumeId int) error {
+	hosts, err := ListAuthorizedHosts(sess, volumeId)
+	if err != nil {
+		return err
+	}
+
+	authorized := Hosts{}
+	for _, host := range hosts {
+		switch host.Type {
+		case HostVirtualGuest:
+			authorized.VirtualGuestIds = append(authorized.VirtualGuestIds, host.HostId)
+		case HostHardware:
+			authorized.HardwareIds = append(authorized.HardwareIds, host.HostId)
+		case HostIpAddress:
+			authorized.IpAddresses = append(authorized.IpAddresses, host.IpAddress)
+		}
+	}
+
+	if err := DeauthorizeHosts(sess, volumeId, authorized); err != nil {
+		return err
+	}
+
+	service := services.GetNetworkStorageService(sess).Id(volumeId)
+
+	subnets, err := service.Mask("id").GetAllowedSubnets()
+	if err != nil {
+		return err
+	}
+
+	if len(subnets) > 0 {
+		if _, err := service.RemoveAccessFromSubnetList(subnets); err != nil {
+			return fmt.Errorf("Error deauthorizing subnets on volume %d: %s", volumeId, err)
+		}
+	}
+
+	return nil
+}
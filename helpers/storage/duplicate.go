@@ -0,0 +1,200 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DuplicateConfig describes the duplicate of a volume.
+//
+// The duplicate is a copy of the volume, or of its snapshot with the provided
+// SnapshotId when set. A dependent duplicate shares the snapshot it is created
+// from with the volume, and is available right away, while an independent
+// duplicate is a full copy. SizeGB, IOPS, Tier and SnapshotSpaceGB default to
+// those of the volume when zero; IOPS only applies to performance volumes, and
+// Tier to endurance ones.
+type DuplicateConfig struct {
+	SnapshotId      int
+	Dependent       bool
+	SizeGB          int
+	IOPS            int
+	Tier            float64
+	SnapshotSpaceGB int
+}
+
+// duplicateOrder is a Container_Product_Order_Network_Storage_AsAService
+// carrying the dependent duplicate flag, which the generated datatype lacks
+type duplicateOrder struct {
+	datatypes.Container_Product_Order_Network_Storage_AsAService
+
+	IsDependentDuplicateFlag *bool `json:"isDependentDuplicateFlag,omitempty" xmlrpc:"isDependentDuplicateFlag,omitempty"`
+}
+
+// DuplicateVolume orders a duplicate of the volume with the provided id, in
+// the datacenter of the volume, waits until the duplication is complete, and
+// returns the duplicate, retrieved with VolumeMask.
+func DuplicateVolume(
+	ctx context.Context,
+	sess *session.Session,
+	volumeId int,
+	config DuplicateConfig,
+	interval time.Duration,
+) (datatypes.Network_Storage, error) {
+
+	volume, err := services.GetNetworkStorageService(sess).
+		Id(volumeId).
+		Mask("id,capacityGb,iops,storageTierLevel,snapshotCapacityGb,storageType[keyName],osType[keyName],serviceResource[datacenter[id]]").
+		GetObject()
+	if err != nil {
+		return datatypes.Network_Storage{}, err
+	}
+
+	datacenterId := sl.Grab(volume, "ServiceResource.Datacenter.Id", 0).(int)
+	if datacenterId == 0 {
+		return datatypes.Network_Storage{}, fmt.Errorf("Could not find the datacenter of volume %d", volumeId)
+	}
+
+	duplicate, category, err := volumeConfig(volume)
+	if err != nil {
+		return datatypes.Network_Storage{}, err
+	}
+
+	if config.SizeGB != 0 {
+		duplicate.SizeGB = config.SizeGB
+	}
+	if config.IOPS != 0 && duplicate.IOPS != 0 {
+		duplicate.IOPS = config.IOPS
+	}
+	if config.Tier != 0 && duplicate.Tier != 0 {
+		duplicate.Tier = config.Tier
+	}
+	if config.SnapshotSpaceGB != 0 {
+		duplicate.SnapshotSpaceGB = config.SnapshotSpaceGB
+	}
+
+	packageId, items, err := getStoragePackage(sess)
+	if err != nil {
+		return datatypes.Network_Storage{}, err
+	}
+
+	prices, err := volumePrices(items, category, duplicate)
+	if err != nil {
+		return datatypes.Network_Storage{}, err
+	}
+
+	order := duplicateOrder{IsDependentDuplicateFlag: sl.Bool(config.Dependent)}
+	order.ComplexType = sl.String("SoftLayer_Container_Product_Order_Network_Storage_AsAService")
+	order.PackageId = sl.Int(packageId)
+	order.Location = sl.String(strconv.Itoa(datacenterId))
+	order.Quantity = sl.Int(1)
+	order.Prices = prices
+	order.DuplicateOriginVolumeId = sl.Int(volumeId)
+	order.VolumeSize = sl.Int(duplicate.SizeGB)
+
+	if config.SnapshotId != 0 {
+		order.DuplicateOriginSnapshotId = sl.Int(config.SnapshotId)
+	}
+
+	if duplicate.IOPS != 0 {
+		order.Iops = sl.Int(duplicate.IOPS)
+	}
+
+	if volume.OsType != nil && category == "storage_block" {
+		order.OsFormatType = &datatypes.Network_Storage_Iscsi_OS_Type{KeyName: volume.OsType.KeyName}
+	}
+
+	// Product_Order::placeOrder() is called directly, as the generated
+	// datatype of the order cannot carry the dependent duplicate flag
+	var receipt datatypes.Container_Product_Order_Receipt
+	err = sess.DoRequest("SoftLayer_Product_Order", "placeOrder", []interface{}{&order, false}, &sl.Options{}, &receipt)
+	if err != nil {
+		return datatypes.Network_Storage{}, fmt.Errorf("Error ordering a duplicate of volume %d: %s", volumeId, err)
+	}
+
+	if receipt.OrderId == nil {
+		return datatypes.Network_Storage{}, fmt.Errorf("No order was placed duplicating volume %d", volumeId)
+	}
+
+	return WaitForOrderedVolume(ctx, sess, *receipt.OrderId, interval)
+}
+
+// ConvertCloneToIndependent converts the dependent duplicate with the provided
+// id to an independent one, waits until the conversion is complete, and
+// returns the volume, retrieved with VolumeMask.
+func ConvertCloneToIndependent(
+	ctx context.Context,
+	sess *session.Session,
+	volumeId int,
+	interval time.Duration,
+) (datatypes.Network_Storage, error) {
+
+	// Network_Storage::convertCloneDependentToIndependent() is called
+	// directly, as it is missing from the generated services
+	var converted bool
+	err := sess.DoRequest("SoftLayer_Network_Storage", "convertCloneDependentToIndependent", nil, &sl.Options{Id: &volumeId}, &converted)
+	if err != nil {
+		return datatypes.Network_Storage{}, fmt.Errorf("Error converting volume %d to an independent volume: %s", volumeId, err)
+	}
+
+	if !converted {
+		return datatypes.Network_Storage{}, fmt.Errorf("Conversion of volume %d to an independent volume was not accepted", volumeId)
+	}
+
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	// The conversion transaction does not start right away, so the volume is
+	// polled until it is no longer a dependent duplicate
+	for {
+		// Network_Storage::getObject() is called directly, as the generated
+		// datatype of the volume cannot carry the dependent duplicate property
+		var volume dependentVolume
+		err := sess.DoRequest("SoftLayer_Network_Storage", "getObject", nil, &sl.Options{Id: &volumeId, Mask: "mask[id,dependentDuplicate]"}, &volume)
+		if err != nil {
+			return datatypes.Network_Storage{}, err
+		}
+
+		if !isDependentDuplicate(volume) {
+			return waitForVolume(ctx, sess, volumeId, VolumeMask, interval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return datatypes.Network_Storage{}, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func isDependentDuplicate(volume dependentVolume) bool {
+	switch sl.Get(volume.DependentDuplicate, "").(string) {
+	case "", "0", "false":
+		return false
+	}
+
+	return true
+}
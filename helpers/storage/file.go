@@ -0,0 +1,58 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// OrderFileVolume places an order for the NFS file volume described by config.
+// The OSType of config is ignored. The volume can be waited for with
+// WaitForFileVolume.
+func OrderFileVolume(sess *session.Session, config VolumeConfig) (datatypes.Container_Product_Order_Receipt, error) {
+	return orderVolume(sess, "storage_file", config)
+}
+
+// WaitForFileVolume is like WaitForOrderedVolume, and also returns the NFS
+// mount path of the volume, see MountPath
+func WaitForFileVolume(
+	ctx context.Context,
+	sess *session.Session,
+	orderId int,
+	interval time.Duration,
+) (datatypes.Network_Storage, string, error) {
+
+	volume, err := WaitForOrderedVolume(ctx, sess, orderId, interval)
+	if err != nil {
+		return datatypes.Network_Storage{}, "", err
+	}
+
+	return volume, MountPath(volume), nil
+}
+
+// MountPath returns the NFS mount path of a file volume (e.g.
+// "fsf-dal1001a-fz.service.softlayer.com:/SL01SV123_1/data01"), or an empty
+// string when the volume is not provisioned yet. The volume must be retrieved
+// with the fileNetworkMountAddress property, as with VolumeMask.
+func MountPath(volume datatypes.Network_Storage) string {
+	return sl.Get(volume.FileNetworkMountAddress, "").(string)
+}
@@ -0,0 +1,69 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/filter"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+)
+
+// Query holds the criteria used to search the volumes of the account. Empty
+// fields are ignored. Datacenter and Username match exactly, while Notes
+// matches the volumes whose notes contain it. StorageType is the key name of
+// the storage type of the volumes (e.g. "ENDURANCE_BLOCK_STORAGE",
+// "PERFORMANCE_FILE_STORAGE").
+type Query struct {
+	Datacenter  string
+	Username    string
+	Notes       string
+	StorageType string
+}
+
+// FindVolumes returns the volumes of the account matching query. An object
+// mask can be provided as an optional argument, and VolumeMask is used
+// otherwise.
+func FindVolumes(sess *session.Session, query Query, mask ...string) ([]datatypes.Network_Storage, error) {
+	objectMask := VolumeMask
+	if len(mask) > 0 {
+		objectMask = mask[0]
+	}
+
+	filters := filter.New()
+
+	if query.Datacenter != "" {
+		filters = append(filters, filter.Path("networkStorage.serviceResource.datacenter.name").Eq(query.Datacenter))
+	}
+
+	if query.Username != "" {
+		filters = append(filters, filter.Path("networkStorage.username").Eq(query.Username))
+	}
+
+	if query.Notes != "" {
+		filters = append(filters, filter.Path("networkStorage.notes").Like(query.Notes))
+	}
+
+	if query.StorageType != "" {
+		filters = append(filters, filter.Path("networkStorage.storageType.keyName").Eq(query.StorageType))
+	}
+
+	return services.GetAccountService(sess).
+		Mask(objectMask).
+		Filter(filters.Build()).
+		GetNetworkStorage()
+}
@@ -0,0 +1,159 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+const modifyMask = "id,capacityGb,iops,storageTierLevel,storageType[keyName],activeTransactions[id]"
+
+// VolumeChange describes the modification of a volume. Fields left to zero
+// keep the value of the volume; IOPS only applies to performance volumes, and
+// Tier to endurance ones. A volume can grow, but not shrink.
+type VolumeChange struct {
+	SizeGB int
+	IOPS   int
+	Tier   float64
+}
+
+// modifyOrder is a Container_Product_Order_Network_Storage_AsAService_Upgrade,
+// which is missing from the generated datatypes
+type modifyOrder struct {
+	datatypes.Container_Product_Order_Network_Storage_AsAService
+
+	Volume *datatypes.Network_Storage `json:"volume,omitempty" xmlrpc:"volume,omitempty"`
+}
+
+// ModifyVolume resizes the volume with the provided id and changes its IOPS or
+// endurance tier, as described by change, waits until the modification is
+// applied, and returns the volume, retrieved with VolumeMask.
+func ModifyVolume(
+	ctx context.Context,
+	sess *session.Session,
+	volumeId int,
+	change VolumeChange,
+	interval time.Duration,
+) (datatypes.Network_Storage, error) {
+
+	service := services.GetNetworkStorageService(sess).Id(volumeId).Mask(modifyMask)
+
+	volume, err := service.GetObject()
+	if err != nil {
+		return datatypes.Network_Storage{}, err
+	}
+
+	if len(volume.ActiveTransactions) > 0 {
+		return datatypes.Network_Storage{}, fmt.Errorf("Volume %d has active transactions", volumeId)
+	}
+
+	current, _, err := volumeConfig(volume)
+	if err != nil {
+		return datatypes.Network_Storage{}, err
+	}
+
+	if change.IOPS != 0 && current.IOPS == 0 {
+		return datatypes.Network_Storage{}, fmt.Errorf("The IOPS of endurance volume %d cannot be changed, change its tier instead", volumeId)
+	}
+	if change.Tier != 0 && current.Tier == 0 {
+		return datatypes.Network_Storage{}, fmt.Errorf("The tier of performance volume %d cannot be changed, change its IOPS instead", volumeId)
+	}
+	if change.SizeGB != 0 && change.SizeGB < current.SizeGB {
+		return datatypes.Network_Storage{}, fmt.Errorf("Volume %d cannot shrink from %d GB to %d GB", volumeId, current.SizeGB, change.SizeGB)
+	}
+
+	desired := current
+	if change.SizeGB != 0 {
+		desired.SizeGB = change.SizeGB
+	}
+	if change.IOPS != 0 {
+		desired.IOPS = change.IOPS
+	}
+	if change.Tier != 0 {
+		desired.Tier = change.Tier
+	}
+
+	if desired == current {
+		return waitForVolume(ctx, sess, volumeId, VolumeMask, interval)
+	}
+
+	packageId, items, err := getStoragePackage(sess)
+	if err != nil {
+		return datatypes.Network_Storage{}, err
+	}
+
+	servicePrice, err := categoryPrice(items, "storage_as_a_service")
+	if err != nil {
+		return datatypes.Network_Storage{}, err
+	}
+
+	capacity, err := capacityPrices(items, desired)
+	if err != nil {
+		return datatypes.Network_Storage{}, err
+	}
+
+	order := modifyOrder{Volume: &datatypes.Network_Storage{Id: sl.Int(volumeId)}}
+	order.ComplexType = sl.String("SoftLayer_Container_Product_Order_Network_Storage_AsAService_Upgrade")
+	order.PackageId = sl.Int(packageId)
+	order.Prices = append([]datatypes.Product_Item_Price{servicePrice}, capacity...)
+	order.VolumeSize = sl.Int(desired.SizeGB)
+
+	if desired.IOPS != 0 {
+		order.Iops = sl.Int(desired.IOPS)
+	}
+
+	// Product_Order::placeOrder() is called directly, as the upgrade
+	// container of volumes is missing from the generated datatypes
+	var receipt datatypes.Container_Product_Order_Receipt
+	err = sess.DoRequest("SoftLayer_Product_Order", "placeOrder", []interface{}{&order, false}, &sl.Options{}, &receipt)
+	if err != nil {
+		return datatypes.Network_Storage{}, fmt.Errorf("Error modifying volume %d: %s", volumeId, err)
+	}
+
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return datatypes.Network_Storage{}, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		volume, err := service.GetObject()
+		if err != nil {
+			return datatypes.Network_Storage{}, err
+		}
+
+		applied, _, err := volumeConfig(volume)
+		if err != nil {
+			return datatypes.Network_Storage{}, err
+		}
+
+		if applied == desired && len(volume.ActiveTransactions) == 0 {
+			return waitForVolume(ctx, sess, volumeId, VolumeMask, interval)
+		}
+	}
+}
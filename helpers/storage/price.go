@@ -0,0 +1,333 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/helpers/product"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// storageItemMask is the object mask of the storage package items, which
+// carries the capacity ranges and restrictions prices are selected with
+const storageItemMask = "id,keyName,capacity,capacityMinimum,capacityMaximum,itemCategory[categoryCode],attributes[value]," +
+	"prices[id,locationGroupId,capacityRestrictionType,capacityRestrictionMinimum,capacityRestrictionMaximum,categories[categoryCode]]"
+
+// Capacity restriction types of the storage prices
+const (
+	restrictionTierLevel    = "STORAGE_TIER_LEVEL"
+	restrictionIops         = "IOPS"
+	restrictionStorageSpace = "STORAGE_SPACE"
+)
+
+// enduranceTiers maps the endurance tiers, in IOPS per GB, to the tier level
+// prices are restricted with
+var enduranceTiers = map[float64]int{
+	0.25: 100,
+	2:    200,
+	4:    300,
+	10:   1000,
+}
+
+// tierKeyNames maps the storage tier levels of endurance volumes, as returned
+// by the storageTierLevel property, to their tier in IOPS per GB
+var tierKeyNames = map[string]float64{
+	"LOW_INTENSITY_TIER": 0.25,
+	"READHEAVY_TIER":     2,
+	"WRITEHEAVY_TIER":    4,
+	"10_IOPS_PER_GB":     10,
+}
+
+// getStoragePackage returns the id of the storage as a service package, along
+// with its items
+func getStoragePackage(sess *session.Session) (int, []datatypes.Product_Item, error) {
+	pkg, err := product.GetPackageByKeyName(sess, PackageKeyName, "id")
+	if err != nil {
+		return 0, nil, err
+	}
+
+	items, err := product.GetPackageProducts(sess, *pkg.Id, storageItemMask)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return *pkg.Id, items, nil
+}
+
+// volumePrices returns the prices of a volume of the provided storage category
+// ("storage_block" or "storage_file"), for the size and performance described
+// by config
+func volumePrices(items []datatypes.Product_Item, category string, config VolumeConfig) ([]datatypes.Product_Item_Price, error) {
+	if (config.IOPS == 0) == (config.Tier == 0) {
+		return nil, fmt.Errorf("Either IOPS or an endurance tier is required to order a volume")
+	}
+
+	prices := []datatypes.Product_Item_Price{}
+	for _, c := range []string{"storage_as_a_service", category} {
+		price, err := categoryPrice(items, c)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, price)
+	}
+
+	capacity, err := capacityPrices(items, config)
+	if err != nil {
+		return nil, err
+	}
+	prices = append(prices, capacity...)
+
+	if config.SnapshotSpaceGB != 0 {
+		snapshot, err := snapshotSpacePrice(items, config.SnapshotSpaceGB, config.Tier, config.IOPS)
+		if err != nil {
+			return nil, err
+		}
+
+		prices = append(prices, snapshot)
+	}
+
+	return prices, nil
+}
+
+// capacityPrices returns the space and performance prices of a volume of the
+// size and performance described by config
+func capacityPrices(items []datatypes.Product_Item, config VolumeConfig) ([]datatypes.Product_Item_Price, error) {
+	if config.IOPS != 0 {
+		space, err := performanceSpacePrice(items, config.SizeGB)
+		if err != nil {
+			return nil, err
+		}
+
+		iops, err := performanceIopsPrice(items, config.SizeGB, config.IOPS)
+		if err != nil {
+			return nil, err
+		}
+
+		return []datatypes.Product_Item_Price{space, iops}, nil
+	}
+
+	tier, err := enduranceTierPrice(items, config.Tier)
+	if err != nil {
+		return nil, err
+	}
+
+	space, err := enduranceSpacePrice(items, config.SizeGB, config.Tier)
+	if err != nil {
+		return nil, err
+	}
+
+	return []datatypes.Product_Item_Price{tier, space}, nil
+}
+
+// categoryPrice returns the standard price of the first item of a category
+func categoryPrice(items []datatypes.Product_Item, category string) (datatypes.Product_Item_Price, error) {
+	for _, item := range items {
+		if price, ok := restrictedPrice(item, category, "", 0); ok {
+			return price, nil
+		}
+	}
+
+	return datatypes.Product_Item_Price{}, fmt.Errorf("No price found for category %s", category)
+}
+
+func performanceSpacePrice(items []datatypes.Product_Item, size int) (datatypes.Product_Item_Price, error) {
+	for _, item := range items {
+		min, max, ok := capacityRange(item)
+		if !ok || size < min || size > max {
+			continue
+		}
+
+		// Performance space items are named after their capacity range
+		if sl.Get(item.KeyName, "").(string) != fmt.Sprintf("%d_%d_GBS", min, max) {
+			continue
+		}
+
+		if price, ok := restrictedPrice(item, "performance_storage_space", "", 0); ok {
+			return price, nil
+		}
+	}
+
+	return datatypes.Product_Item_Price{}, fmt.Errorf("No performance storage space price found for %d GB", size)
+}
+
+func performanceIopsPrice(items []datatypes.Product_Item, size int, iops int) (datatypes.Product_Item_Price, error) {
+	for _, item := range items {
+		min, max, ok := capacityRange(item)
+		if !ok || iops < min || iops > max {
+			continue
+		}
+
+		if price, ok := restrictedPrice(item, "performance_storage_iops", restrictionStorageSpace, size); ok {
+			return price, nil
+		}
+	}
+
+	return datatypes.Product_Item_Price{}, fmt.Errorf("No price found for %d IOPS on a %d GB volume", iops, size)
+}
+
+func enduranceTierPrice(items []datatypes.Product_Item, tier float64) (datatypes.Product_Item_Price, error) {
+	level, ok := enduranceTiers[tier]
+	if !ok {
+		return datatypes.Product_Item_Price{}, fmt.Errorf("Invalid endurance tier %g, valid tiers are 0.25, 2, 4 and 10", tier)
+	}
+
+	for _, item := range items {
+		if len(item.Attributes) == 0 || sl.Get(item.Attributes[0].Value, "").(string) != strconv.Itoa(level) {
+			continue
+		}
+
+		if price, ok := restrictedPrice(item, "storage_tier_level", "", 0); ok {
+			return price, nil
+		}
+	}
+
+	return datatypes.Product_Item_Price{}, fmt.Errorf("No price found for endurance tier %g", tier)
+}
+
+func enduranceSpacePrice(items []datatypes.Product_Item, size int, tier float64) (datatypes.Product_Item_Price, error) {
+	level, ok := enduranceTiers[tier]
+	if !ok {
+		return datatypes.Product_Item_Price{}, fmt.Errorf("Invalid endurance tier %g, valid tiers are 0.25, 2, 4 and 10", tier)
+	}
+
+	// Endurance space items are named after their tier (e.g. STORAGE_SPACE_FOR_0_25_IOPS_PER_GB)
+	keyName := strings.Replace(fmt.Sprintf("STORAGE_SPACE_FOR_%g_IOPS_PER_GB", tier), ".", "_", -1)
+
+	for _, item := range items {
+		if sl.Get(item.KeyName, "").(string) != keyName {
+			continue
+		}
+
+		min, max, ok := capacityRange(item)
+		if !ok || size < min || size > max {
+			continue
+		}
+
+		if price, ok := restrictedPrice(item, "performance_storage_space", restrictionTierLevel, level); ok {
+			return price, nil
+		}
+	}
+
+	return datatypes.Product_Item_Price{}, fmt.Errorf("No storage space price found for %d GB at tier %g", size, tier)
+}
+
+// snapshotSpacePrice returns the price of the snapshot space of a volume,
+// which depends on the endurance tier or, for performance volumes, the IOPS of
+// the volume
+func snapshotSpacePrice(items []datatypes.Product_Item, size int, tier float64, iops int) (datatypes.Product_Item_Price, error) {
+	restrictionType, value := restrictionIops, iops
+	if tier != 0 {
+		level, ok := enduranceTiers[tier]
+		if !ok {
+			return datatypes.Product_Item_Price{}, fmt.Errorf("Invalid endurance tier %g, valid tiers are 0.25, 2, 4 and 10", tier)
+		}
+		restrictionType, value = restrictionTierLevel, level
+	}
+
+	for _, item := range items {
+		if item.Capacity == nil || int(*item.Capacity) != size {
+			continue
+		}
+
+		if price, ok := restrictedPrice(item, "storage_snapshot_space", restrictionType, value); ok {
+			return price, nil
+		}
+	}
+
+	return datatypes.Product_Item_Price{}, fmt.Errorf("No snapshot space price found for %d GB", size)
+}
+
+// replicationPrice returns the price of the replication of a volume, which
+// depends on the endurance tier or, for performance volumes, the IOPS of the
+// volume
+func replicationPrice(items []datatypes.Product_Item, tier float64, iops int) (datatypes.Product_Item_Price, error) {
+	keyName, restrictionType, value := "REPLICATION_FOR_IOPSBASED_PERFORMANCE", restrictionIops, iops
+	if tier != 0 {
+		level, ok := enduranceTiers[tier]
+		if !ok {
+			return datatypes.Product_Item_Price{}, fmt.Errorf("Invalid endurance tier %g, valid tiers are 0.25, 2, 4 and 10", tier)
+		}
+		keyName, restrictionType, value = "REPLICATION_FOR_TIERBASED_PERFORMANCE", restrictionTierLevel, level
+	}
+
+	for _, item := range items {
+		if sl.Get(item.KeyName, "").(string) != keyName {
+			continue
+		}
+
+		if price, ok := restrictedPrice(item, "performance_storage_replication", restrictionType, value); ok {
+			return price, nil
+		}
+	}
+
+	return datatypes.Product_Item_Price{}, fmt.Errorf("No replication price found")
+}
+
+// restrictedPrice returns the standard price of item in the provided category,
+// whose capacity restriction, if restrictionType is set, includes value
+func restrictedPrice(item datatypes.Product_Item, category string, restrictionType string, value int) (datatypes.Product_Item_Price, bool) {
+	for _, price := range item.Prices {
+		if price.LocationGroupId != nil || !hasCategory(price, category) {
+			continue
+		}
+
+		if restrictionType != "" {
+			if sl.Get(price.CapacityRestrictionType, "").(string) != restrictionType {
+				continue
+			}
+
+			min, _ := strconv.Atoi(sl.Get(price.CapacityRestrictionMinimum, "").(string))
+			max, _ := strconv.Atoi(sl.Get(price.CapacityRestrictionMaximum, "").(string))
+			if value < min || value > max {
+				continue
+			}
+		}
+
+		return price, true
+	}
+
+	return datatypes.Product_Item_Price{}, false
+}
+
+func capacityRange(item datatypes.Product_Item) (int, int, bool) {
+	min, err := strconv.Atoi(sl.Get(item.CapacityMinimum, "").(string))
+	if err != nil {
+		return 0, 0, false
+	}
+
+	max, err := strconv.Atoi(sl.Get(item.CapacityMaximum, "").(string))
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return min, max, true
+}
+
+func hasCategory(price datatypes.Product_Item_Price, category string) bool {
+	for _, c := range price.Categories {
+		if sl.Get(c.CategoryCode, "").(string) == category {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,270 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/helpers/location"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+const replicationMask = "id,replicationStatus,activeTransactions[id]," +
+	"replicationPartners[id,username,serviceResource[datacenter[name]]],replicationEvents[createDate,message]"
+
+// Replica is a replication partner of a volume
+type Replica struct {
+	Id         int
+	Username   string
+	Datacenter string
+}
+
+// ReplicationStatus is the replication state of a volume. LastReplication is
+// the time of the latest replication event of the volume, and Lag the time
+// elapsed since then; both are zero when the volume never replicated.
+type ReplicationStatus struct {
+	Status          string
+	Replicas        []Replica
+	LastReplication time.Time
+	Lag             time.Duration
+}
+
+// OrderReplicaVolume places an order for a replica of the volume with the
+// provided id in the provided datacenter. The replica has the size,
+// performance and snapshot space of the volume, and is synchronized on the
+// replication schedule of the provided interval (ScheduleHourly, ScheduleDaily
+// or ScheduleWeekly), which must be set on the volume beforehand. The replica
+// can be waited for with WaitForOrderedVolume.
+func OrderReplicaVolume(
+	sess *session.Session,
+	volumeId int,
+	datacenter string,
+	interval string,
+) (datatypes.Container_Product_Order_Receipt, error) {
+
+	volume, err := services.GetNetworkStorageService(sess).
+		Id(volumeId).
+		Mask("id,capacityGb,iops,storageTierLevel,snapshotCapacityGb,storageType[keyName],osType[keyName],schedules[id,type[keyname]]").
+		GetObject()
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	scheduleKeyName := "REPLICATION_" + strings.ToUpper(interval)
+	var scheduleId *int
+	for _, schedule := range volume.Schedules {
+		if sl.Grab(schedule, "Type.Keyname", "").(string) == scheduleKeyName {
+			scheduleId = schedule.Id
+			break
+		}
+	}
+	if scheduleId == nil {
+		return datatypes.Container_Product_Order_Receipt{}, fmt.Errorf(
+			"Volume %d has no %s replication schedule", volumeId, strings.ToLower(interval))
+	}
+
+	config, category, err := volumeConfig(volume)
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	packageId, items, err := getStoragePackage(sess)
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	prices, err := volumePrices(items, category, config)
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	replication, err := replicationPrice(items, config.Tier, config.IOPS)
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	dc, err := location.GetLocationByName(sess, datacenter, "id")
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	orderContainer := datatypes.Container_Product_Order_Network_Storage_AsAService{
+		Container_Product_Order: datatypes.Container_Product_Order{
+			PackageId: sl.Int(packageId),
+			Location:  sl.String(strconv.Itoa(*dc.Id)),
+			Quantity:  sl.Int(1),
+			Prices:    append(prices, replication),
+		},
+		OriginVolumeId:         sl.Int(volumeId),
+		OriginVolumeScheduleId: scheduleId,
+		VolumeSize:             sl.Int(config.SizeGB),
+	}
+
+	if config.IOPS != 0 {
+		orderContainer.Iops = sl.Int(config.IOPS)
+	}
+
+	if volume.OsType != nil && category == "storage_block" {
+		orderContainer.OsFormatType = &datatypes.Network_Storage_Iscsi_OS_Type{KeyName: volume.OsType.KeyName}
+	}
+
+	return services.GetProductOrderService(sess).PlaceOrder(&orderContainer, sl.Bool(false))
+}
+
+// GetReplicationStatus returns the replication status of the volume with the
+// provided id
+func GetReplicationStatus(sess *session.Session, volumeId int) (ReplicationStatus, error) {
+	volume, err := services.GetNetworkStorageService(sess).Id(volumeId).Mask(replicationMask).GetObject()
+	if err != nil {
+		return ReplicationStatus{}, err
+	}
+
+	status := ReplicationStatus{
+		Status:   sl.Get(volume.ReplicationStatus, "").(string),
+		Replicas: replicas(volume),
+	}
+
+	for _, event := range volume.ReplicationEvents {
+		if event.CreateDate != nil && event.CreateDate.After(status.LastReplication) {
+			status.LastReplication = event.CreateDate.Time
+		}
+	}
+
+	if !status.LastReplication.IsZero() {
+		status.Lag = time.Since(status.LastReplication)
+	}
+
+	return status, nil
+}
+
+// Failover fails the volume with the provided id over to its replica with the
+// provided id. The failover waits for a last replication of the volume, unless
+// immediate is set, in which case the content written since the latest
+// replication is lost. An error is returned, without failing over, when the
+// replica is not a replication partner of the volume or the volume has active
+// transactions.
+func Failover(sess *session.Session, volumeId int, replicaId int, immediate bool) error {
+	volume, err := services.GetNetworkStorageService(sess).Id(volumeId).Mask(replicationMask).GetObject()
+	if err != nil {
+		return err
+	}
+
+	if err := checkReplication(volume); err != nil {
+		return err
+	}
+
+	found := false
+	for _, replica := range replicas(volume) {
+		found = found || replica.Id == replicaId
+	}
+	if !found {
+		return fmt.Errorf("Volume %d is not a replica of volume %d", replicaId, volumeId)
+	}
+
+	service := services.GetNetworkStorageService(sess).Id(volumeId)
+	if immediate {
+		_, err = service.ImmediateFailoverToReplicant(sl.Int(replicaId))
+	} else {
+		_, err = service.FailoverToReplicant(sl.Int(replicaId))
+	}
+	if err != nil {
+		return fmt.Errorf("Error failing volume %d over to replica %d: %s", volumeId, replicaId, err)
+	}
+
+	return nil
+}
+
+// Failback fails the volume with the provided id back from the replica it was
+// failed over to. An error is returned, without failing back, when the volume
+// has no replica or has active transactions.
+func Failback(sess *session.Session, volumeId int) error {
+	volume, err := services.GetNetworkStorageService(sess).Id(volumeId).Mask(replicationMask).GetObject()
+	if err != nil {
+		return err
+	}
+
+	if err := checkReplication(volume); err != nil {
+		return err
+	}
+
+	if _, err := services.GetNetworkStorageService(sess).Id(volumeId).FailbackFromReplicant(); err != nil {
+		return fmt.Errorf("Error failing volume %d back: %s", volumeId, err)
+	}
+
+	return nil
+}
+
+// volumeConfig returns the configuration and storage category of an existing
+// volume, to order another volume like it
+func volumeConfig(volume datatypes.Network_Storage) (VolumeConfig, string, error) {
+	config := VolumeConfig{SizeGB: sl.Get(volume.CapacityGb, 0).(int)}
+	config.SnapshotSpaceGB, _ = strconv.Atoi(sl.Get(volume.SnapshotCapacityGb, "").(string))
+
+	storageType := sl.Grab(volume, "StorageType.KeyName", "").(string)
+	switch {
+	case strings.Contains(storageType, "ENDURANCE"):
+		tier, ok := tierKeyNames[sl.Get(volume.StorageTierLevel, "").(string)]
+		if !ok {
+			return VolumeConfig{}, "", fmt.Errorf("Unknown storage tier level of volume %d", *volume.Id)
+		}
+		config.Tier = tier
+	case strings.Contains(storageType, "PERFORMANCE"):
+		config.IOPS, _ = strconv.Atoi(sl.Get(volume.Iops, "").(string))
+	default:
+		return VolumeConfig{}, "", fmt.Errorf("Unsupported storage type %s of volume %d", storageType, *volume.Id)
+	}
+
+	category := "storage_file"
+	if strings.Contains(storageType, "BLOCK") {
+		category = "storage_block"
+	}
+
+	return config, category, nil
+}
+
+// checkReplication returns an error when the volume has no replica, or has
+// transactions in progress
+func checkReplication(volume datatypes.Network_Storage) error {
+	if len(volume.ReplicationPartners) == 0 {
+		return fmt.Errorf("Volume %d has no replica", *volume.Id)
+	}
+
+	if len(volume.ActiveTransactions) > 0 {
+		return fmt.Errorf("Volume %d has active transactions", *volume.Id)
+	}
+
+	return nil
+}
+
+func replicas(volume datatypes.Network_Storage) []Replica {
+	result := []Replica{}
+	for _, partner := range volume.ReplicationPartners {
+		result = append(result, Replica{
+			Id:         sl.Get(partner.Id, 0).(int),
+			Username:   sl.Get(partner.Username, "").(string),
+			Datacenter: sl.Grab(partner, "ServiceResource.Datacenter.Name", "").(string),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,218 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Intervals of snapshot schedules
+const (
+	ScheduleHourly = "HOURLY"
+	ScheduleDaily  = "DAILY"
+	ScheduleWeekly = "WEEKLY"
+)
+
+// Schedule is a snapshot schedule of a volume. Interval is one of
+// ScheduleHourly, ScheduleDaily or ScheduleWeekly. Hour only applies to daily
+// and weekly schedules, and DayOfWeek to weekly ones. RetentionCount is the
+// number of snapshots kept.
+type Schedule struct {
+	Id             int
+	Interval       string
+	Active         bool
+	Minute         int
+	Hour           int
+	DayOfWeek      string
+	RetentionCount int
+}
+
+// CreateSnapshot takes a manual snapshot of the volume with the provided id,
+// and returns it
+func CreateSnapshot(sess *session.Session, volumeId int, notes string) (datatypes.Network_Storage, error) {
+	return services.GetNetworkStorageService(sess).Id(volumeId).CreateSnapshot(sl.String(notes))
+}
+
+// ListSnapshots returns the snapshots of the volume with the provided id
+func ListSnapshots(sess *session.Session, volumeId int) ([]datatypes.Network_Storage, error) {
+	return services.GetNetworkStorageService(sess).
+		Id(volumeId).
+		Mask("id,notes,createDate,snapshotSizeBytes").
+		GetSnapshots()
+}
+
+// RestoreFromSnapshot restores the volume with the provided id to the
+// snapshot with the provided id. The content written to the volume after the
+// snapshot is lost.
+func RestoreFromSnapshot(sess *session.Session, volumeId int, snapshotId int) error {
+	_, err := services.GetNetworkStorageService(sess).Id(volumeId).RestoreFromSnapshot(sl.Int(snapshotId))
+	if err != nil {
+		return fmt.Errorf("Error restoring volume %d from snapshot %d: %s", volumeId, snapshotId, err)
+	}
+
+	return nil
+}
+
+// DeleteSnapshot deletes the snapshot with the provided id
+func DeleteSnapshot(sess *session.Session, snapshotId int) error {
+	_, err := services.GetNetworkStorageService(sess).Id(snapshotId).DeleteObject()
+	return err
+}
+
+// OrderSnapshotSpace places an order for sizeGB of snapshot space on the
+// volume with the provided id, priced for the tier or IOPS of the volume. The
+// snapshot space of a volume which already has some is upgraded, and must
+// grow.
+func OrderSnapshotSpace(sess *session.Session, volumeId int, sizeGB int) (datatypes.Container_Product_Order_Receipt, error) {
+	volume, err := services.GetNetworkStorageService(sess).
+		Id(volumeId).
+		Mask("id,capacityGb,iops,storageTierLevel,snapshotCapacityGb,storageType[keyName],serviceResource[datacenter[id]]").
+		GetObject()
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	config, _, err := volumeConfig(volume)
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	if config.SnapshotSpaceGB != 0 && sizeGB <= config.SnapshotSpaceGB {
+		return datatypes.Container_Product_Order_Receipt{}, fmt.Errorf(
+			"Volume %d already has %d GB of snapshot space, which can only be upgraded", volumeId, config.SnapshotSpaceGB)
+	}
+
+	packageId, items, err := getStoragePackage(sess)
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	price, err := snapshotSpacePrice(items, sizeGB, config.Tier, config.IOPS)
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	orderContainer := datatypes.Container_Product_Order_Network_Storage_Enterprise_SnapshotSpace{
+		Container_Product_Order: datatypes.Container_Product_Order{
+			PackageId: sl.Int(packageId),
+			Location:  sl.String(strconv.Itoa(sl.Grab(volume, "ServiceResource.Datacenter.Id", 0).(int))),
+			Quantity:  sl.Int(1),
+			Prices:    []datatypes.Product_Item_Price{price},
+		},
+		VolumeId: sl.Int(volumeId),
+	}
+
+	service := services.GetProductOrderService(sess)
+	if config.SnapshotSpaceGB != 0 {
+		return service.PlaceOrder(&datatypes.Container_Product_Order_Network_Storage_Enterprise_SnapshotSpace_Upgrade{
+			Container_Product_Order_Network_Storage_Enterprise_SnapshotSpace: orderContainer,
+		}, sl.Bool(false))
+	}
+
+	return service.PlaceOrder(&orderContainer, sl.Bool(false))
+}
+
+// EnableSchedule enables the snapshot schedule of the provided interval on
+// the volume with the provided id, replacing the previous schedule of that
+// interval. hour is ignored by hourly schedules, and dayOfWeek (e.g.
+// "SUNDAY") by all but weekly schedules.
+func EnableSchedule(
+	sess *session.Session,
+	volumeId int,
+	interval string,
+	hour int,
+	minute int,
+	retention int,
+	dayOfWeek string,
+) error {
+
+	interval = strings.ToUpper(interval)
+	if interval != ScheduleHourly && interval != ScheduleDaily && interval != ScheduleWeekly {
+		return fmt.Errorf("Invalid snapshot schedule interval %s", interval)
+	}
+
+	if interval == ScheduleWeekly && dayOfWeek == "" {
+		return fmt.Errorf("A day of the week is required for weekly snapshot schedules")
+	}
+
+	_, err := services.GetNetworkStorageService(sess).
+		Id(volumeId).
+		EnableSnapshots(sl.String(interval), sl.Int(retention), sl.Int(minute), sl.Int(hour), sl.String(strings.ToUpper(dayOfWeek)))
+	if err != nil {
+		return fmt.Errorf("Error enabling %s snapshots on volume %d: %s", strings.ToLower(interval), volumeId, err)
+	}
+
+	return nil
+}
+
+// DisableSchedule disables the snapshot schedule of the provided interval on
+// the volume with the provided id
+func DisableSchedule(sess *session.Session, volumeId int, interval string) error {
+	_, err := services.GetNetworkStorageService(sess).Id(volumeId).DisableSnapshots(sl.String(strings.ToUpper(interval)))
+	return err
+}
+
+// ListSchedules returns the snapshot schedules of the volume with the
+// provided id
+func ListSchedules(sess *session.Session, volumeId int) ([]Schedule, error) {
+	schedules, err := services.GetNetworkStorageService(sess).
+		Id(volumeId).
+		Mask("id,active,minute,hour,dayOfWeek,retentionCount,type[keyname]").
+		GetSchedules()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Schedule{}
+	for _, schedule := range schedules {
+		// Type key names look like SNAPSHOT_DAILY, and replication schedules
+		// are left out
+		keyName := sl.Grab(schedule, "Type.Keyname", "").(string)
+		if !strings.HasPrefix(keyName, "SNAPSHOT_") {
+			continue
+		}
+
+		// Times are returned as strings, which hold "*" when not applicable
+		minute, _ := strconv.Atoi(sl.Get(schedule.Minute, "").(string))
+		hour, _ := strconv.Atoi(sl.Get(schedule.Hour, "").(string))
+		retention, _ := strconv.Atoi(sl.Get(schedule.RetentionCount, "").(string))
+
+		dayOfWeek := sl.Get(schedule.DayOfWeek, "").(string)
+		if dayOfWeek == "*" {
+			dayOfWeek = ""
+		}
+
+		result = append(result, Schedule{
+			Id:             sl.Get(schedule.Id, 0).(int),
+			Interval:       strings.TrimPrefix(keyName, "SNAPSHOT_"),
+			Active:         sl.Get(schedule.Active, 0).(int) == 1,
+			Minute:         minute,
+			Hour:           hour,
+			DayOfWeek:      dayOfWeek,
+			RetentionCount: retention,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,191 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/helpers/location"
+	"github.com/softlayer/softlayer-go/helpers/order"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// PackageKeyName is the key name of the package block and file volumes are
+// ordered from
+const PackageKeyName = "STORAGE_AS_A_SERVICE_STAAS"
+
+// DefaultPollInterval is the time waited between two checks of a volume,
+// when the functions of this package which wait on a volume are passed a zero
+// interval. These functions return the context's error as soon as their
+// context is canceled or past its deadline.
+const DefaultPollInterval = 30 * time.Second
+
+// VolumeMask is the default object mask for volumes
+const VolumeMask = "id,username,capacityGb,iops,storageTierLevel,notes," +
+	"storageType[keyName],serviceResourceBackendIpAddress,fileNetworkMountAddress," +
+	"serviceResource[datacenter[name]],activeTransactions[id,transactionStatus[name]]"
+
+// ProvisionedVolumeMask is the object mask of the volumes returned by
+// WaitForVolumeProvisioned, which adds the credentials of the volume to
+// VolumeMask
+const ProvisionedVolumeMask = VolumeMask + ",password,credentials[id,username,password,type[keyName]]"
+
+// VolumeConfig describes a block or file volume order.
+//
+// A volume is either a performance volume, with a fixed number of IOPS, or an
+// endurance volume, with a number of IOPS per GB given by Tier (0.25, 2, 4 or
+// 10). OSType is the key name of the operating system of the hosts of a block
+// volume (e.g. "LINUX", "VMWARE"), and does not apply to file volumes. No
+// snapshot space is ordered when SnapshotSpaceGB is zero.
+type VolumeConfig struct {
+	Datacenter      string
+	SizeGB          int
+	IOPS            int
+	Tier            float64
+	OSType          string
+	SnapshotSpaceGB int
+}
+
+// OrderBlockVolume places an order for the block (iSCSI) volume described by
+// config. The volume can be waited for with WaitForOrderedVolume.
+func OrderBlockVolume(sess *session.Session, config VolumeConfig) (datatypes.Container_Product_Order_Receipt, error) {
+	if config.OSType == "" {
+		return datatypes.Container_Product_Order_Receipt{}, fmt.Errorf("An OS type is required to order a block volume")
+	}
+
+	return orderVolume(sess, "storage_block", config)
+}
+
+// WaitForOrderedVolume waits until the volume ordered with the order with the
+// provided id is provisioned, and returns it, retrieved with VolumeMask.
+func WaitForOrderedVolume(
+	ctx context.Context,
+	sess *session.Session,
+	orderId int,
+	interval time.Duration,
+) (datatypes.Network_Storage, error) {
+
+	resources, err := order.WaitForOrder(ctx, sess, orderId, interval)
+	if err != nil {
+		return datatypes.Network_Storage{}, err
+	}
+
+	for _, resource := range resources {
+		if resource.Type == order.ResourceNetworkStorage {
+			return waitForVolume(ctx, sess, resource.Id, VolumeMask, interval)
+		}
+	}
+
+	return datatypes.Network_Storage{}, fmt.Errorf("Order %d did not provision any volume", orderId)
+}
+
+// WaitForVolumeProvisioned waits until the newly ordered volume with the
+// provided id is provisioned, that is until it has a capacity, a service
+// resource and no active transactions, and returns it, retrieved with
+// ProvisionedVolumeMask. Hosts can connect to the volume once they are
+// authorized on it.
+func WaitForVolumeProvisioned(
+	ctx context.Context,
+	sess *session.Session,
+	volumeId int,
+	interval time.Duration,
+) (datatypes.Network_Storage, error) {
+
+	return waitForVolume(ctx, sess, volumeId, ProvisionedVolumeMask, interval)
+}
+
+func orderVolume(sess *session.Session, category string, config VolumeConfig) (datatypes.Container_Product_Order_Receipt, error) {
+	if config.SizeGB == 0 {
+		return datatypes.Container_Product_Order_Receipt{}, fmt.Errorf("A size is required to order a volume")
+	}
+
+	packageId, items, err := getStoragePackage(sess)
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	prices, err := volumePrices(items, category, config)
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	dc, err := location.GetLocationByName(sess, config.Datacenter, "id")
+	if err != nil {
+		return datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	orderContainer := datatypes.Container_Product_Order_Network_Storage_AsAService{
+		Container_Product_Order: datatypes.Container_Product_Order{
+			PackageId: sl.Int(packageId),
+			Location:  sl.String(strconv.Itoa(*dc.Id)),
+			Quantity:  sl.Int(1),
+			Prices:    prices,
+		},
+		VolumeSize: sl.Int(config.SizeGB),
+	}
+
+	if config.IOPS != 0 {
+		orderContainer.Iops = sl.Int(config.IOPS)
+	}
+
+	if config.OSType != "" && category == "storage_block" {
+		orderContainer.OsFormatType = &datatypes.Network_Storage_Iscsi_OS_Type{KeyName: sl.String(config.OSType)}
+	}
+
+	return services.GetProductOrderService(sess).PlaceOrder(&orderContainer, sl.Bool(false))
+}
+
+// waitForVolume polls the volume with the provided id, retrieved with the
+// provided mask, until it has a capacity, a service resource and no active
+// transactions
+func waitForVolume(
+	ctx context.Context,
+	sess *session.Session,
+	volumeId int,
+	mask string,
+	interval time.Duration,
+) (datatypes.Network_Storage, error) {
+
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	service := services.GetNetworkStorageService(sess).Id(volumeId).Mask(mask)
+
+	for {
+		volume, err := service.GetObject()
+		if err != nil {
+			return datatypes.Network_Storage{}, err
+		}
+
+		if volume.CapacityGb != nil && volume.ServiceResource != nil && len(volume.ActiveTransactions) == 0 {
+			return volume, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return datatypes.Network_Storage{}, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,182 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tags
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Types of the resources which can be tagged, as key names of tag types
+const (
+	TypeVirtualGuest  = "GUEST"
+	TypeHardware      = "HARDWARE"
+	TypeDedicatedHost = "DEDICATED_HOST"
+	TypeVlan          = "NETWORK_VLAN"
+	TypeSubnet        = "NETWORK_SUBNET"
+	TypeImage         = "IMAGE_TEMPLATE"
+	TypeTicket        = "TICKET"
+)
+
+// Maximum number of resources tagged at once by BulkSetTags
+const maxConcurrentRequests = 10
+
+const tagReferenceMask = "id,name,references[resourceTableId,tagType[keyName]]"
+
+// Resource is a reference to a tagged resource. Type is the key name of its
+// tag type (e.g. TypeVirtualGuest), and Id its id.
+type Resource struct {
+	Type string
+	Id   int
+}
+
+// TagChange lists the tags to add to, and to remove from, resources
+type TagChange struct {
+	Add    []string
+	Remove []string
+}
+
+// FindResourcesByTag returns the resources of the account tagged with the
+// provided tag, across the types of resources
+func FindResourcesByTag(sess *session.Session, tag string) ([]Resource, error) {
+	found, err := services.GetTagService(sess).Mask(tagReferenceMask).GetTagByTagName(sl.String(tag))
+	if err != nil {
+		return nil, err
+	}
+
+	resources := []Resource{}
+	for _, t := range found {
+		for _, ref := range t.References {
+			resources = append(resources, Resource{
+				Type: sl.Grab(ref, "TagType.KeyName", "").(string),
+				Id:   sl.Get(ref.ResourceTableId, 0).(int),
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// GetResourceTags returns the tags of each tagged resource of the account
+func GetResourceTags(sess *session.Session) (map[Resource][]string, error) {
+	tags, err := services.GetAccountService(sess).Mask(tagReferenceMask).GetTags()
+	if err != nil {
+		return nil, err
+	}
+
+	resourceTags := map[Resource][]string{}
+	for _, tag := range tags {
+		for _, ref := range tag.References {
+			resource := Resource{
+				Type: sl.Grab(ref, "TagType.KeyName", "").(string),
+				Id:   sl.Get(ref.ResourceTableId, 0).(int),
+			}
+			resourceTags[resource] = append(resourceTags[resource], sl.Get(tag.Name, "").(string))
+		}
+	}
+
+	return resourceTags, nil
+}
+
+// BulkSetTags applies change to the tags of the provided resources, adding
+// the tags of change.Add and removing the ones of change.Remove while keeping
+// their other tags. The resources are tagged concurrently, and the errors of
+// all the resources which could not be tagged are returned together.
+func BulkSetTags(sess *session.Session, resources []Resource, change TagChange) error {
+	current, err := GetResourceTags(sess)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, len(resources))
+	slots := make(chan struct{}, maxConcurrentRequests)
+
+	var wg sync.WaitGroup
+	for i, resource := range resources {
+		wg.Add(1)
+		go func(i int, resource Resource) {
+			defer wg.Done()
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			errs[i] = SetTags(sess, resource, applyChange(current[resource], change))
+		}(i, resource)
+	}
+	wg.Wait()
+
+	failed := []string{}
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("Error tagging %d resources: %s", len(failed), strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+// SetTags replaces the tags of resource with the provided ones. The tags of
+// the resource are removed when none is provided.
+func SetTags(sess *session.Session, resource Resource, tags []string) error {
+	ok, err := services.GetTagService(sess).SetTags(
+		sl.String(strings.Join(tags, ",")),
+		sl.String(resource.Type),
+		sl.Int(resource.Id))
+	if err != nil {
+		return fmt.Errorf("Error tagging %s %d: %s", resource.Type, resource.Id, err)
+	}
+
+	if !ok {
+		return fmt.Errorf("Tags of %s %d were not accepted", resource.Type, resource.Id)
+	}
+
+	return nil
+}
+
+// applyChange returns the tags resulting from applying change to tags, sorted
+func applyChange(tags []string, change TagChange) []string {
+	set := map[string]bool{}
+	for _, tag := range tags {
+		set[tag] = true
+	}
+
+	for _, tag := range change.Remove {
+		delete(set, tag)
+	}
+
+	for _, tag := range change.Add {
+		set[tag] = true
+	}
+
+	result := make([]string, 0, len(set))
+	for tag := range set {
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+
+	return result
+}
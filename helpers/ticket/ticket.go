@@ -0,0 +1,108 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ticket
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Types of the devices which can be attached to a ticket
+const (
+	DeviceHardware     = "HARDWARE"
+	DeviceVirtualGuest = "VIRTUAL_GUEST"
+)
+
+// FileAttachment is a file to attach to a ticket
+type FileAttachment struct {
+	Filename string
+	Data     []byte
+}
+
+// TicketConfig describes a ticket to create. SubjectId is the id of one of
+// the subjects returned by Ticket_Subject::getAllObjects. The device with the
+// id AttachedDeviceId, of type AttachedDeviceType (DeviceHardware or
+// DeviceVirtualGuest), is attached to the ticket when set.
+type TicketConfig struct {
+	SubjectId          int
+	Title              string
+	Body               string
+	AttachedDeviceId   int
+	AttachedDeviceType string
+	FileAttachments    []FileAttachment
+}
+
+// CreateTicket creates a ticket assigned to the user the session is
+// authenticated as, attaches the device and the files of config to it, and
+// returns the id of the ticket. The id is returned along with the error when
+// the ticket is created but attaching a file fails.
+func CreateTicket(sess *session.Session, config TicketConfig) (int, error) {
+	if config.SubjectId == 0 || config.Body == "" {
+		return 0, fmt.Errorf("A subject and body are required to create a ticket")
+	}
+
+	var attachmentId *int
+	var attachmentType *string
+	if config.AttachedDeviceId != 0 {
+		if config.AttachedDeviceType != DeviceHardware && config.AttachedDeviceType != DeviceVirtualGuest {
+			return 0, fmt.Errorf("Unknown device type %s", config.AttachedDeviceType)
+		}
+
+		attachmentId = sl.Int(config.AttachedDeviceId)
+		attachmentType = sl.String(config.AttachedDeviceType)
+	}
+
+	user, err := services.GetAccountService(sess).Mask("id").GetCurrentUser()
+	if err != nil {
+		return 0, err
+	}
+
+	template := datatypes.Ticket{
+		SubjectId:      sl.Int(config.SubjectId),
+		AssignedUserId: user.Id,
+	}
+
+	if config.Title != "" {
+		template.Title = sl.String(config.Title)
+	}
+
+	ticket, err := services.GetTicketService(sess).CreateStandardTicket(
+		&template, sl.String(config.Body), attachmentId, nil, nil, nil, nil, attachmentType)
+	if err != nil {
+		return 0, fmt.Errorf("Error creating ticket: %s", err)
+	}
+
+	ticketId := *ticket.Id
+
+	service := services.GetTicketService(sess).Id(ticketId)
+	for _, file := range config.FileAttachments {
+		data := file.Data
+		_, err := service.AddAttachedFile(&datatypes.Container_Utility_File_Attachment{
+			Filename: sl.String(file.Filename),
+			Data:     &data,
+		})
+		if err != nil {
+			return ticketId, fmt.Errorf("Error attaching file %s to ticket %d: %s", file.Filename, ticketId, err)
+		}
+	}
+
+	return ticketId, nil
+}
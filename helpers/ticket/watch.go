@@ -0,0 +1,131 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// DefaultPollInterval is the time waited between two polls of a ticket by
+// WatchTicket, when it is passed a zero interval
+const DefaultPollInterval = 30 * time.Second
+
+const updateMask = "id,createDate,entry,editorId,editorType,type[type]"
+
+// WatchEvent is delivered by WatchTicket for each new update of a ticket, or
+// for each failed poll, in which case Err is set and Update is empty
+type WatchEvent struct {
+	Update datatypes.Ticket_Update
+	Err    error
+}
+
+// WatchTicket polls the ticket with the provided id every interval, and
+// delivers its new updates over the returned channel, oldest first. The updates present when the watch
+// starts are not delivered. Failed polls are delivered as errors without
+// stopping the watch, and the channel is closed once ctx is done.
+func WatchTicket(
+	ctx context.Context,
+	sess *session.Session,
+	ticketId int,
+	interval time.Duration,
+) (<-chan WatchEvent, error) {
+
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	service := services.GetTicketService(sess).Id(ticketId).Mask(updateMask)
+
+	updates, err := service.GetUpdates()
+	if err != nil {
+		return nil, fmt.Errorf("Error listing the updates of ticket %d: %s", ticketId, err)
+	}
+
+	last := lastUpdateId(updates)
+
+	events := make(chan WatchEvent)
+	go func() {
+		defer close(events)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+
+			updates, err := service.GetUpdates()
+			if err != nil {
+				err = fmt.Errorf("Error listing the updates of ticket %d: %s", ticketId, err)
+				if !deliver(ctx, events, WatchEvent{Err: err}) {
+					return
+				}
+				continue
+			}
+
+			sort.SliceStable(updates, func(i, j int) bool {
+				return sl.Get(updates[i].Id, 0).(int) < sl.Get(updates[j].Id, 0).(int)
+			})
+
+			for _, update := range updates {
+				if sl.Get(update.Id, 0).(int) <= last {
+					continue
+				}
+
+				if !deliver(ctx, events, WatchEvent{Update: update}) {
+					return
+				}
+			}
+
+			if id := lastUpdateId(updates); id > last {
+				last = id
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// deliver sends event over events, and reports whether it was delivered
+// before ctx was done
+func deliver(ctx context.Context, events chan<- WatchEvent, event WatchEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func lastUpdateId(updates []datatypes.Ticket_Update) int {
+	last := 0
+	for _, update := range updates {
+		if id := sl.Get(update.Id, 0).(int); id > last {
+			last = id
+		}
+	}
+
+	return last
+}
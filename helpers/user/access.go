@@ -0,0 +1,146 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Permissions granting access to all the devices of the account, present and
+// future
+var allDevicePermissions = []string{"ACCESS_ALL_HARDWARE", "ACCESS_ALL_GUEST"}
+
+// Device is a device a user has access to
+type Device struct {
+	Id       int
+	Hostname string
+}
+
+// DeviceAccess is the access of a user to the devices of the account.
+// AllHardware and AllVirtualGuests report whether the user has access to all
+// the devices of each kind, in which case Hardware and VirtualGuests only list
+// the ones granted individually.
+type DeviceAccess struct {
+	UserId           int
+	Username         string
+	AllHardware      bool
+	AllVirtualGuests bool
+	Hardware         []Device
+	VirtualGuests    []Device
+}
+
+// GetDeviceAccess returns the access of the user with the provided id to the
+// devices of the account
+func GetDeviceAccess(sess *session.Session, userId int) (DeviceAccess, error) {
+	user, err := services.GetUserCustomerService(sess).
+		Id(userId).
+		Mask("id,username,hasFullHardwareAccessFlag,hasFullVirtualGuestAccessFlag," +
+			"hardware[id,fullyQualifiedDomainName],virtualGuests[id,fullyQualifiedDomainName]").
+		GetObject()
+	if err != nil {
+		return DeviceAccess{}, err
+	}
+
+	access := DeviceAccess{
+		UserId:           userId,
+		Username:         sl.Get(user.Username, "").(string),
+		AllHardware:      sl.Get(user.HasFullHardwareAccessFlag, false).(bool),
+		AllVirtualGuests: sl.Get(user.HasFullVirtualGuestAccessFlag, false).(bool),
+		Hardware:         make([]Device, 0, len(user.Hardware)),
+		VirtualGuests:    make([]Device, 0, len(user.VirtualGuests)),
+	}
+
+	for _, hardware := range user.Hardware {
+		access.Hardware = append(access.Hardware, Device{
+			Id:       sl.Get(hardware.Id, 0).(int),
+			Hostname: sl.Get(hardware.FullyQualifiedDomainName, "").(string),
+		})
+	}
+
+	for _, guest := range user.VirtualGuests {
+		access.VirtualGuests = append(access.VirtualGuests, Device{
+			Id:       sl.Get(guest.Id, 0).(int),
+			Hostname: sl.Get(guest.FullyQualifiedDomainName, "").(string),
+		})
+	}
+
+	return access, nil
+}
+
+// GrantDeviceAccess grants the user with the provided id access to the
+// hardware and virtual guests with the provided ids
+func GrantDeviceAccess(sess *session.Session, userId int, hardwareIds []int, guestIds []int) error {
+	service := services.GetUserCustomerService(sess).Id(userId)
+
+	if len(hardwareIds) > 0 {
+		if _, err := service.AddBulkHardwareAccess(hardwareIds); err != nil {
+			return fmt.Errorf("Error granting user %d access to hardware: %s", userId, err)
+		}
+	}
+
+	if len(guestIds) > 0 {
+		if _, err := service.AddBulkVirtualGuestAccess(guestIds); err != nil {
+			return fmt.Errorf("Error granting user %d access to virtual guests: %s", userId, err)
+		}
+	}
+
+	return nil
+}
+
+// RevokeDeviceAccess revokes the access of the user with the provided id to
+// the hardware and virtual guests with the provided ids
+func RevokeDeviceAccess(sess *session.Session, userId int, hardwareIds []int, guestIds []int) error {
+	service := services.GetUserCustomerService(sess).Id(userId)
+
+	if len(hardwareIds) > 0 {
+		if _, err := service.RemoveBulkHardwareAccess(hardwareIds); err != nil {
+			return fmt.Errorf("Error revoking the access of user %d to hardware: %s", userId, err)
+		}
+	}
+
+	if len(guestIds) > 0 {
+		if _, err := service.RemoveBulkVirtualGuestAccess(guestIds); err != nil {
+			return fmt.Errorf("Error revoking the access of user %d to virtual guests: %s", userId, err)
+		}
+	}
+
+	return nil
+}
+
+// SetAllDeviceAccess grants the user with the provided id access to all the
+// hardware and virtual guests of the account, present and future, when
+// enabled is set, and revokes it otherwise. The access granted to individual
+// devices is left unchanged.
+func SetAllDeviceAccess(sess *session.Session, userId int, enabled bool) error {
+	service := services.GetUserCustomerService(sess).Id(userId)
+
+	var err error
+	if enabled {
+		_, err = service.AddBulkPortalPermission(permissionTemplates(allDevicePermissions))
+	} else {
+		_, err = service.RemoveBulkPortalPermission(permissionTemplates(allDevicePermissions))
+	}
+	if err != nil {
+		return fmt.Errorf("Error setting the access of user %d to all devices: %s", userId, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Names of the predefined permission bundles
+const (
+	BundleViewOnly     = "VIEW_ONLY"
+	BundleDeviceAdmin  = "DEVICE_ADMIN"
+	BundleNetworkAdmin = "NETWORK_ADMIN"
+	BundleSupport      = "SUPPORT"
+)
+
+var viewPermissions = []string{
+	"ACCOUNT_SUMMARY_VIEW",
+	"HARDWARE_VIEW",
+	"VIRTUAL_GUEST_VIEW",
+	"TICKET_VIEW",
+	"TICKET_SEARCH",
+}
+
+// PermissionBundles maps the names of the predefined permission bundles to
+// the key names of their permissions. A view only user can look at the
+// account and its devices. A device administrator can also order, reload,
+// upgrade and cancel all the devices of the account, and a network
+// administrator can manage its DNS, firewalls, load balancers and IP
+// addresses. A support user can open and update tickets.
+var PermissionBundles = map[string][]string{
+	BundleViewOnly: viewPermissions,
+	BundleDeviceAdmin: append([]string{
+		"ACCESS_ALL_GUEST",
+		"ACCESS_ALL_HARDWARE",
+		"SERVER_ADD",
+		"SERVER_CANCEL",
+		"SERVER_RELOAD",
+		"SERVER_UPGRADE",
+		"INSTANCE_UPGRADE",
+		"HOSTNAME_EDIT",
+		"REMOTE_MANAGEMENT",
+		"PORT_CONTROL",
+		"CUSTOMER_SSH_KEY_MANAGEMENT",
+	}, viewPermissions...),
+	BundleNetworkAdmin: append([]string{
+		"DNS_MANAGE",
+		"FIREWALL_MANAGE",
+		"LOADBALANCER_MANAGE",
+		"IP_ADD",
+		"NETWORK_VLAN_SPANNING",
+		"PUBLIC_NETWORK_COMPUTE",
+		"SECURITY_MANAGE",
+	}, viewPermissions...),
+	BundleSupport: {
+		"ACCOUNT_SUMMARY_VIEW",
+		"TICKET_ADD",
+		"TICKET_EDIT",
+		"TICKET_VIEW",
+		"TICKET_SEARCH",
+	},
+}
+
+// PermissionDiff lists the key names of the permissions to add to and remove
+// from a user
+type PermissionDiff struct {
+	Add    []string
+	Remove []string
+}
+
+// Empty reports whether the diff holds no change
+func (d PermissionDiff) Empty() bool {
+	return len(d.Add) == 0 && len(d.Remove) == 0
+}
+
+// GetUserPermissions returns the key names of the portal permissions of the
+// user with the provided id, sorted
+func GetUserPermissions(sess *session.Session, userId int) ([]string, error) {
+	permissions, err := services.GetUserCustomerService(sess).Id(userId).Mask("keyName").GetPermissions()
+	if err != nil {
+		return nil, err
+	}
+
+	keyNames := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		keyNames = append(keyNames, sl.Get(permission.KeyName, "").(string))
+	}
+
+	sort.Strings(keyNames)
+	return keyNames, nil
+}
+
+// DiffPermissions returns the permissions to add and remove for a user to go
+// from the current permissions to the desired ones, both given as key names.
+// The key names of the diff are sorted.
+func DiffPermissions(current []string, desired []string) PermissionDiff {
+	has := map[string]bool{}
+	for _, keyName := range current {
+		has[keyName] = true
+	}
+
+	wants := map[string]bool{}
+	for _, keyName := range desired {
+		wants[keyName] = true
+	}
+
+	diff := PermissionDiff{Add: []string{}, Remove: []string{}}
+	for keyName := range wants {
+		if !has[keyName] {
+			diff.Add = append(diff.Add, keyName)
+		}
+	}
+	for keyName := range has {
+		if !wants[keyName] {
+			diff.Remove = append(diff.Remove, keyName)
+		}
+	}
+
+	sort.Strings(diff.Add)
+	sort.Strings(diff.Remove)
+	return diff
+}
+
+// ApplyPermissions sets the portal permissions of the user with the provided
+// id to the desired ones, adding the missing permissions and removing the
+// others, and returns the applied diff. Permissions are given by key name
+// (e.g. "TICKET_VIEW") or by name (e.g. "View Tickets"), case insensitively.
+// When dryRun is set, nothing is changed, and the diff which would be applied
+// is returned.
+func ApplyPermissions(sess *session.Session, userId int, desired []string, dryRun bool) (PermissionDiff, error) {
+	keyNames, err := resolvePermissions(sess, desired)
+	if err != nil {
+		return PermissionDiff{}, err
+	}
+
+	current, err := GetUserPermissions(sess, userId)
+	if err != nil {
+		return PermissionDiff{}, err
+	}
+
+	diff := DiffPermissions(current, keyNames)
+	if dryRun {
+		return diff, nil
+	}
+
+	service := services.GetUserCustomerService(sess).Id(userId)
+
+	if len(diff.Add) > 0 {
+		if _, err := service.AddBulkPortalPermission(permissionTemplates(diff.Add)); err != nil {
+			return diff, fmt.Errorf("Error adding permissions to user %d: %s", userId, err)
+		}
+	}
+
+	if len(diff.Remove) > 0 {
+		if _, err := service.RemoveBulkPortalPermission(permissionTemplates(diff.Remove)); err != nil {
+			return diff, fmt.Errorf("Error removing permissions from user %d: %s", userId, err)
+		}
+	}
+
+	return diff, nil
+}
+
+// resolvePermissions translates permission key names and names to key names
+func resolvePermissions(sess *session.Session, permissions []string) ([]string, error) {
+	all, err := services.GetUserCustomerCustomerPermissionPermissionService(sess).Mask("keyName,name").GetAllObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	keyNames := map[string]string{}
+	for _, permission := range all {
+		keyName := sl.Get(permission.KeyName, "").(string)
+		keyNames[strings.ToUpper(keyName)] = keyName
+		keyNames[strings.ToUpper(sl.Get(permission.Name, "").(string))] = keyName
+	}
+
+	resolved := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		keyName, ok := keyNames[strings.ToUpper(permission)]
+		if !ok {
+			return nil, fmt.Errorf("Unknown permission %s", permission)
+		}
+
+		resolved = append(resolved, keyName)
+	}
+
+	return resolved, nil
+}
+
+func permissionTemplates(keyNames []string) []datatypes.User_Customer_CustomerPermission_Permission {
+	templates := make([]datatypes.User_Customer_CustomerPermission_Permission, 0, len(keyNames))
+	for _, keyName := range keyNames {
+		templates = append(templates, datatypes.User_Customer_CustomerPermission_Permission{KeyName: sl.String(keyName)})
+	}
+
+	return templates
+}
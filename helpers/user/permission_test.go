@@ -0,0 +1,62 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffPermissions(t *testing.T) {
+	tests := []struct {
+		description string
+		current     []string
+		desired     []string
+		expected    PermissionDiff
+	}{
+		{
+			description: "same permissions",
+			current:     []string{"TICKET_VIEW", "ACCOUNT_SUMMARY_VIEW"},
+			desired:     []string{"ACCOUNT_SUMMARY_VIEW", "TICKET_VIEW"},
+			expected:    PermissionDiff{Add: []string{}, Remove: []string{}},
+		},
+		{
+			description: "added and removed permissions, sorted",
+			current:     []string{"TICKET_VIEW", "TICKET_EDIT", "ACCOUNT_SUMMARY_VIEW"},
+			desired:     []string{"TICKET_VIEW", "VPN_MANAGE", "DNS_MANAGE"},
+			expected:    PermissionDiff{Add: []string{"DNS_MANAGE", "VPN_MANAGE"}, Remove: []string{"ACCOUNT_SUMMARY_VIEW", "TICKET_EDIT"}},
+		},
+		{
+			description: "duplicate desired permissions",
+			current:     []string{},
+			desired:     []string{"TICKET_VIEW", "TICKET_VIEW"},
+			expected:    PermissionDiff{Add: []string{"TICKET_VIEW"}, Remove: []string{}},
+		},
+		{
+			description: "no desired permissions",
+			current:     []string{"TICKET_VIEW"},
+			desired:     nil,
+			expected:    PermissionDiff{Add: []string{}, Remove: []string{"TICKET_VIEW"}},
+		},
+	}
+
+	for _, test := range tests {
+		if diff := DiffPermissions(test.current, test.desired); !reflect.DeepEqual(diff, test.expected) {
+			t.Errorf("%s: expected %+v, got %+v", test.description, test.expected, diff)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// activeStatusId is the id of the status of active users
+const activeStatusId = 1001
+
+// UserTemplate describes a user to create. The address, company and timezone
+// of the user default to those of the user the session is authenticated as
+// when empty.
+type UserTemplate struct {
+	Username    string
+	Email       string
+	FirstName   string
+	LastName    string
+	CompanyName string
+	Address1    string
+	City        string
+	State       string
+	Country     string
+	PostalCode  string
+	OfficePhone string
+	TimezoneId  int
+}
+
+// CreateUser creates a user with the provided portal permissions, e.g. one of
+// the PermissionBundles, and returns it. When password is empty, the user is
+// invited by email to set up their own login instead, as required by
+// accounts whose users log in with an IBMid.
+func CreateUser(
+	sess *session.Session,
+	template UserTemplate,
+	permissions []string,
+	password string,
+) (datatypes.User_Customer, error) {
+
+	if template.Username == "" || template.Email == "" {
+		return datatypes.User_Customer{}, fmt.Errorf("A username and email address are required to create a user")
+	}
+
+	current, err := services.GetAccountService(sess).
+		Mask("id,companyName,address1,city,state,country,postalCode,officePhone,timezoneId").
+		GetCurrentUser()
+	if err != nil {
+		return datatypes.User_Customer{}, err
+	}
+
+	user := datatypes.User_Customer{
+		Username:     sl.String(template.Username),
+		Email:        sl.String(template.Email),
+		FirstName:    sl.String(template.FirstName),
+		LastName:     sl.String(template.LastName),
+		CompanyName:  orDefault(template.CompanyName, current.CompanyName),
+		Address1:     orDefault(template.Address1, current.Address1),
+		City:         orDefault(template.City, current.City),
+		State:        orDefault(template.State, current.State),
+		Country:      orDefault(template.Country, current.Country),
+		PostalCode:   orDefault(template.PostalCode, current.PostalCode),
+		OfficePhone:  orDefault(template.OfficePhone, current.OfficePhone),
+		TimezoneId:   current.TimezoneId,
+		UserStatusId: sl.Int(activeStatusId),
+	}
+
+	if template.TimezoneId != 0 {
+		user.TimezoneId = sl.Int(template.TimezoneId)
+	}
+
+	var pass *string
+	if password != "" {
+		pass = sl.String(password)
+	}
+
+	created, err := services.GetUserCustomerService(sess).CreateObject(&user, pass, nil)
+	if err != nil {
+		return datatypes.User_Customer{}, fmt.Errorf("Error creating user %s: %s", template.Username, err)
+	}
+
+	if len(permissions) > 0 {
+		_, err := services.GetUserCustomerService(sess).Id(*created.Id).AddBulkPortalPermission(permissionTemplates(permissions))
+		if err != nil {
+			return created, fmt.Errorf("Error adding permissions to user %s: %s", template.Username, err)
+		}
+	}
+
+	return created, nil
+}
+
+// orDefault returns value, or def when value is empty
+func orDefault(value string, def *string) *string {
+	if value != "" {
+		return sl.String(value)
+	}
+
+	return def
+}
+
+// RotateAPIKey replaces the API keys of the user with the provided id with a
+// new one, and returns the new key.
+//
+// The new key is created before the previous ones are removed when the
+// account allows it. Otherwise, when the new key is refused as users are
+// usually limited to one key, the previous key is removed first, which is
+// refused when the session itself authenticates with it, since the session
+// could not create the new key afterwards. Any other error creating the new
+// key is returned, leaving the previous keys in place.
+func RotateAPIKey(sess *session.Session, userId int) (string, error) {
+	service := services.GetUserCustomerService(sess).Id(userId)
+
+	previous, err := service.Mask("id,authenticationKey").GetApiAuthenticationKeys()
+	if err != nil {
+		return "", err
+	}
+
+	key, err := service.AddApiAuthenticationKey()
+	if err != nil {
+		// Only the refusal of a key beyond the limit of the user falls back to
+		// removing the previous keys first: any other error leaves them as they are
+		if len(previous) == 0 || !isKeyLimitError(err) {
+			return "", fmt.Errorf("Error creating an API key for user %d: %s", userId, err)
+		}
+
+		for _, p := range previous {
+			if sess.APIKey != "" && sl.Get(p.AuthenticationKey, "").(string) == sess.APIKey {
+				return "", fmt.Errorf("Cannot rotate the API key of user %d with a session authenticated by that key", userId)
+			}
+		}
+
+		if err := removeAPIKeys(sess, userId, previous); err != nil {
+			return "", err
+		}
+
+		key, err = service.AddApiAuthenticationKey()
+		if err != nil {
+			return "", fmt.Errorf("Error creating an API key for user %d, which has none left: %s", userId, err)
+		}
+
+		return key, nil
+	}
+
+	if err := removeAPIKeys(sess, userId, previous); err != nil {
+		return key, err
+	}
+
+	return key, nil
+}
+
+// isKeyLimitError returns whether the error is the refusal of the API to
+// create an API key for a user who already has as many as allowed
+func isKeyLimitError(err error) bool {
+	apiErr, ok := err.(sl.Error)
+	if !ok {
+		return false
+	}
+
+	message := strings.ToLower(apiErr.Message)
+	return strings.Contains(message, "key") &&
+		(strings.Contains(message, "already") || strings.Contains(message, "limit") || strings.Contains(message, "maximum"))
+}
+
+func removeAPIKeys(sess *session.Session, userId int, keys []datatypes.User_Customer_ApiAuthentication) error {
+	service := services.GetUserCustomerService(sess).Id(userId)
+
+	for _, key := range keys {
+		if _, err := service.RemoveApiAuthenticationKey(key.Id); err != nil {
+			return fmt.Errorf("Error removing API key %d of user %d: %s", sl.Get(key.Id, 0), userId, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"fmt"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// VpnSubnet is a subnet a user reaches through the SSL VPN. OverrideId is the
+// id of the override granting the access.
+type VpnSubnet struct {
+	OverrideId        int
+	SubnetId          int
+	NetworkIdentifier string
+	Cidr              int
+}
+
+// VpnAccess is the SSL VPN access of a user. With a manual configuration, the
+// user only reaches the subnets listed in Subnets through the VPN; otherwise,
+// the user reaches the subnets of all the devices they have access to.
+type VpnAccess struct {
+	SslVpnAllowed bool
+	ManualConfig  bool
+	Subnets       []VpnSubnet
+}
+
+// GetVpnAccess returns the SSL VPN access of the user with the provided id
+func GetVpnAccess(sess *session.Session, userId int) (VpnAccess, error) {
+	user, err := services.GetUserCustomerService(sess).
+		Id(userId).
+		Mask("id,sslVpnAllowedFlag,vpnManualConfig,overrides[id,subnetId,subnet[id,networkIdentifier,cidr]]").
+		GetObject()
+	if err != nil {
+		return VpnAccess{}, err
+	}
+
+	access := VpnAccess{
+		SslVpnAllowed: sl.Get(user.SslVpnAllowedFlag, false).(bool),
+		ManualConfig:  sl.Get(user.VpnManualConfig, false).(bool),
+		Subnets:       make([]VpnSubnet, 0, len(user.Overrides)),
+	}
+
+	for _, override := range user.Overrides {
+		access.Subnets = append(access.Subnets, VpnSubnet{
+			OverrideId:        sl.Get(override.Id, 0).(int),
+			SubnetId:          sl.Get(override.SubnetId, 0).(int),
+			NetworkIdentifier: sl.Grab(override, "Subnet.NetworkIdentifier", "").(string),
+			Cidr:              sl.Grab(override, "Subnet.Cidr", 0).(int),
+		})
+	}
+
+	return access, nil
+}
+
+// SetSslVpnAllowed allows or forbids the user with the provided id to connect
+// to the SSL VPN
+func SetSslVpnAllowed(sess *session.Session, userId int, allowed bool) error {
+	_, err := services.GetUserCustomerService(sess).
+		Id(userId).
+		EditObject(&datatypes.User_Customer{SslVpnAllowedFlag: sl.Bool(allowed)})
+	if err != nil {
+		return fmt.Errorf("Error setting the SSL VPN access of user %d: %s", userId, err)
+	}
+
+	return UpdateVpnUser(sess, userId)
+}
+
+// SetVpnSubnets restricts the SSL VPN access of the user with the provided id
+// to the subnets with the provided ids, switching the user to a manual VPN
+// configuration. Overrides are only created for the missing subnets, and
+// deleted for the subnets which are not listed. The VPN configuration of the
+// user is updated afterwards.
+func SetVpnSubnets(sess *session.Session, userId int, subnetIds []int) error {
+	access, err := GetVpnAccess(sess, userId)
+	if err != nil {
+		return err
+	}
+
+	wanted := map[int]bool{}
+	for _, id := range subnetIds {
+		wanted[id] = true
+	}
+
+	present := map[int]bool{}
+	stale := []datatypes.Network_Service_Vpn_Overrides{}
+	for _, subnet := range access.Subnets {
+		present[subnet.SubnetId] = true
+		if !wanted[subnet.SubnetId] {
+			stale = append(stale, datatypes.Network_Service_Vpn_Overrides{Id: sl.Int(subnet.OverrideId)})
+		}
+	}
+
+	missing := []datatypes.Network_Service_Vpn_Overrides{}
+	for _, id := range subnetIds {
+		if !present[id] {
+			missing = append(missing, datatypes.Network_Service_Vpn_Overrides{
+				UserId:   sl.Int(userId),
+				SubnetId: sl.Int(id),
+			})
+			present[id] = true
+		}
+	}
+
+	if !access.ManualConfig {
+		if err := SetVpnManualConfig(sess, userId, true); err != nil {
+			return err
+		}
+	}
+
+	service := services.GetNetworkServiceVpnOverridesService(sess)
+
+	if len(stale) > 0 {
+		if _, err := service.DeleteObjects(stale); err != nil {
+			return fmt.Errorf("Error removing VPN subnets of user %d: %s", userId, err)
+		}
+	}
+
+	if len(missing) > 0 {
+		if _, err := service.CreateObjects(missing); err != nil {
+			return fmt.Errorf("Error adding VPN subnets to user %d: %s", userId, err)
+		}
+	}
+
+	return UpdateVpnUser(sess, userId)
+}
+
+// SetVpnManualConfig switches the user with the provided id to a manual VPN
+// configuration, restricted to the subnets set with SetVpnSubnets, when
+// manual is set, or back to an automatic one otherwise
+func SetVpnManualConfig(sess *session.Session, userId int, manual bool) error {
+	_, err := services.GetUserCustomerService(sess).
+		Id(userId).
+		EditObject(&datatypes.User_Customer{VpnManualConfig: sl.Bool(manual)})
+	if err != nil {
+		return fmt.Errorf("Error setting the VPN configuration of user %d: %s", userId, err)
+	}
+
+	return nil
+}
+
+// UpdateVpnUser pushes the VPN access of the user with the provided id to the
+// VPN, which is needed for changes to the access to take effect
+func UpdateVpnUser(sess *session.Session, userId int) error {
+	_, err := services.GetUserCustomerService(sess).Id(userId).UpdateVpnUser()
+	if err != nil {
+		return fmt.Errorf("Error updating the VPN configuration of user %d: %s", userId, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type pkgInfo struct {
+	ImportPath string
+	Dir        string
+	GoFiles    []string
+	Export     string
+}
+
+func main() {
+	root := os.Args[1]
+	cmd := exec.Command("go", "list", "-export", "-deps", "-json", "./helpers/...")
+	cmd.Dir = root
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	out, err := cmd.Output()
+	if err != nil {
+		panic(err)
+	}
+	dec := json.NewDecoder(strings.NewReader(string(out)))
+	exports := map[string]string{}
+	var targets []pkgInfo
+	for {
+		var p pkgInfo
+		if err := dec.Decode(&p); err == io.EOF {
+			break
+		} else if err != nil {
+			panic(err)
+		}
+		exports[p.ImportPath] = p.Export
+		if strings.Contains(p.ImportPath, "softlayer-go/helpers/") {
+			targets = append(targets, p)
+		}
+	}
+	fset := token.NewFileSet()
+	imp := importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+		return os.Open(exports[path])
+	})
+	for _, p := range targets {
+		var files []*ast.File
+		for _, f := range p.GoFiles {
+			af, err := parser.ParseFile(fset, filepath.Join(p.Dir, f), nil, 0)
+			if err != nil {
+				panic(err)
+			}
+			files = append(files, af)
+		}
+		info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+		conf := types.Config{Importer: imp}
+		if _, err := conf.Check(p.ImportPath, fset, files, info); err != nil {
+			fmt.Println("typecheck error", err)
+			continue
+		}
+		for _, f := range files {
+			ast.Inspect(f, func(n ast.Node) bool {
+				// look for TypeAssert(Call sl.Grab)
+				var call *ast.CallExpr
+				var asserted types.Type
+				if ta, ok := n.(*ast.TypeAssertExpr); ok {
+					if c, ok := ta.X.(*ast.CallExpr); ok {
+						call = c
+						if ta.Type != nil {
+							asserted = info.Types[ta.Type].Type
+						}
+					}
+				} else {
+					return true
+				}
+				if call == nil {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || sel.Sel.Name != "Grab" {
+					return true
+				}
+				if id, ok := sel.X.(*ast.Ident); !ok || id.Name != "sl" {
+					return true
+				}
+				pos := fset.Position(call.Pos())
+				t := info.Types[call.Args[0]].Type
+				lit, ok := call.Args[1].(*ast.BasicLit)
+				if !ok {
+					fmt.Println(pos, "non-literal path")
+					return true
+				}
+				path, _ := strconv.Unquote(lit.Value)
+				if _, isPtr := t.Underlying().(*types.Pointer); isPtr {
+					fmt.Println(pos, "FIRST ARG IS POINTER (always default):", t)
+				}
+				cur := t
+				for _, seg := range strings.Split(path, ".") {
+					for {
+						if p, ok := cur.Underlying().(*types.Pointer); ok {
+							cur = p.Elem()
+						} else {
+							break
+						}
+					}
+					obj, _, _ := types.LookupFieldOrMethod(cur, true, nil, seg)
+					v, ok := obj.(*types.Var)
+					if !ok || !v.IsField() {
+						fmt.Println(pos, "MISSING FIELD", seg, "in", cur, "path", path)
+						return true
+					}
+					cur = v.Type()
+				}
+				if p, ok := cur.Underlying().(*types.Pointer); ok {
+					cur = p.Elem()
+				}
+				if asserted != nil && !types.Identical(cur, asserted) {
+					fmt.Println(pos, "TYPE MISMATCH path", path, "field", cur, "asserted", asserted)
+				}
+				if len(call.Args) > 2 {
+					dt := info.Types[call.Args[2]].Type
+					if b, ok := dt.(*types.Basic); ok && b.Info()&types.IsUntyped != 0 {
+						dt = types.Default(dt)
+					}
+					if asserted != nil && !types.Identical(dt, asserted) {
+						fmt.Println(pos, "DEFAULT TYPE MISMATCH path", path, "default", dt, "asserted", asserted)
+					}
+				}
+				return true
+			})
+		}
+	}
+}
@@ -17,11 +17,14 @@
 package session
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/user"
+	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/softlayer/softlayer-go/config"
@@ -204,11 +207,59 @@ func New(args ...interface{}) *Session {
 //
 // For a description of parameters, see TransportHandler.DoRequest in this package
 func (r *Session) DoRequest(service string, method string, args []interface{}, options *sl.Options, pResult interface{}) error {
+	return r.transport().DoRequest(r, service, method, args, options, pResult)
+}
+
+// transportMutex guards the assignment of the default transport handler of the sessions, so
+// that a session can be shared by concurrent requests from its very first request
+var transportMutex sync.Mutex
+
+// transport returns the transport handler of the session, assigning the default one on first use
+func (r *Session) transport() TransportHandler {
+	transportMutex.Lock()
+	defer transportMutex.Unlock()
+
 	if r.TransportHandler == nil {
 		r.TransportHandler = getDefaultTransport(r.Endpoint, r.Logger)
 	}
 
-	return r.TransportHandler.DoRequest(r, service, method, args, options, pResult)
+	return r.TransportHandler
+}
+
+// DoRequestWithContext is DoRequest, returning ctx.Err() as soon as ctx is done. The transport
+// handlers don't accept a context, so a request still in progress when ctx is done is left to
+// complete in the background, and its result is discarded: pResult is only populated when the
+// request completes before ctx is done.
+//
+// It is called by the context-aware variants of the service methods, generated with the
+// -context option of the generator.
+func (r *Session) DoRequestWithContext(ctx context.Context, service string, method string, args []interface{}, options *sl.Options, pResult interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	target := reflect.ValueOf(pResult)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return fmt.Errorf("The result of %s::%s must be a non-nil pointer, got %T", service, method, pResult)
+	}
+
+	transport := r.transport()
+
+	// The request populates its own copy of the result, which is only handed over on completion
+	result := reflect.New(target.Type().Elem())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- transport.DoRequest(r, service, method, args, options, result.Interface())
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		target.Elem().Set(result.Elem())
+		return err
+	}
 }
 
 func envFallback(keyName string, value *string) {
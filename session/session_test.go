@@ -0,0 +1,116 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// blockingTransport completes each request with its result once released
+type blockingTransport struct {
+	release chan struct{}
+	result  string
+}
+
+func (b *blockingTransport) DoRequest(sess *Session, service string, method string, args []interface{}, options *sl.Options, pResult interface{}) error {
+	<-b.release
+	*pResult.(*string) = b.result
+	return nil
+}
+
+func TestDoRequestConcurrentDefaultTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"done"`))
+	}))
+	defer server.Close()
+
+	sess := &Session{Endpoint: server.URL}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var result string
+			err := sess.DoRequest("SoftLayer_Account", "getObject", nil, &sl.Options{}, &result)
+			if err != nil {
+				t.Errorf("Expect no error, but was %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if sess.TransportHandler == nil {
+		t.Errorf("Expect the default transport handler to be set")
+	}
+}
+
+func TestDoRequestWithContext(t *testing.T) {
+	transport := &blockingTransport{release: make(chan struct{}), result: "done"}
+	sess := &Session{TransportHandler: transport}
+	close(transport.release)
+
+	var result string
+	err := sess.DoRequestWithContext(context.Background(), "SoftLayer_Account", "getObject", nil, &sl.Options{}, &result)
+	if err != nil {
+		t.Errorf("Expect no error, but was %s", err)
+	}
+
+	if result != "done" {
+		t.Errorf("Expect result to be done, but was %s", result)
+	}
+}
+
+func TestDoRequestWithContextCanceled(t *testing.T) {
+	transport := &blockingTransport{release: make(chan struct{}), result: "done"}
+	sess := &Session{TransportHandler: transport}
+	defer close(transport.release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go cancel()
+
+	var result string
+	err := sess.DoRequestWithContext(ctx, "SoftLayer_Account", "getObject", nil, &sl.Options{}, &result)
+	if err != context.Canceled {
+		t.Errorf("Expect %s, but was %v", context.Canceled, err)
+	}
+
+	if result != "" {
+		t.Errorf("Expect result to be left unset, but was %s", result)
+	}
+}
+
+func TestDoRequestWithContextInvalidResult(t *testing.T) {
+	sess := &Session{TransportHandler: &blockingTransport{release: make(chan struct{})}}
+
+	var result string
+	var nilResult *string
+
+	for _, pResult := range []interface{}{nil, result, nilResult} {
+		err := sess.DoRequestWithContext(context.Background(), "SoftLayer_Account", "getObject", nil, &sl.Options{}, pResult)
+		if err == nil {
+			t.Errorf("Expect an error for result %#v", pResult)
+		}
+	}
+}
@@ -36,6 +36,21 @@ var Version = VersionInfo{
 	Pre:   "alpha",
 }
 
+// APIMetadataInfo identifies the snapshot of the API metadata the services and datatypes were
+// generated from. Version is the version of the metadata API, Digest the SHA-256 digest of the
+// metadata, and Date the date it was retrieved.
+type APIMetadataInfo struct {
+	Version string
+	Digest  string
+	Date    string
+}
+
+var APIMetadata = APIMetadataInfo{
+	Version: "",
+	Digest:  "",
+	Date:    "",
+}
+
 func (v VersionInfo) String() string {
 	result := fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
 
@@ -45,3 +60,18 @@ func (v VersionInfo) String() string {
 
 	return result
 }
+
+// APIMetadataVersion returns the snapshot of the API metadata the services and datatypes were
+// generated from, e.g. "v3.1 sha256:4f9bf79e3a1c 2016-10-24", or "unknown" when it was not recorded
+func APIMetadataVersion() string {
+	if APIMetadata.Digest == "" {
+		return "unknown"
+	}
+
+	digest := APIMetadata.Digest
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+
+	return fmt.Sprintf("%s sha256:%s %s", APIMetadata.Version, digest, APIMetadata.Date)
+}
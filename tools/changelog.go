@@ -0,0 +1,238 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Changelog lists the differences between two snapshots of the metadata. Services and types are
+// named as in the metadata, methods as SoftLayer_Service::method and properties as
+// SoftLayer_Type.property.
+type Changelog struct {
+	AddedServices     []string
+	RemovedServices   []string
+	AddedTypes        []string
+	RemovedTypes      []string
+	AddedMethods      []string
+	RemovedMethods    []string
+	ChangedMethods    []string
+	AddedProperties   []string
+	RemovedProperties []string
+	ChangedProperties []string
+}
+
+func changelog() {
+	flagset := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	oldFile := flagset.String("old", "", "the previous metadata snapshot to compare against (required)")
+	inputFile := flagset.String("i", "", "read the new metadata from a local file instead of the metadata API")
+	cacheFile := flagset.String("cache", "", "store the fetched metadata in this file, and reuse it while unchanged upstream")
+	flagset.Parse(os.Args[2:])
+
+	if *oldFile == "" {
+		bail(fmt.Errorf("The previous metadata snapshot must be provided with -old"))
+	}
+
+	// Read first, as the snapshot may be the cache file refreshed below
+	oldJson, err := ioutil.ReadFile(*oldFile)
+	if err != nil {
+		bail(fmt.Errorf("Error reading metadata file: %s", err))
+	}
+
+	newJson, err := loadMetadata(*inputFile, *cacheFile)
+	if err != nil {
+		bail(err)
+	}
+
+	var oldMeta, newMeta map[string]Type
+
+	err = json.Unmarshal(oldJson, &oldMeta)
+	if err != nil {
+		bail(fmt.Errorf("Error unmarshaling json response: %s", err))
+	}
+
+	err = json.Unmarshal(newJson, &newMeta)
+	if err != nil {
+		bail(fmt.Errorf("Error unmarshaling json response: %s", err))
+	}
+
+	fmt.Print(diffMetadata(oldMeta, newMeta).String())
+}
+
+// diffMetadata compares two snapshots of the metadata. Only the methods and properties declared
+// by each type are compared, not the ones inherited from their base.
+func diffMetadata(oldMeta map[string]Type, newMeta map[string]Type) Changelog {
+	c := Changelog{}
+
+	for _, name := range getSortedKeys(newMeta) {
+		t := newMeta[name]
+
+		old, ok := oldMeta[name]
+		if !ok {
+			c.AddedTypes = append(c.AddedTypes, name)
+			if !t.NoService {
+				c.AddedServices = append(c.AddedServices, name)
+			}
+			continue
+		}
+
+		if !t.NoService && old.NoService {
+			c.AddedServices = append(c.AddedServices, name)
+		}
+		if t.NoService && !old.NoService {
+			c.RemovedServices = append(c.RemovedServices, name)
+		}
+
+		for _, p := range sortedProperties(t.Properties) {
+			oldProperty, ok := old.Properties[p.Name]
+			switch {
+			case !ok:
+				c.AddedProperties = append(c.AddedProperties, name+"."+p.Name)
+			case typeName(oldProperty.Type, oldProperty.TypeArray) != typeName(p.Type, p.TypeArray):
+				c.ChangedProperties = append(c.ChangedProperties, fmt.Sprintf("%s.%s: %s instead of %s",
+					name, p.Name, typeName(p.Type, p.TypeArray), typeName(oldProperty.Type, oldProperty.TypeArray)))
+			}
+		}
+
+		for _, p := range sortedProperties(old.Properties) {
+			if _, ok := t.Properties[p.Name]; !ok {
+				c.RemovedProperties = append(c.RemovedProperties, name+"."+p.Name)
+			}
+		}
+
+		for _, m := range sortedMethods(t.Methods) {
+			oldMethod, ok := old.Methods[m.Name]
+			switch {
+			case !ok:
+				c.AddedMethods = append(c.AddedMethods, name+"::"+m.Name)
+			case signature(oldMethod) != signature(m):
+				c.ChangedMethods = append(c.ChangedMethods, fmt.Sprintf("%s::%s: %s instead of %s",
+					name, m.Name, signature(m), signature(oldMethod)))
+			}
+		}
+
+		for _, m := range sortedMethods(old.Methods) {
+			if _, ok := t.Methods[m.Name]; !ok {
+				c.RemovedMethods = append(c.RemovedMethods, name+"::"+m.Name)
+			}
+		}
+	}
+
+	for _, name := range getSortedKeys(oldMeta) {
+		if _, ok := newMeta[name]; !ok {
+			c.RemovedTypes = append(c.RemovedTypes, name)
+			if !oldMeta[name].NoService {
+				c.RemovedServices = append(c.RemovedServices, name)
+			}
+		}
+	}
+
+	return c
+}
+
+// String formats the changelog in markdown, leaving out the empty sections
+func (c Changelog) String() string {
+	var buf bytes.Buffer
+
+	sections := []struct {
+		title string
+		items []string
+	}{
+		{"Added services", c.AddedServices},
+		{"Removed services", c.RemovedServices},
+		{"Added methods", c.AddedMethods},
+		{"Removed methods", c.RemovedMethods},
+		{"Changed methods", c.ChangedMethods},
+		{"Added types", c.AddedTypes},
+		{"Removed types", c.RemovedTypes},
+		{"Added properties", c.AddedProperties},
+		{"Removed properties", c.RemovedProperties},
+		{"Changed properties", c.ChangedProperties},
+	}
+
+	for _, section := range sections {
+		if len(section.items) == 0 {
+			continue
+		}
+
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+
+		fmt.Fprintf(&buf, "### %s\n\n", section.title)
+		for _, item := range section.items {
+			fmt.Fprintf(&buf, "- %s\n", item)
+		}
+	}
+
+	if buf.Len() == 0 {
+		return "No API changes\n"
+	}
+
+	return buf.String()
+}
+
+// Returns the signature of a method, e.g. (id int, tags string[]) SoftLayer_Tag[]
+func signature(m Method) string {
+	params := make([]string, 0, len(m.Parameters))
+	for _, p := range m.Parameters {
+		params = append(params, p.Name+" "+typeName(p.Type, p.TypeArray))
+	}
+
+	return "(" + strings.Join(params, ", ") + ") " + typeName(m.Type, m.TypeArray)
+}
+
+func typeName(t string, array bool) string {
+	if array {
+		return t + "[]"
+	}
+
+	return t
+}
+
+func sortedProperties(m map[string]Property) []Property {
+	properties := make([]Property, 0, len(m))
+	for _, p := range m {
+		properties = append(properties, p)
+	}
+
+	sort.Slice(properties, func(i, j int) bool {
+		return properties[i].Name < properties[j].Name
+	})
+
+	return properties
+}
+
+func sortedMethods(m map[string]Method) []Method {
+	methods := make([]Method, 0, len(m))
+	for _, method := range m {
+		methods = append(methods, method)
+	}
+
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Name < methods[j].Name
+	})
+
+	return methods
+}
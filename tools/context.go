@@ -0,0 +1,72 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The build tag is set ahead of the license, as build constraints may only be preceded by line
+// comments
+var contextMethods = fmt.Sprintf(`// +build softlayer_context
+
+%s
+
+%s
+
+package services
+
+{{range .}}{{$base := .Name|removePrefix}}{{$rawBase := .Name}}{{range .Methods}}
+	// {{.Name|titleCase}}WithContext is {{.Name|titleCase}}, returning ctx.Err() as soon as ctx is done.
+	// See session.Session.DoRequestWithContext.{{.Deprecated|deprecatedDoc}}
+	func (r {{$base}}) {{.Name|titleCase}}WithContext(ctx context.Context, {{methodParams .}}) ({{methodResults .}}) {
+		{{if .Type|eq "void"}}var resp datatypes.Void
+		{{end}}{{if or (eq .Name "placeOrder") (eq .Name "verifyOrder")}}err = datatypes.SetComplexType(orderData)
+		if err != nil {
+			return
+		}
+		{{end}}{{if len .Parameters | lt 0}}params := []interface{}{
+			{{range .Parameters}}{{.Name|removeReserved}},
+			{{end}}
+		}
+		{{end}}err = r.Session.DoRequestWithContext(ctx, "{{$rawBase}}", "{{.Name}}", {{if len .Parameters | lt 0}}params{{else}}nil{{end}}, &r.Options, &resp)
+	return
+	}
+	{{end}}
+{{end}}
+`, license, codegenWarning)
+
+// writeContextMethods writes the context-aware variants of the service methods to
+// services/<prefix>_context.go. They are only built with the softlayer_context build tag, so
+// that they can be adopted without changing the signatures of the existing methods.
+func writeContextMethods(base string, services []Type, ts string) error {
+	byPrefix := map[string][]Type{}
+	for _, service := range services {
+		prefix := strings.Split(RemovePrefix(service.Name), "_")[0]
+		byPrefix[prefix] = append(byPrefix[prefix], service)
+	}
+
+	for prefix, services := range byPrefix {
+		err := writeGoFile(base, "services", prefix+"_context", services, ts)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+)
+
+// Deprecations lists the deprecated types, properties (SoftLayer_Type.property) and methods
+// (SoftLayer_Service::method) of the generated code
+type Deprecations struct {
+	Types      []string `json:"types"`
+	Properties []string `json:"properties"`
+	Methods    []string `json:"methods"`
+}
+
+// writeDeprecations writes the deprecated types, properties and methods of the generated code
+// to deprecations.json, at the root of the project
+func writeDeprecations(base string, types []Type, services []Type) error {
+	deprecations := Deprecations{
+		Types:      []string{},
+		Properties: []string{},
+		Methods:    []string{},
+	}
+
+	for _, t := range types {
+		if t.Deprecated {
+			deprecations.Types = append(deprecations.Types, t.Name)
+		}
+
+		for _, p := range t.Properties {
+			if p.Deprecated {
+				deprecations.Properties = append(deprecations.Properties, t.Name+"."+p.Name)
+			}
+		}
+	}
+
+	// Methods inherited from a base service are listed for each service they are generated for
+	for _, service := range services {
+		for _, m := range service.Methods {
+			if m.Deprecated {
+				deprecations.Methods = append(deprecations.Methods, service.Name+"::"+m.Name)
+			}
+		}
+	}
+
+	sort.Strings(deprecations.Properties)
+	sort.Strings(deprecations.Methods)
+
+	b, err := json.MarshalIndent(deprecations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error marshaling deprecations: %s", err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(base, "deprecations.json"), append(b, '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("Error creating file: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+var enums = fmt.Sprintf(`%s
+
+%s
+
+package datatypes
+
+{{range .}}{{$type := .Name}}{{range .Properties}}{{if .Enum}}{{$property := .Name}}
+// Values of {{$type|removePrefix}}.{{.Name|titleCase}}
+const (
+	{{range .Enum}}{{enumConst $type $property .}} = {{printf "%%q" .}}
+	{{end}}
+)
+{{end}}{{end}}{{end}}
+`, license, codegenWarning)
+
+// Sentences of the documentation introducing the values of a property
+var enumIntro = regexp.MustCompile(`(?i)(?:possible|valid|allowed|accepted|acceptable) values(?: are| include)?:?`)
+
+// Quoted values, as in: Valid values are "SOURCE_IP"
+var enumQuoted = regexp.MustCompile(`"([^"]+)"|'([^']+)'`)
+
+// Bulleted values, as in: * ABSOLUTE - Force the group to be set at...
+var enumBullet = regexp.MustCompile(`(?m)^\s*\*\s+(.+?)\s+-\s`)
+
+var enumValue = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+var nonAlphanumeric = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// addEnums fills the enum values of the string properties of the datatypes. They are scraped from
+// the documentation of the properties, when it lists them, and read from overrideFile when one is
+// provided. The override file maps datatype names to property names to their values, which replace
+// the scraped ones. An empty list of values drops a property's scraped values. Override values which
+// would be declared as the same constant (e.g. "ABUSE-DISCONNECT" and "ABUSE_DISCONNECT") are
+// reported as an error.
+func addEnums(meta map[string]Type, overrideFile string) error {
+	for _, t := range meta {
+		for name, p := range t.Properties {
+			if p.Type != "string" && p.Type != "enum" {
+				continue
+			}
+
+			p.Enum = scrapeEnum(p.Doc)
+			t.Properties[name] = p
+		}
+	}
+
+	if overrideFile == "" {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile(overrideFile)
+	if err != nil {
+		return fmt.Errorf("Error reading enum override file: %s", err)
+	}
+
+	var overrides map[string]map[string][]string
+	err = json.Unmarshal(b, &overrides)
+	if err != nil {
+		return fmt.Errorf("Error unmarshaling enum override file: %s", err)
+	}
+
+	for typeName, values := range overrides {
+		t, ok := meta[typeName]
+		if !ok {
+			return fmt.Errorf("Unknown datatype %s in enum override file", typeName)
+		}
+
+		for name, enum := range values {
+			p, ok := t.Properties[name]
+			if !ok {
+				return fmt.Errorf("Unknown property %s.%s in enum override file", typeName, name)
+			}
+
+			names := map[string]string{}
+			for _, value := range enum {
+				if !enumValue.MatchString(value) {
+					return fmt.Errorf("Invalid value %q for %s.%s in enum override file", value, typeName, name)
+				}
+
+				if other, ok := names[enumName(value)]; ok {
+					return fmt.Errorf("Values %q and %q of %s.%s in enum override file have the same constant name",
+						other, value, typeName, name)
+				}
+				names[enumName(value)] = value
+			}
+
+			p.Enum = enum
+			t.Properties[name] = p
+		}
+	}
+
+	return nil
+}
+
+// scrapeEnum returns the values listed by the documentation of a property, if any. Only lists
+// introduced as the possible or valid values are considered, and a list is discarded entirely
+// when any of its values does not look like an identifier. Of the values sharing a constant name,
+// only the first one is kept.
+func scrapeEnum(doc string) []string {
+	loc := enumIntro.FindStringIndex(doc)
+	if loc == nil {
+		return nil
+	}
+
+	rest := doc[loc[1]:]
+
+	var values []string
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(rest), "*"):
+		for _, match := range enumBullet.FindAllStringSubmatch(rest, -1) {
+			values = append(values, strings.Split(match[1], " and ")...)
+		}
+	default:
+		// The list ends with the sentence
+		if end := strings.Index(rest, ". "); end >= 0 {
+			rest = rest[:end]
+		}
+		rest = strings.TrimSuffix(strings.TrimSpace(rest), ".")
+
+		if quoted := enumQuoted.FindAllStringSubmatch(rest, -1); len(quoted) > 0 {
+			for _, match := range quoted {
+				values = append(values, match[1]+match[2])
+			}
+		} else {
+			for _, value := range strings.Split(rest, ",") {
+				value = strings.TrimSpace(value)
+				value = strings.TrimPrefix(value, "and ")
+				value = strings.TrimPrefix(value, "or ")
+				values = append(values, value)
+			}
+		}
+	}
+
+	enum := []string{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if !enumValue.MatchString(value) {
+			return nil
+		}
+
+		if !seen[enumName(value)] {
+			enum = append(enum, value)
+			seen[enumName(value)] = true
+		}
+	}
+
+	if len(enum) == 0 {
+		return nil
+	}
+
+	return enum
+}
+
+// Returns the name of the constant of an enum value, e.g. Network_Component_Status_USER_OFF
+func EnumConst(args ...interface{}) string {
+	typeName := args[0].(string)
+	property := args[1].(string)
+	value := args[2].(string)
+
+	return RemovePrefix(typeName) + "_" + strings.Title(property) + "_" + enumName(value)
+}
+
+// enumName returns the part of the name of the constant of an enum value derived from the value,
+// e.g. USER_OFF for "USER-OFF"
+func enumName(value string) string {
+	return strings.Trim(nonAlphanumeric.ReplaceAllString(value, "_"), "_")
+}
@@ -0,0 +1,155 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var examples = fmt.Sprintf(`%s
+
+%s
+
+package services_test
+
+{{range .}}{{$base := .Service|removePrefix}}
+// Calls {{.Service}}::{{.Method.Name}}
+func ExampleGet{{$base|desnake}}Service_{{.Method.Name}}() {
+	sess := session.New()
+	service := services.Get{{$base|desnake}}Service(sess){{if .Id}}.Id(123456){{end}}{{if .Mask}}.
+		Mask("{{.Mask}}"){{end}}{{if .Filter}}.
+		Filter(filter.Build({{.Filter}})){{end}}
+
+	{{if .Method.Type|ne "void"}}resp, {{end}}err := service.{{.Method.Name|titleCase}}({{.Args}})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}{{if .Method.Type|ne "void"}}
+
+	fmt.Println(resp){{end}}
+}
+{{end}}
+`, license, codegenWarning)
+
+// Service methods for which examples are generated, and whether they are called on an object
+var exampleMethods = []struct {
+	Service string
+	Method  string
+	Id      bool
+}{
+	{"SoftLayer_Account", "getObject", false},
+	{"SoftLayer_Account", "getVirtualGuests", false},
+	{"SoftLayer_Product_Order", "placeOrder", false},
+	{"SoftLayer_Product_Order", "verifyOrder", false},
+	{"SoftLayer_Virtual_Guest", "getObject", true},
+}
+
+// Number of properties selected by the object mask of the examples
+const exampleMaskSize = 4
+
+// Example is a call to a service method, from which an example is generated
+type Example struct {
+	Service string
+	Method  Method
+	Id      bool
+	Mask    string
+	Filter  string
+	Args    string
+}
+
+// writeExamples writes the examples of the curated service methods to
+// services/<service>_example_test.go. The methods missing from the generated services are skipped.
+func writeExamples(base string, services []Type, meta map[string]Type, ts string) error {
+	generated := map[string]Type{}
+	for _, service := range services {
+		generated[service.Name] = service
+	}
+
+	byService := map[string][]Example{}
+	for _, e := range exampleMethods {
+		service, ok := generated[e.Service]
+		if !ok {
+			continue
+		}
+
+		method, ok := service.Methods[e.Method]
+		if !ok {
+			continue
+		}
+
+		byService[e.Service] = append(byService[e.Service], newExample(service, method, e.Id, meta))
+	}
+
+	for name, examples := range byService {
+		err := writeGoFile(base, "services", strings.ToLower(RemovePrefix(name))+"_example_test", examples, ts)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newExample(service Type, method Method, id bool, meta map[string]Type) Example {
+	example := Example{
+		Service: service.Name,
+		Method:  method,
+		Id:      id,
+	}
+
+	args := []string{}
+	for _, p := range method.Parameters {
+		if strings.HasPrefix(p.Type, "SoftLayer_Container_Product_Order") {
+			args = append(args, "&datatypes."+RemovePrefix(p.Type)+"{}")
+		} else {
+			args = append(args, "nil")
+		}
+	}
+	example.Args = strings.Join(args, ", ")
+
+	// Masks and filters are only shown for the methods retrieving objects
+	returned, ok := meta[method.Type]
+	if !ok || !strings.HasPrefix(method.Name, "get") {
+		return example
+	}
+
+	local := []string{}
+	for name, p := range returned.Properties {
+		if !IsDatatype(p.Type) && !p.TypeArray {
+			local = append(local, name)
+		}
+	}
+	sort.Strings(local)
+
+	if len(local) > exampleMaskSize {
+		local = local[:exampleMaskSize]
+	}
+	example.Mask = strings.Join(local, ",")
+
+	// Relational getters filter on the relational property of the service
+	property := strings.TrimPrefix(method.Name, "get")
+	property = strings.ToLower(property[:1]) + property[1:]
+
+	_, hasId := returned.Properties["id"]
+	if p, ok := service.Properties[property]; ok && p.TypeArray && hasId {
+		example.Filter = fmt.Sprintf("paths.%s{}.%s().Id().Eq(123456)", RemovePrefix(service.Name), strings.Title(property))
+	}
+
+	return example
+}
@@ -0,0 +1,183 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import "fmt"
+
+var fakeCalls = fmt.Sprintf(`%s
+
+%s
+
+package fakes
+
+import (
+	"sync"
+
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// Call is a call made to a method of a fake service. Method is the name of the method in the
+// API (e.g. getObject), and Args are the arguments it was called with.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// calls records the calls made to a fake service
+type calls struct {
+	mutex sync.Mutex
+	calls []Call
+}
+
+func (c *calls) record(method string, args ...interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.calls = append(c.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the calls made to the fake service, in order
+func (c *calls) Calls() []Call {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]Call{}, c.calls...)
+}
+
+// CallCount returns the number of calls made to a method of the fake service
+func (c *calls) CallCount(method string) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	count := 0
+	for _, call := range c.calls {
+		if call.Method == method {
+			count++
+		}
+	}
+
+	return count
+}
+
+// options records the options set on a fake service through its chaining methods
+type options struct {
+	mutex   sync.Mutex
+	options sl.Options
+}
+
+func (o *options) setId(id int) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.options.Id = &id
+}
+
+func (o *options) setMask(mask string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.options.Mask = mask
+}
+
+func (o *options) setFilter(filter string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.options.Filter = filter
+}
+
+func (o *options) setLimit(limit int) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.options.Limit = &limit
+}
+
+func (o *options) setOffset(offset int) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.options.Offset = &offset
+}
+
+// Options returns the id, mask, filter, limit and offset last set on the fake service
+func (o *options) Options() sl.Options {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.options
+}
+`, license, codegenWarning)
+
+var fakes = fmt.Sprintf(`%s
+
+%s
+
+package fakes
+
+{{range .}}{{$base := .Name|removePrefix}}
+	// {{$base}} is a fake implementation of services.{{$base}}Interface. Each method records its
+	// calls, and returns the results of its stub when one is set, or zero values otherwise. The
+	// chaining methods record the options they set, and return the fake itself.
+	type {{$base}} struct {
+		calls
+		options
+
+		{{range .Methods}}{{.Name|titleCase}}Stub func({{methodParams .}}) ({{methodResults .}})
+		{{end}}
+	}
+
+	var _ services.{{$base}}Interface = &{{$base}}{}
+
+	func (f *{{$base}}) Id(id int) services.{{$base}}Interface {
+		f.setId(id)
+		return f
+	}
+
+	func (f *{{$base}}) Mask(mask string) services.{{$base}}Interface {
+		f.setMask(mask)
+		return f
+	}
+
+	func (f *{{$base}}) Filter(filter string) services.{{$base}}Interface {
+		f.setFilter(filter)
+		return f
+	}
+
+	func (f *{{$base}}) Limit(limit int) services.{{$base}}Interface {
+		f.setLimit(limit)
+		return f
+	}
+
+	func (f *{{$base}}) Offset(offset int) services.{{$base}}Interface {
+		f.setOffset(offset)
+		return f
+	}
+
+	{{range .Methods}}
+	func (f *{{$base}}) {{.Name|titleCase}}({{methodParams .}}) ({{methodResults .}}) {
+		f.record("{{.Name}}", {{range .Parameters}}{{.Name|removeReserved}}, {{end}})
+		if f.{{.Name|titleCase}}Stub != nil {
+			return f.{{.Name|titleCase}}Stub({{range .Parameters}}{{.Name|removeReserved}}, {{end}})
+		}
+
+		return
+	}
+	{{end}}
+
+{{end}}
+`, license, codegenWarning)
@@ -0,0 +1,307 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var goldenRunner = fmt.Sprintf(`%s
+
+%s
+
+package tests
+
+// Date of the fixtures of the dateTime properties
+var goldenTime = time.Date(2016, 10, 24, 12, 0, 0, 0, time.UTC)
+
+// goldenTest holds a datatype fixture, its JSON encoding, and the JSON encoding of the zero
+// value of the datatype
+type goldenTest struct {
+	name    string
+	fixture interface{}
+	golden  string
+	zero    string
+}
+
+// runGoldenTests checks that each fixture and the zero value of its datatype encode to their
+// golden JSON, and that the golden JSON decodes back to the fixture
+func runGoldenTests(t *testing.T, tests []goldenTest) {
+	for _, test := range tests {
+		typ := reflect.TypeOf(test.fixture).Elem()
+
+		encoded, err := json.Marshal(test.fixture)
+		if err != nil {
+			t.Errorf("Error encoding %%s fixture: %%s", test.name, err)
+			continue
+		}
+		assertSameJSON(t, test.name+" fixture", encoded, test.golden)
+
+		encoded, err = json.Marshal(reflect.New(typ).Interface())
+		if err != nil {
+			t.Errorf("Error encoding %%s zero value: %%s", test.name, err)
+			continue
+		}
+		assertSameJSON(t, test.name+" zero value", encoded, test.zero)
+
+		decoded := reflect.New(typ).Interface()
+		err = json.Unmarshal([]byte(test.golden), decoded)
+		if err != nil {
+			t.Errorf("Error decoding %%s golden JSON: %%s", test.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(decoded, test.fixture) {
+			t.Errorf("Expect %%s golden JSON to decode to the fixture, but decoded %%+v", test.name, decoded)
+		}
+	}
+}
+
+func assertSameJSON(t *testing.T, name string, actual []byte, expected string) {
+	var actualValue, expectedValue interface{}
+
+	if err := json.Unmarshal(actual, &actualValue); err != nil {
+		t.Errorf("Error decoding %%s JSON: %%s", name, err)
+		return
+	}
+
+	if err := json.Unmarshal([]byte(expected), &expectedValue); err != nil {
+		t.Errorf("Error decoding %%s golden JSON: %%s", name, err)
+		return
+	}
+
+	if !reflect.DeepEqual(actualValue, expectedValue) {
+		t.Errorf("Expect %%s to encode to %%s, but was %%s", name, expected, actual)
+	}
+}
+`, license, codegenWarning)
+
+var golden = fmt.Sprintf(`%s
+
+%s
+
+package tests
+
+func TestGolden{{.Prefix}}(t *testing.T) {
+	tests := []goldenTest{
+		{{range .Tests}}{
+			name:    "{{.Name}}",
+			fixture: &{{.Fixture}},
+			golden:  `+"`{{.Golden}}`"+`,
+			zero:    `+"`{{.Zero}}`"+`,
+		},
+		{{end}}
+	}
+
+	runGoldenTests(t, tests)
+}
+`, license, codegenWarning)
+
+// GoldenTest is the fixture of a datatype, along with its expected JSON encoding, from which a
+// serialization test is generated
+type GoldenTest struct {
+	Name    string
+	Fixture string
+	Golden  string
+	Zero    string
+}
+
+// Fixtures of the properties of built-in types, as Go expressions for single values and arrays,
+// and their JSON encoding
+var goldenValues = map[string]struct {
+	single string
+	array  string
+	json   interface{}
+}{
+	"int":          {"sl.Int(1)", "[]int{1}", 1},
+	"unsignedInt":  {"sl.Uint(1)", "[]uint{1}", 1},
+	"unsignedLong": {"sl.Uint(1)", "[]uint{1}", 1},
+	"string":       {`sl.String("string")`, `[]string{"string"}`, "string"},
+	"json":         {`sl.String("string")`, `[]string{"string"}`, "string"},
+	"enum":         {`sl.String("string")`, `[]string{"string"}`, "string"},
+	"boolean":      {"sl.Bool(true)", "[]bool{true}", true},
+	"dateTime":     {"sl.Time(goldenTime)", "[]datatypes.Time{{Time: goldenTime}}", "2016-10-24T12:00:00Z"},
+	"decimal":      {"sl.Float(1.5)", "[]datatypes.Float64{1.5}", 1.5},
+	"float":        {"sl.Float(1.5)", "[]datatypes.Float64{1.5}", 1.5},
+	"base64Binary": {`&[]byte{'d', 'a', 't', 'a'}`, `[][]byte{{'d', 'a', 't', 'a'}}`, "ZGF0YQ=="},
+}
+
+// writeGoldenTests writes a serialization test for each datatype to tests/<prefix>_golden_test.go,
+// along with the code running them in tests/golden_test.go
+func writeGoldenTests(base string, types []Type, meta map[string]Type, ts string) error {
+	if len(types) == 0 {
+		return nil
+	}
+
+	err := writeGoFile(base, "tests", "golden_test", nil, goldenRunner)
+	if err != nil {
+		return err
+	}
+
+	byPrefix := map[string][]GoldenTest{}
+	for _, t := range types {
+		prefix := strings.Split(RemovePrefix(t.Name), "_")[0]
+
+		test, err := newGoldenTest(t, meta)
+		if err != nil {
+			return err
+		}
+
+		byPrefix[prefix] = append(byPrefix[prefix], test)
+	}
+
+	for prefix, tests := range byPrefix {
+		data := struct {
+			Prefix string
+			Tests  []GoldenTest
+		}{prefix, tests}
+
+		err := writeGoFile(base, "tests", strings.ToLower(prefix)+"_golden_test", data, ts)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newGoldenTest(t Type, meta map[string]Type) (GoldenTest, error) {
+	fixture, encoded := goldenFixture(t, meta, map[string]bool{})
+
+	golden, err := json.Marshal(encoded)
+	if err != nil {
+		return GoldenTest{}, fmt.Errorf("Error marshaling golden JSON of %s: %s", t.Name, err)
+	}
+
+	zero, err := json.Marshal(goldenZero(t, meta, map[string]bool{}))
+	if err != nil {
+		return GoldenTest{}, fmt.Errorf("Error marshaling golden JSON of %s: %s", t.Name, err)
+	}
+
+	return GoldenTest{
+		Name:    RemovePrefix(t.Name),
+		Fixture: fixture,
+		Golden:  string(golden),
+		Zero:    string(zero),
+	}, nil
+}
+
+// goldenFixture returns a composite literal of a datatype with all its properties set, and its
+// JSON encoding. Relational properties are set to the zero value of the related datatype. The
+// properties shadowed by a same-named property of a subtype are left unset, as they are neither
+// encoded nor decoded.
+func goldenFixture(t Type, meta map[string]Type, shadowed map[string]bool) (string, map[string]interface{}) {
+	fields := []string{}
+	encoded := map[string]interface{}{}
+
+	names := []string{}
+	for name := range t.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	own := map[string]bool{}
+	for name := range shadowed {
+		own[name] = true
+	}
+
+	for _, name := range names {
+		p := t.Properties[name]
+		if shadowed[p.Name] {
+			continue
+		}
+		own[p.Name] = true
+
+		var value string
+		var encodedValue interface{}
+
+		if related, ok := meta[p.Type]; ok {
+			zero := goldenZero(related, meta, map[string]bool{})
+			if p.TypeArray {
+				value = fmt.Sprintf("[]datatypes.%s{{}}", RemovePrefix(p.Type))
+				encodedValue = []interface{}{zero}
+			} else {
+				value = fmt.Sprintf("&datatypes.%s{}", RemovePrefix(p.Type))
+				encodedValue = zero
+			}
+		} else if v, ok := goldenValues[p.Type]; ok {
+			encodedValue = v.json
+			value = v.single
+			if p.TypeArray {
+				value = v.array
+				encodedValue = []interface{}{v.json}
+			}
+		} else {
+			// Left unset, as its Go type is unknown
+			continue
+		}
+
+		fields = append(fields, fmt.Sprintf("%s: %s", strings.Title(p.Name), value))
+		encoded[p.Name] = encodedValue
+	}
+
+	if base, ok := meta[t.Base]; ok {
+		baseFixture, baseEncoded := goldenFixture(base, meta, own)
+		if len(baseEncoded) > 0 {
+			fields = append([]string{fmt.Sprintf("%s: %s", RemovePrefix(base.Name), baseFixture)}, fields...)
+		}
+
+		for name, value := range baseEncoded {
+			encoded[name] = value
+		}
+	}
+
+	literal := fmt.Sprintf("datatypes.%s{\n%s,\n}", RemovePrefix(t.Name), strings.Join(fields, ",\n"))
+	if len(fields) == 0 {
+		literal = fmt.Sprintf("datatypes.%s{}", RemovePrefix(t.Name))
+	}
+
+	return literal, encoded
+}
+
+// goldenZero returns the JSON encoding of the zero value of a datatype, in which only the
+// properties without omitempty are encoded, as null
+func goldenZero(t Type, meta map[string]Type, shadowed map[string]bool) map[string]interface{} {
+	encoded := map[string]interface{}{}
+
+	own := map[string]bool{}
+	for name := range shadowed {
+		own[name] = true
+	}
+
+	for _, p := range t.Properties {
+		if shadowed[p.Name] {
+			continue
+		}
+		own[p.Name] = true
+
+		if !strings.HasSuffix(Tags(p.Name), ",omitempty") {
+			encoded[p.Name] = nil
+		}
+	}
+
+	if base, ok := meta[t.Base]; ok {
+		for name, value := range goldenZero(base, meta, own) {
+			encoded[name] = value
+		}
+	}
+
+	return encoded
+}
@@ -18,17 +18,19 @@ package main
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"go/format"
 	"io/ioutil"
-	"net/http"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"text/template"
 
+	"github.com/softlayer/softlayer-go/sl"
 	"golang.org/x/tools/imports"
 )
 
@@ -40,14 +42,17 @@ type Type struct {
 	ServiceDoc string              `json:"serviceDoc"`
 	Methods    map[string]Method   `json:"methods"`
 	NoService  bool                `json:"noservice"`
+	Deprecated bool                `json:"deprecated"`
 }
 
 type Property struct {
-	Name      string `json:"name"`
-	Type      string `json:"type"`
-	TypeArray bool   `json:"typeArray"`
-	Form      string `json:"form"`
-	Doc       string `json:"doc"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	TypeArray  bool     `json:"typeArray"`
+	Form       string   `json:"form"`
+	Doc        string   `json:"doc"`
+	Enum       []string `json:"enum"`
+	Deprecated bool     `json:"deprecated"`
 }
 
 type Method struct {
@@ -61,6 +66,7 @@ type Method struct {
 	Filterable bool        `json:"filterable"`
 	Maskable   bool        `json:"maskable"`
 	Parameters []Parameter `json:"parameters"`
+	Deprecated bool        `json:"deprecated"`
 }
 
 type Parameter struct {
@@ -71,6 +77,8 @@ type Parameter struct {
 	DefaultValue interface{} `json:"defaultValue"`
 }
 
+const sldnReference = "https://sldn.softlayer.com/reference"
+
 // Define custom template functions
 var fMap = template.FuncMap{
 	"convertType":     ConvertType,         // Converts SoftLayer types to Go types
@@ -79,8 +87,16 @@ var fMap = template.FuncMap{
 	"titleCase":       strings.Title,       // TitleCase the argument
 	"desnake":         Desnake,             // Remove '_' from Snake_Case
 	"goDoc":           GoDoc,               // Format a go doc string
+	"deprecatedDoc":   DeprecatedDoc,       // Deprecation notice to append to a go doc string
+	"sldnDatatype":    SLDNDatatype,        // SLDN reference link of a datatype
+	"sldnService":     SLDNService,         // SLDN reference link of a service
+	"sldnMethod":      SLDNMethod,          // SLDN reference link of a service method
 	"tags":            Tags,                // Remove omitempty tags if required
 	"phraseMethodArg": phraseMethodArg,     // Get proper phrase for method argument
+	"enumConst":       EnumConst,           // Name of the constant of an enum value
+	"methodParams":    MethodParams,        // Parameters of a service method
+	"methodResults":   MethodResults,       // Named results of a service method
+	"isDatatype":      IsDatatype,          // Whether a type is an API datatype
 }
 
 var datatype = fmt.Sprintf(`%s
@@ -89,11 +105,11 @@ var datatype = fmt.Sprintf(`%s
 
 package datatypes
 
-{{range .}}{{.TypeDoc|goDoc}}
+{{range .}}{{.TypeDoc|goDoc}}{{.Name|sldnDatatype}}{{.Deprecated|deprecatedDoc}}
 type {{.Name|removePrefix}} struct {
 	{{.Base|removePrefix}}
 
-	{{$base := .Name}}{{range .Properties}}{{.Doc|goDoc}}
+	{{$base := .Name}}{{range .Properties}}{{.Doc|goDoc}}{{.Deprecated|deprecatedDoc}}
 	{{.Name|titleCase}} {{if .TypeArray}}[]{{else}}*{{end}}{{convertType .Type "datatypes" $base .Name}}`+
 	"`json:\"{{.Name|tags}}\" xmlrpc:\"{{.Name|tags}}\"`"+`
 
@@ -114,17 +130,61 @@ import (
 	"strings"
 )
 
-{{range .}}{{$base := .Name|removePrefix}}{{.TypeDoc|goDoc}}
+{{range .}}{{$base := .Name|removePrefix}}{{.TypeDoc|goDoc}}{{.Name|sldnService}}{{.Deprecated|deprecatedDoc}}
 	type {{$base}} struct {
 		Session *session.Session
 		Options sl.Options
 	}
 
+	// {{$base}}Interface is implemented by the service returned by Get{{$base | desnake}}ServiceInterface,
+	// and by its fake in the fakes package
+	type {{$base}}Interface interface {
+		Id(id int) {{$base}}Interface
+		Mask(mask string) {{$base}}Interface
+		Filter(filter string) {{$base}}Interface
+		Limit(limit int) {{$base}}Interface
+		Offset(offset int) {{$base}}Interface
+
+		{{range .Methods}}{{.Name|titleCase}}({{methodParams .}}) ({{methodResults .}})
+		{{end}}
+	}
+
 	// Get{{$base | desnake}}Service returns an instance of the {{$base}} SoftLayer service
 	func Get{{$base | desnake}}Service(sess *session.Session) {{$base}} {
 		return {{$base}}{Session: sess}
 	}
 
+	// Get{{$base | desnake}}ServiceInterface returns an instance of the {{$base}} SoftLayer service,
+	// whose chaining methods return a {{$base}}Interface
+	func Get{{$base | desnake}}ServiceInterface(sess *session.Session) {{$base}}Interface {
+		return chained{{$base}}{Get{{$base | desnake}}Service(sess)}
+	}
+
+	// chained{{$base}} is a {{$base}} implementing {{$base}}Interface
+	type chained{{$base}} struct {
+		{{$base}}
+	}
+
+	func (r chained{{$base}}) Id(id int) {{$base}}Interface {
+		return chained{{$base}}{r.{{$base}}.Id(id)}
+	}
+
+	func (r chained{{$base}}) Mask(mask string) {{$base}}Interface {
+		return chained{{$base}}{r.{{$base}}.Mask(mask)}
+	}
+
+	func (r chained{{$base}}) Filter(filter string) {{$base}}Interface {
+		return chained{{$base}}{r.{{$base}}.Filter(filter)}
+	}
+
+	func (r chained{{$base}}) Limit(limit int) {{$base}}Interface {
+		return chained{{$base}}{r.{{$base}}.Limit(limit)}
+	}
+
+	func (r chained{{$base}}) Offset(offset int) {{$base}}Interface {
+		return chained{{$base}}{r.{{$base}}.Offset(offset)}
+	}
+
 	func (r {{$base}}) Id(id int) {{$base}} {
 		r.Options.Id = &id
 		return r
@@ -154,8 +214,8 @@ import (
 		return r
 	}
 
-	{{$rawBase := .Name}}{{range .Methods}}{{$methodName := .Name}}{{.Doc|goDoc}}
-	func (r {{$base}}) {{.Name|titleCase}}({{range .Parameters}}{{phraseMethodArg $methodName .Name .TypeArray .Type}}{{end}}) ({{if .Type|ne "void"}}resp {{if .TypeArray}}[]{{end}}{{convertType .Type "services"}}, {{end}}err error) {
+	{{$rawBase := .Name}}{{range .Methods}}{{.Doc|goDoc}}{{sldnMethod $rawBase .Name}}{{.Deprecated|deprecatedDoc}}
+	func (r {{$base}}) {{.Name|titleCase}}({{methodParams .}}) ({{methodResults .}}) {
 		{{if .Type|eq "void"}}var resp datatypes.Void
 		{{end}}{{if or (eq .Name "placeOrder") (eq .Name "verifyOrder")}}err = datatypes.SetComplexType(orderData)
 		if err != nil {
@@ -177,18 +237,74 @@ func generateAPI() {
 	var meta map[string]Type
 
 	flagset := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
-	outputPath := flag.String("o", ".", "the root of the go project to be refreshed")
+	outputPath := flagset.String("o", ".", "the root of the go project to be refreshed")
+	inputFile := flagset.String("i", "", "read the metadata from a local file instead of the metadata API")
+	cacheFile := flagset.String("cache", "", "store the fetched metadata in this file, and reuse it while unchanged upstream")
+	serviceList := flagset.String("services", "", "comma separated list of the only services to generate, along with their datatypes")
+	typeList := flagset.String("types", "", "comma separated list of the only datatypes to generate, along with their dependencies")
+	excludeList := flagset.String("exclude-services", "", "comma separated list of services not to generate")
+	enumFile := flagset.String("enums", "", "JSON file of enum values overriding the ones found in the documentation")
+	templateDir := flagset.String("templates", "", "directory of datatypes.tmpl, enums.tmpl, masks.tmpl, paths.tmpl, services.tmpl, context.tmpl, fakes.tmpl, examples.tmpl and golden.tmpl templates overriding the built-in ones")
+	withContext := flagset.Bool("context", false, "also generate context-aware variants of the service methods, built with the softlayer_context build tag")
 	flagset.Parse(os.Args[2:])
 
-	jsonResp, code, err := makeHttpRequest("https://api.softlayer.com/metadata/v3.1", "GET", new(bytes.Buffer))
+	datatypeTemplate, err := loadTemplate(*templateDir, "datatypes", datatype)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	serviceTemplate, err := loadTemplate(*templateDir, "services", services)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	contextTemplate, err := loadTemplate(*templateDir, "context", contextMethods)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fakeTemplate, err := loadTemplate(*templateDir, "fakes", fakes)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 
+	maskTemplate, err := loadTemplate(*templateDir, "masks", masks)
 	if err != nil {
-		fmt.Printf("Error retrieving metadata API: %s", err)
+		fmt.Println(err)
 		os.Exit(1)
 	}
 
-	if code != 200 {
-		fmt.Printf("Unexpected HTTP status code received while retrieving metadata API: %d", code)
+	pathTemplate, err := loadTemplate(*templateDir, "paths", paths)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	exampleTemplate, err := loadTemplate(*templateDir, "examples", examples)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	goldenTemplate, err := loadTemplate(*templateDir, "golden", golden)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	enumTemplate, err := loadTemplate(*templateDir, "enums", enums)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	jsonResp, err := loadMetadata(*inputFile, *cacheFile)
+	if err != nil {
+		fmt.Println(err)
 		os.Exit(1)
 	}
 
@@ -198,6 +314,12 @@ func generateAPI() {
 		os.Exit(1)
 	}
 
+	err = addEnums(meta, *enumFile)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	// Build an array of Types, sorted by name
 	// This will ensure consistency in the order that code is later emitted
 	keys := getSortedKeys(meta)
@@ -226,12 +348,109 @@ func generateAPI() {
 		fixReturnType(&sortedServices[i])
 	}
 
-	err = writePackage(*outputPath, "datatypes", sortedTypes, datatype)
+	sortedTypes, sortedServices, err = selectTypes(
+		sortedTypes,
+		sortedServices,
+		meta,
+		splitList(*serviceList),
+		splitList(*typeList),
+		splitList(*excludeList),
+	)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// Files left from a previous generation would otherwise remain next to a subset of the API, and
+	// refer to datatypes which are no longer generated
+	for _, pkg := range []string{"datatypes", "masks", "paths", "services", "fakes", "tests"} {
+		err = removeGeneratedFiles(*outputPath, pkg)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	err = writePackage(*outputPath, "datatypes", sortedTypes, datatypeTemplate)
 	if err != nil {
 		fmt.Printf("Error writing to file: %s", err)
 	}
 
-	err = writePackage(*outputPath, "services", sortedServices, services)
+	if len(sortedTypes) > 0 {
+		err = writeGoFile(*outputPath, "datatypes", "enums", sortedTypes, enumTemplate)
+		if err != nil {
+			fmt.Printf("Error writing to file: %s", err)
+		}
+	}
+
+	if len(sortedTypes) > 0 {
+		err = writeGoFile(*outputPath, "masks", "relational", nil, maskRelational)
+		if err != nil {
+			fmt.Printf("Error writing to file: %s", err)
+		}
+	}
+
+	err = writePackage(*outputPath, "masks", sortedTypes, maskTemplate)
+	if err != nil {
+		fmt.Printf("Error writing to file: %s", err)
+	}
+
+	if len(sortedTypes) > 0 {
+		err = writeGoFile(*outputPath, "paths", "join", nil, pathJoin)
+		if err != nil {
+			fmt.Printf("Error writing to file: %s", err)
+		}
+	}
+
+	err = writePackage(*outputPath, "paths", sortedTypes, pathTemplate)
+	if err != nil {
+		fmt.Printf("Error writing to file: %s", err)
+	}
+
+	err = writePackage(*outputPath, "services", sortedServices, serviceTemplate)
+	if err != nil {
+		fmt.Printf("Error writing to file: %s", err)
+	}
+
+	if *withContext {
+		err = writeContextMethods(*outputPath, sortedServices, contextTemplate)
+		if err != nil {
+			fmt.Printf("Error writing to file: %s", err)
+		}
+	}
+
+	err = writeGoldenTests(*outputPath, sortedTypes, meta, goldenTemplate)
+	if err != nil {
+		fmt.Printf("Error writing to file: %s", err)
+	}
+
+	err = writeExamples(*outputPath, sortedServices, meta, exampleTemplate)
+	if err != nil {
+		fmt.Printf("Error writing to file: %s", err)
+	}
+
+	err = writeVersionFile(*outputPath, sl.Version, sl.APIMetadataInfo{
+		Version: metadataVersion,
+		Digest:  fmt.Sprintf("%x", sha256.Sum256(jsonResp)),
+		Date:    metadataDate(*inputFile, *cacheFile).UTC().Format("2006-01-02"),
+	})
+	if err != nil {
+		fmt.Printf("Error writing to file: %s", err)
+	}
+
+	err = writeDeprecations(*outputPath, sortedTypes, sortedServices)
+	if err != nil {
+		fmt.Printf("Error writing to file: %s", err)
+	}
+
+	if len(sortedServices) > 0 {
+		err = writeGoFile(*outputPath, "fakes", "calls", nil, fakeCalls)
+		if err != nil {
+			fmt.Printf("Error writing to file: %s", err)
+		}
+	}
+
+	err = writePackage(*outputPath, "fakes", sortedServices, fakeTemplate)
 	if err != nil {
 		fmt.Printf("Error writing to file: %s", err)
 	}
@@ -324,6 +543,58 @@ func GoDoc(args ...interface{}) string {
 	return "// " + strings.Replace(s, "\n", "\n// ", -1)
 }
 
+// Returns the parameters of a service method, as declared by its signature
+func MethodParams(args ...interface{}) string {
+	m := args[0].(Method)
+
+	params := ""
+	for _, p := range m.Parameters {
+		params += phraseMethodArg(m.Name, p.Name, p.TypeArray, p.Type)
+	}
+
+	return params
+}
+
+// Returns the named results of a service method, as declared by its signature
+func MethodResults(args ...interface{}) string {
+	m := args[0].(Method)
+
+	if m.Type == "void" {
+		return "err error"
+	}
+
+	refPrefix := ""
+	if m.TypeArray {
+		refPrefix = "[]"
+	}
+
+	return fmt.Sprintf("resp %s%s, err error", refPrefix, ConvertType(m.Type, "services"))
+}
+
+// Returns the link to the SLDN reference of a datatype, to append to its doc
+func SLDNDatatype(args ...interface{}) string {
+	return "\n//\n// " + sldnReference + "/datatypes/" + args[0].(string)
+}
+
+// Returns the link to the SLDN reference of a service, to append to its doc
+func SLDNService(args ...interface{}) string {
+	return "\n//\n// " + sldnReference + "/services/" + args[0].(string)
+}
+
+// Returns the link to the SLDN reference of a service method, to append to its doc
+func SLDNMethod(args ...interface{}) string {
+	return "\n//\n// " + sldnReference + "/services/" + args[0].(string) + "/" + args[1].(string)
+}
+
+// Returns the deprecation notice of a deprecated type, property or method, to append to its doc
+func DeprecatedDoc(args ...interface{}) string {
+	if !args[0].(bool) {
+		return ""
+	}
+
+	return "\n//\n// Deprecated: This is deprecated in the SoftLayer API, and may be removed from it."
+}
+
 // Remove omitempty tags if required
 func Tags(args ...interface{}) string {
 	n := args[0].(string)
@@ -452,6 +723,32 @@ func getBaseMethods(s Type, typeMap map[string]Type) map[string]Method {
 	return methods
 }
 
+// loadTemplate returns the template of a package. It is read from <pkg>.tmpl in dir when the file
+// exists, and is the built-in template otherwise.
+func loadTemplate(dir string, pkg string, builtin string) (string, error) {
+	if dir == "" {
+		return builtin, nil
+	}
+
+	filename := filepath.Join(dir, pkg+".tmpl")
+
+	b, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return builtin, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("Error reading template: %s", err)
+	}
+
+	// Report syntax errors before anything is generated
+	_, err = template.New(pkg).Funcs(fMap).Parse(string(b))
+	if err != nil {
+		return "", fmt.Errorf("Error parsing template %s: %s", filename, err)
+	}
+
+	return string(b), nil
+}
+
 func getSortedKeys(m map[string]Type) []string {
 	keys := make([]string, 0, len(m))
 	for key := range m {
@@ -466,6 +763,11 @@ func writePackage(base string, pkg string, meta []Type, ts string) error {
 	var currPrefix string
 	var start int
 
+	// Nothing to write when every type of the package was left out of the generation
+	if len(meta) == 0 {
+		return nil
+	}
+
 	for i, t := range meta {
 		components := strings.Split(RemovePrefix(t.Name), "_")
 
@@ -490,14 +792,48 @@ func writePackage(base string, pkg string, meta []Type, ts string) error {
 	return nil
 }
 
+// Removes the go source files of a package which were generated, leaving the hand-written ones
+func removeGeneratedFiles(base string, pkg string) error {
+	files, err := filepath.Glob(filepath.Join(base, pkg, "*.go"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("Error reading file: %s", err)
+		}
+
+		if !bytes.Contains(src, []byte(codegenWarning)) {
+			continue
+		}
+
+		err = os.Remove(file)
+		if err != nil {
+			return fmt.Errorf("Error removing file: %s", err)
+		}
+	}
+
+	return nil
+}
+
 // Executes a template against the metadata structure, and generates a go source file with the result
-func writeGoFile(base string, pkg string, name string, meta []Type, ts string) error {
+func writeGoFile(base string, pkg string, name string, meta interface{}, ts string) error {
 	filename := base + "/" + pkg + "/" + strings.ToLower(name) + ".go"
 
+	err := os.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return fmt.Errorf("Error creating directory: %s", err)
+	}
+
 	// Generate the source
 	var buf bytes.Buffer
 	t := template.New(pkg).Funcs(fMap)
-	template.Must(t.Parse(ts)).Execute(&buf, meta)
+	err = template.Must(t.Parse(ts)).Execute(&buf, meta)
+	if err != nil {
+		return fmt.Errorf("Error executing template: %s", err)
+	}
 
 	/*if pkg == "services" && name == "Account"{
 		fmt.Println(string(buf.String()))
@@ -525,26 +861,3 @@ func writeGoFile(base string, pkg string, name string, meta []Type, ts string) e
 
 	return nil
 }
-
-func makeHttpRequest(url string, requestType string, requestBody *bytes.Buffer) ([]byte, int, error) {
-	client := http.DefaultClient
-
-	req, err := http.NewRequest(requestType, url, requestBody)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, 520, err
-	}
-
-	defer resp.Body.Close()
-
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, resp.StatusCode, err
-	}
-
-	return responseBody, resp.StatusCode, nil
-}
@@ -27,6 +27,8 @@ Commands:
 
 	generate: Generate the SDK from the API metadata
 
+	changelog: List the API changes since a previous metadata snapshot
+
 	version: library version management
 `
 
@@ -39,6 +41,8 @@ func main() {
 	switch os.Args[1] {
 	case "generate":
 		generateAPI()
+	case "changelog":
+		changelog()
 	case "version":
 		version()
 	default:
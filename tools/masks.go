@@ -0,0 +1,84 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var maskRelational = fmt.Sprintf(`%s
+
+%s
+
+package masks
+
+import "strings"
+
+// relational returns the mask of a relational property selecting the provided properties, or
+// its local properties when none is provided
+func relational(name string, properties []string) string {
+	if len(properties) == 0 {
+		return name
+	}
+
+	return name + "[" + strings.Join(properties, ",") + "]"
+}
+`, license, codegenWarning)
+
+var masks = fmt.Sprintf(`%s
+
+%s
+
+package masks
+
+{{range .}}{{$base := .Name|removePrefix}}
+	// {{$base}} is an object mask of {{.Name}}.
+	// Each field selects a property of the datatype, and the fields of the relational properties
+	// select the properties of the related datatype.
+	type {{$base}} struct {
+		{{if .Base}}{{.Base|removePrefix}}
+
+		{{end}}{{range .Properties}}{{.Name|titleCase}} {{if isDatatype .Type}}*{{.Type|removePrefix}}{{else}}bool{{end}}
+		{{end}}
+	}
+
+	// String returns the object mask, as accepted by the Mask method of the services
+	func (m {{$base}}) String() string {
+		return strings.Join(m.maskProperties(), ",")
+	}
+
+	func (m {{$base}}) maskProperties() []string {
+		properties := {{if .Base}}m.{{.Base|removePrefix}}.maskProperties(){{else}}[]string{}{{end}}
+		{{range .Properties}}{{if isDatatype .Type}}if m.{{.Name|titleCase}} != nil {
+			properties = append(properties, relational("{{.Name}}", m.{{.Name|titleCase}}.maskProperties()))
+		}{{else}}if m.{{.Name|titleCase}} {
+			properties = append(properties, "{{.Name}}")
+		}{{end}}
+		{{end}}
+		return properties
+	}
+
+{{end}}
+`, license, codegenWarning)
+
+// Returns whether a type is a datatype of the API, rather than a built-in type
+func IsDatatype(args ...interface{}) bool {
+	t := args[0].(string)
+
+	return strings.HasPrefix(t, "SoftLayer_") || strings.HasPrefix(t, "McAfee_")
+}
@@ -0,0 +1,136 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Version of the metadata API the code is generated from
+const metadataVersion = "v3.1"
+
+const metadataURL = "https://api.softlayer.com/metadata/" + metadataVersion
+
+// loadMetadata returns the raw metadata JSON. It is read from inputFile when one is provided, and
+// fetched from the metadata API otherwise. When cacheFile is provided, the fetched metadata is
+// stored there, along with its ETag, and is only downloaded again once it changed upstream.
+func loadMetadata(inputFile string, cacheFile string) ([]byte, error) {
+	if inputFile != "" {
+		jsonResp, err := ioutil.ReadFile(inputFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading metadata file: %s", err)
+		}
+
+		return jsonResp, nil
+	}
+
+	if cacheFile == "" {
+		jsonResp, _, err := fetchMetadata("")
+		return jsonResp, err
+	}
+
+	etagFile := cacheFile + ".etag"
+
+	// The ETag is only sent when the cached metadata is still around
+	etag := ""
+	cached, err := ioutil.ReadFile(cacheFile)
+	if err == nil {
+		if b, err := ioutil.ReadFile(etagFile); err == nil {
+			etag = strings.TrimSpace(string(b))
+		}
+	}
+
+	jsonResp, etag, err := fetchMetadata(etag)
+	if err != nil {
+		return nil, err
+	}
+
+	// Not modified since it was cached
+	if jsonResp == nil {
+		fmt.Fprintf(os.Stderr, "Using cached metadata from %s\n", cacheFile)
+		return cached, nil
+	}
+
+	err = ioutil.WriteFile(cacheFile, jsonResp, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("Error writing metadata cache: %s", err)
+	}
+
+	err = ioutil.WriteFile(etagFile, []byte(etag+"\n"), 0644)
+	if err != nil {
+		return nil, fmt.Errorf("Error writing metadata cache: %s", err)
+	}
+
+	return jsonResp, nil
+}
+
+// metadataDate returns the date the metadata loaded by loadMetadata was retrieved: the modification
+// date of the file it was read from or cached in, or the current date when it was just fetched
+func metadataDate(inputFile string, cacheFile string) time.Time {
+	filename := inputFile
+	if filename == "" {
+		filename = cacheFile
+	}
+
+	if filename != "" {
+		if info, err := os.Stat(filename); err == nil {
+			return info.ModTime()
+		}
+	}
+
+	return time.Now()
+}
+
+// fetchMetadata retrieves the metadata from the metadata API, along with its ETag. When etag is
+// not empty, it is sent as a conditional request, and a nil result is returned if the metadata did
+// not change.
+func fetchMetadata(etag string) ([]byte, string, error) {
+	req, err := http.NewRequest("GET", metadataURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("Error retrieving metadata API: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if etag != "" && resp.StatusCode == http.StatusNotModified {
+		return nil, etag, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("Unexpected HTTP status code received while retrieving metadata API: %d", resp.StatusCode)
+	}
+
+	jsonResp, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("Error retrieving metadata API: %s", err)
+	}
+
+	return jsonResp, resp.Header.Get("ETag"), nil
+}
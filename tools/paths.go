@@ -0,0 +1,66 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import "fmt"
+
+var pathJoin = fmt.Sprintf(`%s
+
+%s
+
+package paths
+
+// join appends the name of a property to the path of its datatype
+func join(path string, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+`, license, codegenWarning)
+
+var paths = fmt.Sprintf(`%s
+
+%s
+
+package paths
+
+{{range .}}{{$base := .Name|removePrefix}}
+	// {{$base}} builds the object filter paths of the properties of {{.Name}}.
+	// The methods of the local properties return a filter on their path, and the methods of the
+	// relational properties the paths of the related datatype.
+	type {{$base}} struct {
+		{{if .Base}}{{.Base|removePrefix}}{{else}}path string{{end}}
+	}
+
+	func new{{$base}}(path string) {{$base}} {
+		return {{$base}}{ {{if .Base}}new{{.Base|removePrefix}}(path){{else}}path: path{{end}} }
+	}
+
+	{{range .Properties}}{{if isDatatype .Type}}
+	func (p {{$base}}) {{.Name|titleCase}}() {{.Type|removePrefix}} {
+		return new{{.Type|removePrefix}}(join(p.path, "{{.Name}}"))
+	}
+	{{else}}
+	func (p {{$base}}) {{.Name|titleCase}}() filter.Filter {
+		return filter.Path(join(p.path, "{{.Name}}"))
+	}
+	{{end}}{{end}}
+
+{{end}}
+`, license, codegenWarning)
@@ -0,0 +1,145 @@
+/**
+ * Copyright 2016 IBM Corp.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// selectTypes restricts the datatypes and services to generate. When services or datatypes are
+// named, only those are generated, along with the datatypes they depend on, transitively (base
+// types, property types, and method return and parameter types). Everything is generated
+// otherwise. Excluded services are never generated, although their datatype still is when another
+// generated type depends on it.
+func selectTypes(
+	sortedTypes []Type,
+	sortedServices []Type,
+	meta map[string]Type,
+	serviceNames []string,
+	typeNames []string,
+	excludedServices []string,
+) ([]Type, []Type, error) {
+
+	if len(serviceNames) == 0 && len(typeNames) == 0 && len(excludedServices) == 0 {
+		return sortedTypes, sortedServices, nil
+	}
+
+	// Methods of the services, including the ones inherited from their base service
+	methods := map[string]map[string]Method{}
+	for _, service := range sortedServices {
+		methods[service.Name] = service.Methods
+	}
+
+	for _, name := range append(serviceNames, excludedServices...) {
+		if _, ok := methods[name]; !ok {
+			return nil, nil, fmt.Errorf("Unknown service %s", name)
+		}
+	}
+
+	for _, name := range typeNames {
+		if _, ok := meta[name]; !ok {
+			return nil, nil, fmt.Errorf("Unknown datatype %s", name)
+		}
+	}
+
+	excluded := map[string]bool{}
+	for _, name := range excludedServices {
+		excluded[name] = true
+	}
+
+	wanted := map[string]bool{}
+	if len(serviceNames) == 0 && len(typeNames) == 0 {
+		for name := range methods {
+			wanted[name] = true
+		}
+	}
+
+	for _, name := range serviceNames {
+		wanted[name] = true
+	}
+
+	required := map[string]bool{}
+
+	// Only SoftLayer types are in the metadata, so the names of the built-in types are skipped
+	var require func(name string)
+	require = func(name string) {
+		t, ok := meta[name]
+		if !ok || required[name] {
+			return
+		}
+
+		required[name] = true
+
+		require(t.Base)
+		for _, p := range t.Properties {
+			require(p.Type)
+		}
+	}
+
+	if len(serviceNames) == 0 && len(typeNames) == 0 {
+		for name := range meta {
+			require(name)
+		}
+	}
+
+	for _, name := range typeNames {
+		require(name)
+	}
+
+	for name := range wanted {
+		if excluded[name] {
+			continue
+		}
+
+		require(name)
+		for _, m := range methods[name] {
+			require(m.Type)
+			for _, p := range m.Parameters {
+				require(p.Type)
+			}
+		}
+	}
+
+	types := make([]Type, 0, len(required))
+	for _, t := range sortedTypes {
+		if required[t.Name] {
+			types = append(types, t)
+		}
+	}
+
+	services := make([]Type, 0, len(wanted))
+	for _, service := range sortedServices {
+		if wanted[service.Name] && !excluded[service.Name] {
+			services = append(services, service)
+		}
+	}
+
+	return types, services, nil
+}
+
+// splitList splits a comma separated list of names, ignoring blanks
+func splitList(list string) []string {
+	names := []string{}
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
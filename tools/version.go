@@ -18,12 +18,13 @@ package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"go/format"
-	"html/template"
 	"os"
+	"path/filepath"
+	"text/template"
 
-	"flag"
 	"github.com/softlayer/softlayer-go/sl"
 )
 
@@ -43,10 +44,25 @@ type VersionInfo struct {
 }
 
 var Version = VersionInfo {
-	Major:  {{.Major}},
-	Minor:  {{.Minor}},
-	Patch:  {{.Patch}},
-	Pre:    "{{.Pre}}",
+	Major:  {{.Version.Major}},
+	Minor:  {{.Version.Minor}},
+	Patch:  {{.Version.Patch}},
+	Pre:    "{{.Version.Pre}}",
+}
+
+// APIMetadataInfo identifies the snapshot of the API metadata the services and datatypes were
+// generated from. Version is the version of the metadata API, Digest the SHA-256 digest of the
+// metadata, and Date the date it was retrieved.
+type APIMetadataInfo struct {
+	Version string
+	Digest  string
+	Date    string
+}
+
+var APIMetadata = APIMetadataInfo {
+	Version: "{{.Metadata.Version}}",
+	Digest:  "{{.Metadata.Digest}}",
+	Date:    "{{.Metadata.Date}}",
 }
 
 func (v VersionInfo) String() string {
@@ -59,6 +75,21 @@ func (v VersionInfo) String() string {
 	return result
 }
 
+// APIMetadataVersion returns the snapshot of the API metadata the services and datatypes were
+// generated from, e.g. "v3.1 sha256:4f9bf79e3a1c 2016-10-24", or "unknown" when it was not recorded
+func APIMetadataVersion() string {
+	if APIMetadata.Digest == "" {
+		return "unknown"
+	}
+
+	digest := APIMetadata.Digest
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+
+	return fmt.Sprintf("%%s sha256:%%s %%s", APIMetadata.Version, digest, APIMetadata.Date)
+}
+
 `, license, codegenWarning)
 
 const (
@@ -99,32 +130,42 @@ func version() {
 		bail(fmt.Errorf("Invalid value for bump: %s", bump))
 	}
 
-	writeVersionFile(v)
+	err := writeVersionFile(".", v, sl.APIMetadata)
+	if err != nil {
+		bail(err)
+	}
 
 	fmt.Println(v)
 }
 
-func writeVersionFile(v sl.VersionInfo) {
+// writeVersionFile writes the library version and the API metadata snapshot to sl/version.go
+func writeVersionFile(base string, v sl.VersionInfo, m sl.APIMetadataInfo) error {
+	data := struct {
+		Version  sl.VersionInfo
+		Metadata sl.APIMetadataInfo
+	}{v, m}
+
 	// Generate source
 	var buf bytes.Buffer
 	t := template.New("version")
-	template.Must(t.Parse(versionfile)).Execute(&buf, v)
-
-	//fmt.Println(string(buf.Bytes()))
+	err := template.Must(t.Parse(versionfile)).Execute(&buf, data)
+	if err != nil {
+		return fmt.Errorf("Error executing template: %s", err)
+	}
 
 	// format go file
 	pretty, err := format.Source(buf.Bytes())
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return fmt.Errorf("Error while formatting source: %s", err)
 	}
 
 	// write go file
-	f, err := os.Create("sl/version.go")
+	f, err := os.Create(filepath.Join(base, "sl", "version.go"))
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return fmt.Errorf("Error creating file: %s", err)
 	}
 	defer f.Close()
 	fmt.Fprintf(f, "%s", pretty)
+
+	return nil
 }